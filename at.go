@@ -0,0 +1,48 @@
+package absfs
+
+import "os"
+
+// Atter - is implemented by filesystems that can resolve names relative to an
+// open directory handle, like `openat(2)` and `fstatat(2)`. `dir` must be a
+// directory opened from the same filesystem. Absolute names ignore `dir`.
+//
+// Implementations must resolve `name` from the directory `dir` refers to,
+// not from its path, so a traversal that holds `dir` open keeps working on
+// the same directory even if it, or one of its parents, is renamed
+// concurrently.
+type Atter interface {
+	OpenFileAt(dir File, name string, flag int, perm os.FileMode) (File, error)
+	StatAt(dir File, name string) (os.FileInfo, error)
+}
+
+// OpenFileAt - opens `name` relative to the open directory `dir`. If `fsys`
+// implements `Atter` the call is delegated to it, with the guarantees
+// described there. Otherwise `name` is joined to `dir.Name()` and opened with
+// `OpenFile`; that fallback re-resolves the directory's path, so it is
+// neither faster than opening the joined path nor safe against a parent
+// being renamed after `dir` was opened.
+func OpenFileAt(fsys FileSystem, dir File, name string, flag int, perm os.FileMode) (File, error) {
+	if a, ok := fsys.(Atter); ok {
+		return a.OpenFileAt(dir, name, flag, perm)
+	}
+	return fsys.OpenFile(atPath(fsys, dir, name), flag, perm)
+}
+
+// StatAt - returns the `os.FileInfo` of `name` relative to the open directory
+// `dir`, delegating to `fsys` if it implements `Atter`, and otherwise falling
+// back to `Stat` of the joined path as `OpenFileAt` does.
+func StatAt(fsys FileSystem, dir File, name string) (os.FileInfo, error) {
+	if a, ok := fsys.(Atter); ok {
+		return a.StatAt(dir, name)
+	}
+	return fsys.Stat(atPath(fsys, dir, name))
+}
+
+// atPath - returns `name` joined to the path of `dir`, or `name` itself if it
+// is absolute.
+func atPath(fsys FileSystem, dir File, name string) string {
+	if IsAbs(name) {
+		return name
+	}
+	return Join(fsys, dir.Name(), name)
+}
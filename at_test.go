@@ -0,0 +1,66 @@
+package absfs
+
+import (
+	"os"
+	"testing"
+)
+
+// atFS - records calls to `Atter` methods.
+type atFS struct {
+	FileSystem
+	calls []string
+}
+
+func (fs *atFS) OpenFileAt(dir File, name string, flag int, perm os.FileMode) (File, error) {
+	fs.calls = append(fs.calls, "open "+name)
+	return fs.FileSystem.OpenFile(Join(fs, dir.Name(), name), flag, perm)
+}
+
+func (fs *atFS) StatAt(dir File, name string) (os.FileInfo, error) {
+	fs.calls = append(fs.calls, "stat "+name)
+	return fs.FileSystem.Stat(Join(fs, dir.Name(), name))
+}
+
+func TestOpenFileAt(t *testing.T) {
+	fsys := newTestFS()
+	if err := fsys.MkdirAll("/a/b", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, fsys, "/a/b/file", "data")
+	writeTestFile(t, fsys, "/top", "top")
+
+	dir, err := fsys.Open("/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dir.Close()
+
+	f, err := OpenFileAt(fsys, dir, "b/file", os.O_RDONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if f.Name() != "/a/b/file" {
+		t.Errorf("got %q, expected %q", f.Name(), "/a/b/file")
+	}
+	info, err := StatAt(fsys, dir, "b")
+	if err != nil || !info.IsDir() {
+		t.Errorf("StatAt: got %v, %v", info, err)
+	}
+	if info, err := StatAt(fsys, dir, "/top"); err != nil || info.Size() != 3 {
+		t.Errorf("absolute StatAt: got %v, %v", info, err)
+	}
+
+	afs := &atFS{FileSystem: fsys}
+	if _, err := StatAt(afs, dir, "b/file"); err != nil {
+		t.Fatal(err)
+	}
+	f, err = OpenFileAt(afs, dir, "b/file", os.O_RDONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if len(afs.calls) != 2 || afs.calls[0] != "stat b/file" || afs.calls[1] != "open b/file" {
+		t.Errorf("got calls %q, expected delegation", afs.calls)
+	}
+}
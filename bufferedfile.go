@@ -0,0 +1,126 @@
+package absfs
+
+import (
+	"bufio"
+	"io"
+	"os"
+)
+
+// Buffered - returns a File that coalesces small writes to `f` into a buffer
+// of `size` bytes, and reads from `f` through a `bufio.Reader` of the same
+// size. If `size` is <= 0 a default size is used.
+//
+// Buffered writes are flushed on `Close`, `Sync`, `Seek`, `Stat`, `Truncate`,
+// `ReadAt`, `WriteAt`, and before any `Read`. Any read-ahead is discarded, and
+// the offset of `f` restored, before writing or seeking so that the offset
+// seen by the caller is always correct. This is intended for backends where
+// each `Write` is expensive, such as those that make a network round-trip per
+// call.
+func Buffered(f File, size int) File {
+	if size <= 0 {
+		size = 4096
+	}
+	return &bufferedFile{
+		File: f,
+		w:    bufio.NewWriterSize(f, size),
+		r:    bufio.NewReaderSize(f, size),
+	}
+}
+
+type bufferedFile struct {
+	File
+	w *bufio.Writer
+	r *bufio.Reader
+}
+
+// discard - drops any read-ahead and moves the offset of the nested file back
+// to the position of the last byte returned by `Read`.
+func (f *bufferedFile) discard() error {
+	n := f.r.Buffered()
+	f.r.Reset(f.File)
+	if n == 0 {
+		return nil
+	}
+	_, err := f.File.Seek(-int64(n), io.SeekCurrent)
+	return err
+}
+
+func (f *bufferedFile) Read(b []byte) (int, error) {
+	if err := f.w.Flush(); err != nil {
+		return 0, err
+	}
+	return f.r.Read(b)
+}
+
+func (f *bufferedFile) Write(b []byte) (int, error) {
+	if err := f.discard(); err != nil {
+		return 0, err
+	}
+	return f.w.Write(b)
+}
+
+func (f *bufferedFile) WriteString(s string) (int, error) {
+	if err := f.discard(); err != nil {
+		return 0, err
+	}
+	return f.w.WriteString(s)
+}
+
+func (f *bufferedFile) Seek(offset int64, whence int) (int64, error) {
+	if err := f.w.Flush(); err != nil {
+		return 0, err
+	}
+	if err := f.discard(); err != nil {
+		return 0, err
+	}
+	return f.File.Seek(offset, whence)
+}
+
+func (f *bufferedFile) ReadAt(b []byte, off int64) (int, error) {
+	if err := f.w.Flush(); err != nil {
+		return 0, err
+	}
+	return f.File.ReadAt(b, off)
+}
+
+func (f *bufferedFile) WriteAt(b []byte, off int64) (int, error) {
+	if err := f.w.Flush(); err != nil {
+		return 0, err
+	}
+	if err := f.discard(); err != nil {
+		return 0, err
+	}
+	return f.File.WriteAt(b, off)
+}
+
+func (f *bufferedFile) Truncate(size int64) error {
+	if err := f.w.Flush(); err != nil {
+		return err
+	}
+	if err := f.discard(); err != nil {
+		return err
+	}
+	return f.File.Truncate(size)
+}
+
+func (f *bufferedFile) Stat() (os.FileInfo, error) {
+	if err := f.w.Flush(); err != nil {
+		return nil, err
+	}
+	return f.File.Stat()
+}
+
+func (f *bufferedFile) Sync() error {
+	if err := f.w.Flush(); err != nil {
+		return err
+	}
+	return f.File.Sync()
+}
+
+func (f *bufferedFile) Close() error {
+	err := f.w.Flush()
+	if cerr := f.File.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
@@ -0,0 +1,62 @@
+package absfs
+
+import (
+	"io"
+	"testing"
+)
+
+// countingFile - counts calls to `Write`.
+type countingFile struct {
+	File
+	writes int
+}
+
+func (f *countingFile) Write(b []byte) (int, error) {
+	f.writes++
+	return f.File.Write(b)
+}
+
+func TestBuffered(t *testing.T) {
+	fsys := newTestFS()
+	mf, err := fsys.Create("/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cf := &countingFile{File: mf}
+	f := Buffered(cf, 64)
+
+	for i := 0; i < 10; i++ {
+		if _, err := f.WriteString("abc"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if cf.writes != 0 {
+		t.Errorf("got %d writes before flush, expected 0", cf.writes)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if cf.writes != 1 {
+		t.Errorf("got %d writes after seek, expected 1", cf.writes)
+	}
+
+	buf := make([]byte, 3)
+	if _, err := io.ReadFull(f, buf); err != nil || string(buf) != "abc" {
+		t.Fatalf("read %q, %v", buf, err)
+	}
+
+	// writing after a read must start at the logical offset, not after the
+	// read-ahead.
+	if _, err := f.WriteString("XYZ"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "abcXYZ" + "abcabcabcabcabcabcabcabc"
+	if got := readTestFile(t, fsys, "/file"); got != expected {
+		t.Errorf("got %q, expected %q", got, expected)
+	}
+}
@@ -0,0 +1,253 @@
+package absfs
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// CacheOption - configures the FileSystem returned by `WithCache`.
+type CacheOption func(*cachefs)
+
+// CacheStatTTL - sets how long `Stat` results from the backing filesystem are
+// reused. The default is one second; zero disables caching of `Stat`.
+func CacheStatTTL(ttl time.Duration) CacheOption {
+	return func(c *cachefs) {
+		c.ttl = ttl
+	}
+}
+
+// WithCache - returns a FileSystem that serves reads of regular files from
+// `cache`, typically a fast local filesystem, in front of a slow `backing`
+// filesystem. On a miss the file is copied from `backing` into `cache`, with
+// its mode and modification time, and then read from `cache`. A cached copy
+// is used only while its size and modification time match those reported by
+// `backing`. Directories are always read from `backing`.
+//
+// Files opened for writing, and all other mutations, go to `backing`, and the
+// affected paths are invalidated in `cache` before the operation and again
+// when a written file is closed. `Remove`, `RemoveAll`, and `Rename`
+// invalidate whole subtrees. `Stat` results from `backing` are reused for a
+// short time, see `CacheStatTTL`.
+//
+// Changes made to `backing` other than through the returned FileSystem are
+// only noticed once the cached `Stat` result has expired, and then only if
+// they changed the size or modification time; until then stale data may be
+// served. The returned FileSystem is safe for concurrent use only if both
+// `backing` and `cache` are.
+func WithCache(backing FileSystem, cache FileSystem, opts ...CacheOption) FileSystem {
+	c := &cachefs{
+		FileSystem: backing,
+		cache:      cache,
+		ttl:        time.Second,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.stats = newStatCache(c.ttl)
+	return c
+}
+
+type cachefs struct {
+	FileSystem // backing
+	cache      FileSystem
+	ttl        time.Duration
+	stats      *statCache
+}
+
+type cachedStat struct {
+	info    os.FileInfo
+	expires time.Time
+}
+
+// statCache - holds successful `Stat` results by absolute path for `ttl`.
+// It is shared by `WithCache` and `WithStatCache`.
+type statCache struct {
+	ttl time.Duration
+
+	mu    sync.Mutex
+	stats map[string]cachedStat
+}
+
+func newStatCache(ttl time.Duration) *statCache {
+	return &statCache{ttl: ttl, stats: make(map[string]cachedStat)}
+}
+
+// get - returns the result cached for `key`, calling `stat` and caching its
+// result on a miss. Nothing is cached if `ttl` is zero or less.
+func (s *statCache) get(key string, stat func(string) (os.FileInfo, error)) (os.FileInfo, error) {
+	now := time.Now()
+	s.mu.Lock()
+	cs, ok := s.stats[key]
+	s.mu.Unlock()
+	if ok && now.Before(cs.expires) {
+		return cs.info, nil
+	}
+
+	info, err := stat(key)
+	if err != nil || s.ttl <= 0 {
+		return info, err
+	}
+	s.mu.Lock()
+	s.stats[key] = cachedStat{info, now.Add(s.ttl)}
+	s.mu.Unlock()
+	return info, nil
+}
+
+// forget - drops the result cached for `key` only.
+func (s *statCache) forget(key string) {
+	s.mu.Lock()
+	delete(s.stats, key)
+	s.mu.Unlock()
+}
+
+// invalidate - drops the results cached for `key` and everything below it,
+// using `sep` as the path separator.
+func (s *statCache) invalidate(sep uint8, key string) {
+	s.mu.Lock()
+	for k := range s.stats {
+		if IsDescendant(sep, key, k) {
+			delete(s.stats, k)
+		}
+	}
+	s.mu.Unlock()
+}
+
+// mutatePath - resolves `name` to an absolute path in `fsys`, passes it to
+// `invalidate`, calls `fn`, and passes it to `invalidate` again, so that
+// results cached while `fn` runs are dropped too.
+func mutatePath(fsys FileSystem, name string, invalidate func(key string), fn func() error) error {
+	key, err := Abs(fsys, name)
+	if err != nil {
+		return err
+	}
+	invalidate(key)
+	err = fn()
+	invalidate(key)
+	return err
+}
+
+// invalidate - drops the cached `Stat` results and the cached copies of
+// `key` and everything below it.
+func (c *cachefs) invalidate(key string) {
+	c.stats.invalidate(c.Separator(), key)
+	c.cache.RemoveAll(key)
+}
+
+func (c *cachefs) Stat(name string) (os.FileInfo, error) {
+	key, err := Abs(c.FileSystem, name)
+	if err != nil {
+		return nil, err
+	}
+	return c.stat(key)
+}
+
+func (c *cachefs) stat(key string) (os.FileInfo, error) {
+	return c.stats.get(key, c.FileSystem.Stat)
+}
+
+func (c *cachefs) Open(name string) (File, error) {
+	return c.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (c *cachefs) Create(name string) (File, error) {
+	return c.OpenFile(name, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0666)
+}
+
+func (c *cachefs) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	key, err := Abs(c.FileSystem, name)
+	if err != nil {
+		return nil, err
+	}
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) != 0 {
+		c.invalidate(key)
+		f, err := c.FileSystem.OpenFile(name, flag, perm)
+		if err != nil {
+			return f, err
+		}
+		return &cacheWriteFile{f, c, key}, nil
+	}
+
+	info, err := c.stat(key)
+	if err != nil || !info.Mode().IsRegular() {
+		return c.FileSystem.OpenFile(name, flag, perm)
+	}
+	if f := c.cached(key, info); f != nil {
+		return f, nil
+	}
+	if err := c.fill(key); err != nil {
+		return c.FileSystem.OpenFile(name, flag, perm)
+	}
+	return c.cache.OpenFile(key, os.O_RDONLY, 0)
+}
+
+// cached - returns the cached copy of `key` if it matches `info`.
+func (c *cachefs) cached(key string, info os.FileInfo) File {
+	f, err := c.cache.OpenFile(key, os.O_RDONLY, 0)
+	if err != nil {
+		return nil
+	}
+	cinfo, err := f.Stat()
+	if err != nil || cinfo.Size() != info.Size() || !cinfo.ModTime().Equal(info.ModTime()) {
+		f.Close()
+		return nil
+	}
+	return f
+}
+
+// fill - copies `key` from the backing filesystem to the cache.
+func (c *cachefs) fill(key string) error {
+	c.cache.RemoveAll(key)
+	if err := c.cache.MkdirAll(Dir(c.cache, key), 0755); err != nil {
+		return err
+	}
+	return copyFile(c.cache, key, c.FileSystem, key)
+}
+
+// cacheWriteFile - invalidates the cache when a written file is closed.
+type cacheWriteFile struct {
+	File
+	c   *cachefs
+	key string
+}
+
+func (f *cacheWriteFile) Close() error {
+	err := f.File.Close()
+	f.c.invalidate(f.key)
+	return err
+}
+
+// mutate - invalidates `name`, calls `fn`, and invalidates `name` again.
+func (c *cachefs) mutate(name string, fn func() error) error {
+	return mutatePath(c.FileSystem, name, c.invalidate, fn)
+}
+
+func (c *cachefs) Remove(name string) error {
+	return c.mutate(name, func() error { return c.FileSystem.Remove(name) })
+}
+
+func (c *cachefs) RemoveAll(name string) error {
+	return c.mutate(name, func() error { return c.FileSystem.RemoveAll(name) })
+}
+
+func (c *cachefs) Truncate(name string, size int64) error {
+	return c.mutate(name, func() error { return c.FileSystem.Truncate(name, size) })
+}
+
+func (c *cachefs) Chmod(name string, mode os.FileMode) error {
+	return c.mutate(name, func() error { return c.FileSystem.Chmod(name, mode) })
+}
+
+func (c *cachefs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	return c.mutate(name, func() error { return c.FileSystem.Chtimes(name, atime, mtime) })
+}
+
+func (c *cachefs) Chown(name string, uid, gid int) error {
+	return c.mutate(name, func() error { return c.FileSystem.Chown(name, uid, gid) })
+}
+
+func (c *cachefs) Rename(oldpath, newpath string) error {
+	return c.mutate(oldpath, func() error {
+		return c.mutate(newpath, func() error { return c.FileSystem.Rename(oldpath, newpath) })
+	})
+}
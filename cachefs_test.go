@@ -0,0 +1,112 @@
+package absfs
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+// openCountFS - counts the files opened for reading.
+type openCountFS struct {
+	FileSystem
+	opens int
+}
+
+func (fs *openCountFS) Open(name string) (File, error) {
+	return fs.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (fs *openCountFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR) == 0 {
+		fs.opens++
+	}
+	return fs.FileSystem.OpenFile(name, flag, perm)
+}
+
+func TestWithCache(t *testing.T) {
+	backing := &openCountFS{FileSystem: newTestFS()}
+	cache := newTestFS()
+	if err := backing.MkdirAll("/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, backing, "/dir/file", "v1")
+	fsys := WithCache(backing, cache)
+
+	for i := 0; i < 3; i++ {
+		if got := readTestFile(t, fsys, "/dir/file"); got != "v1" {
+			t.Fatalf("read %d: got %q", i, got)
+		}
+	}
+	if backing.opens != 1 {
+		t.Errorf("backing opened %d times, expected 1", backing.opens)
+	}
+	if got := readTestFile(t, cache, "/dir/file"); got != "v1" {
+		t.Errorf("cache holds %q", got)
+	}
+
+	writeTestFile(t, fsys, "/dir/file", "version 2")
+	if got := readTestFile(t, fsys, "/dir/file"); got != "version 2" {
+		t.Errorf("after write got %q", got)
+	}
+
+	if err := fsys.Rename("/dir", "/moved"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cache.Stat("/dir/file"); err == nil {
+		t.Error("Rename did not invalidate the cached copy")
+	}
+	if got := readTestFile(t, fsys, "/moved/file"); got != "version 2" {
+		t.Errorf("after rename got %q", got)
+	}
+
+	if err := fsys.Remove("/moved/file"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fsys.Open("/moved/file"); err == nil {
+		t.Error("expected the removed file to be gone")
+	}
+}
+
+func TestWithCacheStatTTL(t *testing.T) {
+	backing := newTestFS()
+	writeTestFile(t, backing, "/file", "v1")
+	fsys := WithCache(backing, newTestFS(), CacheStatTTL(time.Hour))
+
+	if _, err := fsys.Stat("/file"); err != nil {
+		t.Fatal(err)
+	}
+	// an external change is not seen until the cached Stat expires
+	writeTestFile(t, backing, "/file", "external")
+	if info, _ := fsys.Stat("/file"); info.Size() != 2 {
+		t.Errorf("got size %d, expected the cached size 2", info.Size())
+	}
+
+	fsys = WithCache(backing, newTestFS(), CacheStatTTL(0))
+	if got := readTestFile(t, fsys, "/file"); got != "external" {
+		t.Fatalf("got %q", got)
+	}
+	writeTestFile(t, backing, "/file", "external 2")
+	if got := readTestFile(t, fsys, "/file"); got != "external 2" {
+		t.Errorf("got %q, expected the changed size to invalidate the copy", got)
+	}
+}
+
+func TestWithCacheInvalidateRoot(t *testing.T) {
+	backing, cache := newTestFS(), newTestFS()
+	writeTestFile(t, backing, "/file", "data")
+	fsys := WithCache(backing, cache, CacheStatTTL(time.Hour))
+	if got := readTestFile(t, fsys, "/file"); got != "data" {
+		t.Fatalf("got %q, expected %q", got, "data")
+	}
+
+	fsys.RemoveAll("/")
+	if _, err := backing.Stat("/file"); !os.IsNotExist(err) {
+		t.Fatalf("backing: got %v, expected the file to be removed", err)
+	}
+	if _, err := fsys.Stat("/file"); !os.IsNotExist(err) {
+		t.Errorf("Stat: got %v, expected a stale result to be dropped", err)
+	}
+	if _, err := cache.Stat("/file"); !os.IsNotExist(err) {
+		t.Errorf("cache: got %v, expected the cached copy to be removed", err)
+	}
+}
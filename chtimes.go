@@ -0,0 +1,45 @@
+package absfs
+
+import (
+	"os"
+	"time"
+)
+
+// ChtimesPartial - is like `Chtimes` but a nil `atime` or `mtime` leaves that
+// time unchanged, like `UTIME_OMIT` with `utimensat(2)`. The preserved value
+// is read with `Stat` and passed to `Chtimes` along with the new one. If both
+// are nil nothing is done.
+//
+// The access time is read from `AccessTime() time.Time` when the FileInfo
+// has that method, and otherwise from its `Sys` value on platforms where the
+// layout is known. When it can't be found the modification time is used in
+// its place.
+func ChtimesPartial(fsys FileSystem, name string, atime, mtime *time.Time) error {
+	if atime == nil && mtime == nil {
+		return nil
+	}
+	if atime != nil && mtime != nil {
+		return fsys.Chtimes(name, *atime, *mtime)
+	}
+	info, err := fsys.Stat(name)
+	if err != nil {
+		return err
+	}
+	if atime == nil {
+		at := info.ModTime()
+		if t, ok := accessTime(info); ok {
+			at = t
+		}
+		return fsys.Chtimes(name, at, *mtime)
+	}
+	return fsys.Chtimes(name, *atime, info.ModTime())
+}
+
+// accessTime - returns the access time recorded in `info`, if it can be
+// found.
+func accessTime(info os.FileInfo) (time.Time, bool) {
+	if a, ok := info.(interface{ AccessTime() time.Time }); ok {
+		return a.AccessTime(), true
+	}
+	return sysAccessTime(info.Sys())
+}
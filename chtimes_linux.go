@@ -0,0 +1,15 @@
+package absfs
+
+import (
+	"syscall"
+	"time"
+)
+
+// sysAccessTime - returns the access time from a `*syscall.Stat_t`.
+func sysAccessTime(sys interface{}) (time.Time, bool) {
+	st, ok := sys.(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}, false
+	}
+	return time.Unix(st.Atim.Unix()), true
+}
@@ -0,0 +1,10 @@
+//go:build !linux
+
+package absfs
+
+import "time"
+
+// sysAccessTime - reports that the access time is unknown on this platform.
+func sysAccessTime(sys interface{}) (time.Time, bool) {
+	return time.Time{}, false
+}
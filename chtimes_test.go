@@ -0,0 +1,76 @@
+package absfs
+
+import (
+	"testing"
+	"time"
+)
+
+func TestChtimesPartial(t *testing.T) {
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/file", "data")
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+	t2 := t1.Add(time.Hour)
+	if err := fsys.Chtimes("/file", t0, t1); err != nil {
+		t.Fatal(err)
+	}
+
+	times := func() (atime, mtime time.Time) {
+		t.Helper()
+		info, err := fsys.Stat("/file")
+		if err != nil {
+			t.Fatal(err)
+		}
+		atime, ok := accessTime(info)
+		if !ok {
+			t.Fatal("access time unavailable")
+		}
+		return atime, info.ModTime()
+	}
+
+	// nil atime preserves the access time
+	if err := ChtimesPartial(fsys, "/file", nil, &t2); err != nil {
+		t.Fatal(err)
+	}
+	if atime, mtime := times(); !atime.Equal(t0) || !mtime.Equal(t2) {
+		t.Errorf("got %s, %s, expected %s, %s", atime, mtime, t0, t2)
+	}
+
+	// nil mtime preserves the modification time
+	if err := ChtimesPartial(fsys, "/file", &t1, nil); err != nil {
+		t.Fatal(err)
+	}
+	if atime, mtime := times(); !atime.Equal(t1) || !mtime.Equal(t2) {
+		t.Errorf("got %s, %s, expected %s, %s", atime, mtime, t1, t2)
+	}
+
+	if err := ChtimesPartial(fsys, "/file", nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := ChtimesPartial(fsys, "/missing", nil, &t2); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestChtimesPartialOSFiler(t *testing.T) {
+	fsys := ExtendFiler(OSFiler(t.TempDir()))
+	writeTestFile(t, fsys, "/file", "data")
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+	if err := fsys.Chtimes("/file", t0, t0); err != nil {
+		t.Fatal(err)
+	}
+	if err := ChtimesPartial(fsys, "/file", nil, &t1); err != nil {
+		t.Fatal(err)
+	}
+	info, err := fsys.Stat("/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(t1) {
+		t.Errorf("got mtime %s, expected %s", info.ModTime(), t1)
+	}
+	if atime, ok := accessTime(info); ok && !atime.Equal(t0) {
+		t.Errorf("got atime %s, expected %s", atime, t0)
+	}
+}
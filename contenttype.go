@@ -0,0 +1,33 @@
+package absfs
+
+import (
+	"io"
+	"net/http"
+)
+
+// sniffLen - is the number of bytes `http.DetectContentType` considers.
+const sniffLen = 512
+
+// DetectContentType - returns the MIME type of the file `name` as determined
+// by `http.DetectContentType`. Only the first 512 bytes are read, with
+// `ReadAt` on a new handle, so the offsets of other open handles are not
+// affected. Files shorter than 512 bytes are sniffed from whatever they
+// contain, and empty files are reported as "application/octet-stream", as
+// the standard library does.
+func DetectContentType(fsys FileSystem, name string) (string, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	buf := make([]byte, sniffLen)
+	n, err := readFullAt(f, buf, 0)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	if n == 0 {
+		return "application/octet-stream", nil
+	}
+	return http.DetectContentType(buf[:n]), nil
+}
@@ -0,0 +1,39 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDetectContentType(t *testing.T) {
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/empty", "")
+	writeTestFile(t, fsys, "/small.txt", "hello")
+	writeTestFile(t, fsys, "/page.html", "<!DOCTYPE html><html><body>"+strings.Repeat("x", 1024)+"</body></html>")
+	writeTestFile(t, fsys, "/image.png", "\x89PNG\r\n\x1a\n")
+
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"/empty", "application/octet-stream"},
+		{"/small.txt", "text/plain; charset=utf-8"},
+		{"/page.html", "text/html; charset=utf-8"},
+		{"/image.png", "image/png"},
+	}
+	for _, tt := range tests {
+		got, err := DetectContentType(fsys, tt.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.expected {
+			t.Errorf("%s: got %q, expected %q", tt.name, got, tt.expected)
+		}
+	}
+
+	if _, err := DetectContentType(fsys, "/missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrNotExist)
+	}
+}
@@ -0,0 +1,271 @@
+package absfs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"sync"
+	"syscall"
+)
+
+// CopyIfNewer - copies `srcName` in `src` to `dstName` in `dst` only when the
+// destination is missing, its modification time is older than the source's,
+// or the modification times are equal but the sizes differ. This is the
+// mtime and size heuristic used by rsync-style incremental synchronization.
+// The copy preserves the source's mode and modification time so that a
+// subsequent call is a no-op. It returns whether a copy occurred.
+func CopyIfNewer(dst FileSystem, dstName string, src FileSystem, srcName string) (copied bool, err error) {
+	srcInfo, err := src.Stat(srcName)
+	if err != nil {
+		return false, err
+	}
+	dstInfo, err := dst.Stat(dstName)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+	case err != nil:
+		return false, err
+	case dstInfo.ModTime().Before(srcInfo.ModTime()):
+	case dstInfo.ModTime().Equal(srcInfo.ModTime()) && dstInfo.Size() != srcInfo.Size():
+	default:
+		return false, nil
+	}
+
+	if err := copyFile(dst, dstName, src, srcName); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// CopyFile - copies the regular file `src` to `dst` within `fsys`, then
+// applies the mode and modification time of `src` to `dst`. If `dst` already
+// exists it is truncated. If `src` is a directory the error is an
+// `*os.PathError` wrapping `ErrIsDirectory`. If the copy fails part way the
+// partially written `dst` is removed. If `dst` and `src` resolve to the same
+// path nothing is copied, since truncating `dst` would erase `src`, and the
+// error is an `*os.LinkError` wrapping `syscall.EINVAL`.
+func CopyFile(fsys FileSystem, dst, src string) error {
+	srcAbs, err := Abs(fsys, src)
+	if err != nil {
+		return err
+	}
+	dstAbs, err := Abs(fsys, dst)
+	if err != nil {
+		return err
+	}
+	if srcAbs == dstAbs {
+		return &os.LinkError{Op: "copy", Old: src, New: dst, Err: syscall.EINVAL}
+	}
+	return copyFile(fsys, dst, fsys, src)
+}
+
+// CopyAll - recursively copies the tree rooted at `srcRoot` in `src` to
+// `dstRoot` in `dst`. Directories are recreated with `MkdirAll`, regular files
+// are copied, and the mode and modification time of every entry is
+// preserved. Directory metadata is applied after the directory's contents
+// have been copied, so read-only directories can be copied too.
+//
+// Symbolic links are replicated with `Readlink` and `Symlink` when both `src`
+// and `dst` implement `SymLinker`; otherwise the target of the link is
+// copied. If `dst` and `src` are the same instance and the roots resolve to
+// the same path, `CopyAll` does nothing; when `dstRoot` lies within
+// `srcRoot` the copy is not walked again.
+func CopyAll(dst FileSystem, dstRoot string, src FileSystem, srcRoot string) error {
+	same := sameFS(dst, src)
+	if same {
+		srcAbs, err := Abs(src, srcRoot)
+		if err != nil {
+			return err
+		}
+		dstAbs, err := Abs(dst, dstRoot)
+		if err != nil {
+			return err
+		}
+		if srcAbs == dstAbs {
+			return nil
+		}
+		srcRoot, dstRoot = srcAbs, dstAbs
+	}
+
+	srcLinker, srcLinks := src.(SymLinker)
+	dstLinker, dstLinks := dst.(SymLinker)
+
+	type dirInfo struct {
+		name string
+		info os.FileInfo
+	}
+	var dirs []dirInfo
+
+	err := Walk(src, srcRoot, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if same && info.IsDir() && path == dstRoot {
+			return SkipDir
+		}
+		rel, err := Rel(src, srcRoot, path)
+		if err != nil {
+			return err
+		}
+		target := Join(dst, dstRoot, rel)
+
+		switch {
+		case info.IsDir():
+			if err := dst.MkdirAll(target, 0700); err != nil {
+				return err
+			}
+			dirs = append(dirs, dirInfo{target, info})
+			return nil
+		case info.Mode()&os.ModeSymlink != 0 && srcLinks && dstLinks:
+			link, err := srcLinker.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return dstLinker.Symlink(link, target)
+		}
+		return copyFile(dst, target, src, path)
+	})
+	if err != nil {
+		return err
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		d := dirs[i]
+		if err := dst.Chmod(d.name, d.info.Mode()&^os.ModeType); err != nil {
+			return err
+		}
+		if err := dst.Chtimes(d.name, d.info.ModTime(), d.info.ModTime()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Move - moves `srcPath` in `src` to `dstPath` in `dst`. When `dst` and `src`
+// are the same instance `Rename` is used. Otherwise, or if `Rename` fails with
+// `syscall.EXDEV`, the tree is copied with `CopyAll` and then the source is
+// removed with `RemoveAll`; directories are moved recursively.
+//
+// The copy and remove path is not atomic: readers may observe both copies
+// while the move is in progress, and if removing the source fails both
+// copies remain. The source is only removed once the copy has fully
+// succeeded, so a failed copy leaves the source untouched; any partial copy
+// at a previously missing `dstPath` is removed.
+func Move(dst FileSystem, dstPath string, src FileSystem, srcPath string) error {
+	if sameFS(dst, src) {
+		err := src.Rename(srcPath, dstPath)
+		if !errors.Is(err, syscall.EXDEV) {
+			return err
+		}
+	}
+
+	existed, err := Exists(dst, dstPath)
+	if err != nil {
+		return err
+	}
+	if err := CopyAll(dst, dstPath, src, srcPath); err != nil {
+		if !existed {
+			dst.RemoveAll(dstPath)
+		}
+		return err
+	}
+	return src.RemoveAll(srcPath)
+}
+
+// sameFS - reports whether `a` and `b` are the same FileSystem instance.
+// Unlike `a == b` it does not panic when the dynamic type is not comparable,
+// such as a struct value holding a slice or map; such values are never the
+// same instance.
+func sameFS(a, b FileSystem) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if reflect.TypeOf(a) != reflect.TypeOf(b) || !reflect.ValueOf(a).Comparable() {
+		return false
+	}
+	return a == b
+}
+
+// RenameMkdirAll - is like `fsys.Rename` but first creates any missing parent
+// directories of `newpath` with `MkdirAll` and mode 0755. Parents that already
+// exist are left unchanged. An error from `Rename` is returned as is.
+func RenameMkdirAll(fsys FileSystem, oldpath, newpath string) error {
+	if err := fsys.MkdirAll(Dir(fsys, newpath), 0755); err != nil {
+		return err
+	}
+	return fsys.Rename(oldpath, newpath)
+}
+
+// copyBufPool - holds the buffers used to stream file contents.
+var copyBufPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 32*1024)
+		return &b
+	},
+}
+
+// Copy - copies from `src` to `dst` until either EOF is reached on `src` or an
+// error occurs, and returns the number of bytes copied. It behaves like
+// `io.Copy`: if `src` implements `io.WriterTo` or `dst` implements
+// `io.ReaderFrom` the copy is delegated to them. Otherwise the data is
+// streamed through a 32KB buffer drawn from a package-level pool, so that
+// many concurrent copies do not each allocate their own buffer.
+func Copy(dst File, src File) (int64, error) {
+	if wt, ok := src.(io.WriterTo); ok {
+		return wt.WriteTo(dst)
+	}
+	if rf, ok := dst.(io.ReaderFrom); ok {
+		return rf.ReadFrom(src)
+	}
+	buf := copyBufPool.Get().(*[]byte)
+	defer copyBufPool.Put(buf)
+	return io.CopyBuffer(onlyWriter{dst}, onlyReader{src}, *buf)
+}
+
+// onlyWriter and onlyReader - hide any `io.ReaderFrom` and `io.WriterTo`
+// implementations so that `io.CopyBuffer` uses the supplied buffer.
+type onlyWriter struct {
+	io.Writer
+}
+
+type onlyReader struct {
+	io.Reader
+}
+
+// copyFile - copies the contents, mode, and modification time of the regular
+// file `srcName` in `src` to `dstName` in `dst`, truncating `dstName` if it
+// exists. A partially written destination is removed on error.
+func copyFile(dst FileSystem, dstName string, src FileSystem, srcName string) (err error) {
+	s, err := src.Open(srcName)
+	if err != nil {
+		return err
+	}
+	defer s.Close()
+
+	info, err := s.Stat()
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return &os.PathError{Op: "copy", Path: srcName, Err: ErrIsDirectory}
+	}
+
+	mode := info.Mode() &^ os.ModeType
+	d, err := dst.OpenFile(dstName, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	_, err = Copy(d, s)
+	if cerr := d.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		dst.Remove(dstName)
+		return err
+	}
+
+	if err := dst.Chmod(dstName, mode); err != nil {
+		return err
+	}
+	return dst.Chtimes(dstName, info.ModTime(), info.ModTime())
+}
@@ -0,0 +1,352 @@
+package absfs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestCopyIfNewer(t *testing.T) {
+	src, dst := newTestFS(), newTestFS()
+	writeTestFile(t, src, "/file", "version 1")
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := src.Chtimes("/file", t0, t0); err != nil {
+		t.Fatal(err)
+	}
+
+	// destination missing
+	copied, err := CopyIfNewer(dst, "/copy", src, "/file")
+	if err != nil || !copied {
+		t.Fatalf("missing destination: got %t, %v", copied, err)
+	}
+	if got := readTestFile(t, dst, "/copy"); got != "version 1" {
+		t.Errorf("got %q, expected %q", got, "version 1")
+	}
+
+	// destination equal
+	copied, err = CopyIfNewer(dst, "/copy", src, "/file")
+	if err != nil || copied {
+		t.Errorf("equal destination: got %t, %v", copied, err)
+	}
+
+	// destination newer
+	writeTestFile(t, src, "/file", "version 2")
+	if err := src.Chtimes("/file", t0, t0); err != nil {
+		t.Fatal(err)
+	}
+	t1 := t0.Add(time.Hour)
+	if err := dst.Chtimes("/copy", t1, t1); err != nil {
+		t.Fatal(err)
+	}
+	copied, err = CopyIfNewer(dst, "/copy", src, "/file")
+	if err != nil || copied {
+		t.Errorf("newer destination: got %t, %v", copied, err)
+	}
+
+	// source newer
+	t2 := t1.Add(time.Hour)
+	if err := src.Chtimes("/file", t2, t2); err != nil {
+		t.Fatal(err)
+	}
+	copied, err = CopyIfNewer(dst, "/copy", src, "/file")
+	if err != nil || !copied {
+		t.Errorf("newer source: got %t, %v", copied, err)
+	}
+	if got := readTestFile(t, dst, "/copy"); got != "version 2" {
+		t.Errorf("got %q, expected %q", got, "version 2")
+	}
+}
+
+// failWriteFS - returns files whose writes fail.
+type failWriteFS struct {
+	FileSystem
+}
+
+type failWriteFile struct {
+	File
+}
+
+func (f *failWriteFile) Write(p []byte) (int, error) {
+	return 0, errors.New("write failed")
+}
+
+func (fs *failWriteFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	f, err := fs.FileSystem.OpenFile(name, flag, perm)
+	if err != nil {
+		return f, err
+	}
+	return &failWriteFile{f}, nil
+}
+
+func TestCopyFile(t *testing.T) {
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/src", "contents")
+	mtime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := fsys.Chmod("/src", 0640); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Chtimes("/src", mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, fsys, "/dst", "a much longer existing file")
+
+	if err := CopyFile(fsys, "/dst", "/src"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, fsys, "/dst"); got != "contents" {
+		t.Errorf("got %q, expected %q", got, "contents")
+	}
+	info, err := fsys.Stat("/dst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode() != 0640 || !info.ModTime().Equal(mtime) {
+		t.Errorf("got mode %s, mtime %s", info.Mode(), info.ModTime())
+	}
+
+	if err := fsys.Mkdir("/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := CopyFile(fsys, "/dst2", "/dir"); !errors.Is(err, ErrIsDirectory) {
+		t.Errorf("got %v, expected %v", err, ErrIsDirectory)
+	}
+
+	if err := CopyFile(&failWriteFS{fsys}, "/partial", "/src"); err == nil {
+		t.Error("expected a write error")
+	}
+	if _, err := fsys.Stat("/partial"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("partial destination was not removed: %v", err)
+	}
+}
+
+func TestCopyFileOntoItself(t *testing.T) {
+	fsys := newTestFS()
+	if err := fsys.Mkdir("/x", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, fsys, "/a", "contents")
+	if err := fsys.Chdir("/x"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, dst := range []string{"/a", "/x/../a", "../a"} {
+		err := CopyFile(fsys, dst, "/a")
+		var lerr *os.LinkError
+		if !errors.As(err, &lerr) || !errors.Is(err, syscall.EINVAL) {
+			t.Errorf("%s: got %v, expected an *os.LinkError wrapping %v", dst, err, syscall.EINVAL)
+		}
+		if got := readTestFile(t, fsys, "/a"); got != "contents" {
+			t.Fatalf("%s: source changed to %q", dst, got)
+		}
+	}
+}
+
+func TestCopyAll(t *testing.T) {
+	src, dst := newTestSymlinkFS(), newTestSymlinkFS()
+	if err := src.MkdirAll("/src/a/b", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, src, "/src/a/file", "file")
+	writeTestFile(t, src, "/src/a/b/nested", "nested")
+	if err := src.Symlink("file", "/src/a/link"); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := src.Chmod("/src/a/b", 0500); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"/src/a/file", "/src/a/b"} {
+		if err := src.Chtimes(name, mtime, mtime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := dst.Mkdir("/dst", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := CopyAll(dst, "/dst/copy", src, "/src"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readTestFile(t, dst, "/dst/copy/a/b/nested"); got != "nested" {
+		t.Errorf("got %q, expected %q", got, "nested")
+	}
+	info, err := dst.Stat("/dst/copy/a/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode() != os.ModeDir|0500 || !info.ModTime().Equal(mtime) {
+		t.Errorf("directory: got mode %s, mtime %s", info.Mode(), info.ModTime())
+	}
+	info, err = dst.Stat("/dst/copy/a/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.ModTime().Equal(mtime) {
+		t.Errorf("file: got mtime %s", info.ModTime())
+	}
+	link, err := dst.Readlink("/dst/copy/a/link")
+	if err != nil || link != "file" {
+		t.Errorf("got link %q, %v", link, err)
+	}
+
+	// copying a tree onto itself is a no-op
+	if err := CopyAll(src, "/src/a/../a", src, "/src/a"); err != nil {
+		t.Fatal(err)
+	}
+
+	// copying a tree into itself does not walk the copy
+	if err := CopyAll(dst, "/dst/copy/a/inner", dst, "/dst/copy/a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := dst.Stat("/dst/copy/a/inner/b/nested"); err != nil {
+		t.Error(err)
+	}
+	if _, err := dst.Stat("/dst/copy/a/inner/inner"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("copy was walked: %v", err)
+	}
+}
+
+func TestMove(t *testing.T) {
+	src, dst := newTestFS(), newTestFS()
+	if err := src.MkdirAll("/dir/sub", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, src, "/dir/sub/file", "contents")
+	writeTestFile(t, src, "/single", "single")
+
+	if err := Move(dst, "/moved", src, "/dir"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, dst, "/moved/sub/file"); got != "contents" {
+		t.Errorf("got %q, expected %q", got, "contents")
+	}
+	if _, err := src.Stat("/dir"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("source was not removed: %v", err)
+	}
+
+	if err := Move(src, "/renamed", src, "/single"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, src, "/renamed"); got != "single" {
+		t.Errorf("got %q, expected %q", got, "single")
+	}
+
+	// a failed copy leaves the source untouched
+	if err := Move(&failWriteFS{dst}, "/failed", src, "/renamed"); err == nil {
+		t.Fatal("expected a write error")
+	}
+	if got := readTestFile(t, src, "/renamed"); got != "single" {
+		t.Errorf("source modified: got %q", got)
+	}
+	if _, err := dst.Stat("/failed"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("partial copy was not removed: %v", err)
+	}
+}
+
+// sliceFS - is a FileSystem whose dynamic type is not comparable.
+type sliceFS struct {
+	FileSystem
+	tags []string
+}
+
+func TestMoveNotComparable(t *testing.T) {
+	src, dst := sliceFS{FileSystem: newTestFS()}, sliceFS{FileSystem: newTestFS()}
+	writeTestFile(t, src, "/file", "contents")
+
+	if err := CopyAll(dst, "/copy", src, "/file"); err != nil {
+		t.Fatal(err)
+	}
+	if err := Move(dst, "/moved", src, "/file"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, dst, "/moved"); got != "contents" {
+		t.Errorf("got %q, expected %q", got, "contents")
+	}
+	if _, err := src.Stat("/file"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("source was not removed: %v", err)
+	}
+}
+
+func TestRenameMkdirAll(t *testing.T) {
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/file", "data")
+	if err := fsys.Mkdir("/a", 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RenameMkdirAll(fsys, "/file", "/a/b/c/file"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, fsys, "/a/b/c/file"); got != "data" {
+		t.Errorf("got %q, expected %q", got, "data")
+	}
+	info, err := fsys.Stat("/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0700 {
+		t.Errorf("existing parent mode changed to %s", info.Mode())
+	}
+
+	err = RenameMkdirAll(fsys, "/missing", "/x/y/file")
+	var lerr *os.LinkError
+	if !errors.As(err, &lerr) || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %#v, expected an *os.LinkError wrapping %v", err, os.ErrNotExist)
+	}
+}
+
+func TestCopy(t *testing.T) {
+	fsys := newTestFS()
+	data := strings.Repeat("0123456789", 10000)
+	writeTestFile(t, fsys, "/src", data)
+
+	src, err := fsys.Open("/src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer src.Close()
+	dst, err := fsys.Create("/dst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err := Copy(dst, src)
+	dst.Close()
+	if err != nil || n != int64(len(data)) {
+		t.Fatalf("copied %d bytes, %v, expected %d", n, err, len(data))
+	}
+	if got := readTestFile(t, fsys, "/dst"); got != data {
+		t.Errorf("copied %d bytes of content, expected %d", len(got), len(data))
+	}
+}
+
+func benchmarkCopy(b *testing.B, copy func(dst, src File) (int64, error)) {
+	data := []byte(strings.Repeat("x", 64*1024))
+	b.SetBytes(int64(len(data)))
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		fsys := newTestFS()
+		src, _ := fsys.Create("/src")
+		src.Write(data)
+		dst, _ := fsys.Create("/dst")
+		for pb.Next() {
+			src.Seek(0, io.SeekStart)
+			dst.Seek(0, io.SeekStart)
+			if _, err := copy(dst, src); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func BenchmarkCopy(b *testing.B) {
+	benchmarkCopy(b, Copy)
+}
+
+func BenchmarkIOCopy(b *testing.B) {
+	benchmarkCopy(b, func(dst, src File) (int64, error) { return io.Copy(dst, src) })
+}
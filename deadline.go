@@ -0,0 +1,115 @@
+package absfs
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// Deadliner - is implemented by File handles that support read and write
+// deadlines, with the semantics of `net.Conn`: an operation that has not
+// completed by the deadline fails with an error wrapping
+// `os.ErrDeadlineExceeded`, and a zero time means no deadline.
+type Deadliner interface {
+	SetDeadline(t time.Time) error
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
+}
+
+// WithDeadline - returns a File that implements `Deadliner`, so callers can
+// bound how long a request may hang on a slow or network backed handle. If
+// `f` already implements `Deadliner`, such as a handle wrapping a `net.Conn`,
+// it is returned unchanged and its native deadlines are used.
+//
+// Otherwise the returned File runs each `Read`, `ReadAt`, `Write`, `WriteAt`,
+// and `WriteString` in a new goroutine, while a deadline is set, and stops
+// waiting for it when the deadline passes, returning an `*os.PathError`
+// wrapping `os.ErrDeadlineExceeded`. Go can't interrupt a blocked call, so an
+// operation that misses its deadline keeps running in the background and may
+// still take effect later, and after a read misses its deadline the
+// background read may still write into the caller's buffer. Each operation
+// uses the deadline in force when it starts; changing a deadline does not
+// affect operations already in progress. Operations are run directly, with
+// no goroutine, while no deadline is set.
+func WithDeadline(f File) File {
+	if _, ok := f.(Deadliner); ok {
+		return f
+	}
+	return &deadlineFile{File: f}
+}
+
+type deadlineFile struct {
+	File
+
+	mu    sync.Mutex
+	read  time.Time
+	write time.Time
+}
+
+func (f *deadlineFile) SetDeadline(t time.Time) error {
+	f.mu.Lock()
+	f.read, f.write = t, t
+	f.mu.Unlock()
+	return nil
+}
+
+func (f *deadlineFile) SetReadDeadline(t time.Time) error {
+	f.mu.Lock()
+	f.read = t
+	f.mu.Unlock()
+	return nil
+}
+
+func (f *deadlineFile) SetWriteDeadline(t time.Time) error {
+	f.mu.Lock()
+	f.write = t
+	f.mu.Unlock()
+	return nil
+}
+
+// rw - runs a read or write, giving up at the read or write deadline.
+func (f *deadlineFile) rw(op string, write bool, fn func() (int, error)) (int, error) {
+	f.mu.Lock()
+	deadline := f.read
+	if write {
+		deadline = f.write
+	}
+	f.mu.Unlock()
+	if deadline.IsZero() {
+		return fn()
+	}
+
+	d := time.Until(deadline)
+	if d <= 0 {
+		return 0, &os.PathError{Op: op, Path: f.File.Name(), Err: os.ErrDeadlineExceeded}
+	}
+	var n int
+	err := withTimeout(d, func() (err error) {
+		n, err = fn()
+		return err
+	})
+	if err == os.ErrDeadlineExceeded {
+		return 0, &os.PathError{Op: op, Path: f.File.Name(), Err: err}
+	}
+	return n, err
+}
+
+func (f *deadlineFile) Read(b []byte) (int, error) {
+	return f.rw("read", false, func() (int, error) { return f.File.Read(b) })
+}
+
+func (f *deadlineFile) ReadAt(b []byte, off int64) (int, error) {
+	return f.rw("read", false, func() (int, error) { return f.File.ReadAt(b, off) })
+}
+
+func (f *deadlineFile) Write(b []byte) (int, error) {
+	return f.rw("write", true, func() (int, error) { return f.File.Write(b) })
+}
+
+func (f *deadlineFile) WriteAt(b []byte, off int64) (int, error) {
+	return f.rw("write", true, func() (int, error) { return f.File.WriteAt(b, off) })
+}
+
+func (f *deadlineFile) WriteString(s string) (int, error) {
+	return f.rw("write", true, func() (int, error) { return f.File.WriteString(s) })
+}
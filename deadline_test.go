@@ -0,0 +1,67 @@
+package absfs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+	"time"
+)
+
+// nativeDeadlineFile - records deadlines set on it.
+type nativeDeadlineFile struct {
+	File
+	deadline time.Time
+}
+
+func (f *nativeDeadlineFile) SetDeadline(t time.Time) error      { f.deadline = t; return nil }
+func (f *nativeDeadlineFile) SetReadDeadline(t time.Time) error  { f.deadline = t; return nil }
+func (f *nativeDeadlineFile) SetWriteDeadline(t time.Time) error { f.deadline = t; return nil }
+
+func TestWithDeadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	f := WithDeadline(&slowFile{File: openTestFile(t, "data"), release: release})
+	d, ok := f.(Deadliner)
+	if !ok {
+		t.Fatal("WithDeadline did not return a Deadliner")
+	}
+
+	// without a deadline operations run directly
+	buf := make([]byte, 4)
+	if n, err := f.ReadAt(buf, 0); n != 4 || (err != nil && err != io.EOF) {
+		t.Fatalf("got %d, %v", n, err)
+	}
+
+	if err := d.SetReadDeadline(time.Now().Add(20 * time.Millisecond)); err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	_, err := f.Read(buf)
+	var perr *os.PathError
+	if !errors.As(err, &perr) || !errors.Is(err, os.ErrDeadlineExceeded) || perr.Op != "read" {
+		t.Errorf("got %v, expected a read PathError wrapping %v", err, os.ErrDeadlineExceeded)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Read took %v", elapsed)
+	}
+
+	// a deadline in the past fails at once, and only applies to reads
+	if _, err := f.ReadAt(buf, 0); !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("got %v, expected %v", err, os.ErrDeadlineExceeded)
+	}
+	if _, err := f.WriteAt([]byte("DA"), 0); err != nil {
+		t.Errorf("got %v, expected the write to succeed", err)
+	}
+	if err := d.SetDeadline(time.Time{}); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := f.ReadAt(buf, 0); n != 4 || string(buf) != "DAta" {
+		t.Errorf("got %d, %v, %q after clearing the deadline", n, err, buf)
+	}
+
+	native := &nativeDeadlineFile{File: openTestFile(t, "")}
+	if WithDeadline(native) != File(native) {
+		t.Error("a native Deadliner should be returned unchanged")
+	}
+}
@@ -0,0 +1,156 @@
+package absfs
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"sort"
+)
+
+// DiffKind - describes how a path differs between two trees.
+type DiffKind int
+
+const (
+	DiffOnlyInA DiffKind = iota // the path exists only in the first tree.
+	DiffOnlyInB                 // the path exists only in the second tree.
+	DiffMode                    // the file modes differ.
+	DiffSize                    // both are regular files of different sizes.
+	DiffContent                 // both are regular files of equal size with different contents.
+)
+
+// String - returns the name of the `DiffKind`.
+func (k DiffKind) String() string {
+	switch k {
+	case DiffOnlyInA:
+		return "only in a"
+	case DiffOnlyInB:
+		return "only in b"
+	case DiffMode:
+		return "mode mismatch"
+	case DiffSize:
+		return "size mismatch"
+	case DiffContent:
+		return "content mismatch"
+	}
+	return "unknown"
+}
+
+// DiffEntry - is a single difference reported by `Diff`. `Path` is slash
+// separated and relative to the roots being compared.
+type DiffEntry struct {
+	Path string
+	Kind DiffKind
+}
+
+// Diff - walks the tree rooted at `aRoot` in `a` and the tree rooted at
+// `bRoot` in `b` and reports every path that differs, in lexical order. Every
+// path missing from one side is reported, including the contents of missing
+// directories. For paths present on both sides the modes are compared first,
+// then the sizes of regular files, and finally their contents, which are
+// streamed in chunks so large files are never held in memory. Modification
+// times are not compared. An empty result means the trees are equivalent.
+func Diff(a FileSystem, aRoot string, b FileSystem, bRoot string) ([]DiffEntry, error) {
+	aInfos, err := diffTree(a, aRoot)
+	if err != nil {
+		return nil, err
+	}
+	bInfos, err := diffTree(b, bRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([]string, 0, len(aInfos)+len(bInfos))
+	for p := range aInfos {
+		paths = append(paths, p)
+	}
+	for p := range bInfos {
+		if _, ok := aInfos[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	var diffs []DiffEntry
+	for _, p := range paths {
+		aInfo, inA := aInfos[p]
+		bInfo, inB := bInfos[p]
+		switch {
+		case !inB:
+			diffs = append(diffs, DiffEntry{p, DiffOnlyInA})
+		case !inA:
+			diffs = append(diffs, DiffEntry{p, DiffOnlyInB})
+		case aInfo.Mode() != bInfo.Mode():
+			diffs = append(diffs, DiffEntry{p, DiffMode})
+		case !aInfo.Mode().IsRegular():
+		case aInfo.Size() != bInfo.Size():
+			diffs = append(diffs, DiffEntry{p, DiffSize})
+		default:
+			equal, err := sameContent(a, Join(a, aRoot, p), b, Join(b, bRoot, p))
+			if err != nil {
+				return nil, err
+			}
+			if !equal {
+				diffs = append(diffs, DiffEntry{p, DiffContent})
+			}
+		}
+	}
+	return diffs, nil
+}
+
+// diffTree - returns the `os.FileInfo` of every entry below `root`, keyed by
+// its slash separated path relative to `root`.
+func diffTree(fsys FileSystem, root string) (map[string]os.FileInfo, error) {
+	infos := make(map[string]os.FileInfo)
+	err := Walk(fsys, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := Rel(fsys, root, path)
+		if err != nil {
+			return err
+		}
+		if rel != "." {
+			infos[toSlash(fsys.Separator(), rel)] = info
+		}
+		return nil
+	})
+	return infos, err
+}
+
+// sameContent - reports whether two files have identical contents, reading
+// both in chunks.
+func sameContent(a FileSystem, aName string, b FileSystem, bName string) (bool, error) {
+	af, err := a.Open(aName)
+	if err != nil {
+		return false, err
+	}
+	defer af.Close()
+	bf, err := b.Open(bName)
+	if err != nil {
+		return false, err
+	}
+	defer bf.Close()
+
+	abuf := copyBufPool.Get().(*[]byte)
+	defer copyBufPool.Put(abuf)
+	bbuf := copyBufPool.Get().(*[]byte)
+	defer copyBufPool.Put(bbuf)
+
+	for {
+		an, aerr := io.ReadFull(af, *abuf)
+		bn, berr := io.ReadFull(bf, *bbuf)
+		if !bytes.Equal((*abuf)[:an], (*bbuf)[:bn]) {
+			return false, nil
+		}
+		aeof := aerr == io.EOF || aerr == io.ErrUnexpectedEOF
+		beof := berr == io.EOF || berr == io.ErrUnexpectedEOF
+		switch {
+		case aerr != nil && !aeof:
+			return false, aerr
+		case berr != nil && !beof:
+			return false, berr
+		case aeof || beof:
+			return aeof == beof, nil
+		}
+	}
+}
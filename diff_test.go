@@ -0,0 +1,84 @@
+package absfs
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDiff(t *testing.T) {
+	a, b := newTestFS(), newTestFS()
+	for _, fsys := range []FileSystem{a, b} {
+		if err := fsys.MkdirAll("/root/dir", 0755); err != nil {
+			t.Fatal(err)
+		}
+		writeTestFile(t, fsys, "/root/same", "same")
+		writeTestFile(t, fsys, "/root/dir/nested", "nested")
+	}
+	large := strings.Repeat("x", 100*1024)
+	writeTestFile(t, a, "/root/size", "short")
+	writeTestFile(t, b, "/root/size", "longer")
+	writeTestFile(t, a, "/root/content", large+"a")
+	writeTestFile(t, b, "/root/content", large+"b")
+	writeTestFile(t, a, "/root/mode", "mode")
+	writeTestFile(t, b, "/root/mode", "mode")
+	if err := b.Chmod("/root/mode", 0600); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, a, "/root/dir/onlya", "a")
+	if err := b.Mkdir("/root/onlyb", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	diffs, err := Diff(a, "/root", b, "/root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []DiffEntry{
+		{"content", DiffContent},
+		{"dir/onlya", DiffOnlyInA},
+		{"mode", DiffMode},
+		{"onlyb", DiffOnlyInB},
+		{"size", DiffSize},
+	}
+	if !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("got %v, expected %v", diffs, expected)
+	}
+
+	diffs, err = Diff(a, "/root/dir", b, "/root/dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(diffs) != 1 || diffs[0].Kind.String() != "only in a" {
+		t.Errorf("got %v, expected a single only in a entry", diffs)
+	}
+
+	if diffs, err := Diff(a, "/root", a, "/root"); err != nil || len(diffs) != 0 {
+		t.Errorf("a tree compared to itself: %v, %v", diffs, err)
+	}
+}
+
+func TestDiffSeparators(t *testing.T) {
+	m := newMockFiler()
+	a := ExtendFilerWithOptions(backslashFiler{m}, WithSeparator('\\'))
+	b := newTestFS()
+	if err := a.MkdirAll(`\root\dir`, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.MkdirAll("/root/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, a, `\root\dir\same`, "same")
+	writeTestFile(t, b, "/root/dir/same", "same")
+	writeTestFile(t, a, `\root\dir\content`, "a")
+	writeTestFile(t, b, "/root/dir/content", "b")
+
+	diffs, err := Diff(a, `\root`, b, "/root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []DiffEntry{{"dir/content", DiffContent}}
+	if !reflect.DeepEqual(diffs, expected) {
+		t.Errorf("got %v, expected %v", diffs, expected)
+	}
+}
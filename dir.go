@@ -0,0 +1,205 @@
+package absfs
+
+import (
+	"io"
+	"os"
+	"sort"
+	"syscall"
+)
+
+// IsEmptyDir - reports whether the directory `name` contains no entries. It
+// reads at most one entry with `Readdirnames(1)` instead of listing the
+// whole directory. If `name` is not a directory the error is an
+// `*os.PathError` wrapping `syscall.ENOTDIR`.
+func IsEmptyDir(fsys FileSystem, name string) (bool, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return false, err
+	}
+	if !info.IsDir() {
+		return false, &os.PathError{Op: "readdirent", Path: name, Err: syscall.ENOTDIR}
+	}
+
+	names, err := f.Readdirnames(1)
+	if err == io.EOF {
+		return true, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return len(names) == 0, nil
+}
+
+// PruneEmptyDirs - removes every empty directory below `root`, bottom-up, so
+// that a directory which only contains empty directories is removed as well,
+// and returns the number of directories removed. `root` itself is never
+// removed, even if it ends up empty. Symbolic links are not followed. The
+// first error stops the pruning and is returned with the number removed so
+// far.
+func PruneEmptyDirs(fsys FileSystem, root string) (int, error) {
+	info, err := Lstat(fsys, root)
+	if err != nil {
+		return 0, err
+	}
+	if !info.IsDir() {
+		return 0, &os.PathError{Op: "readdirent", Path: root, Err: syscall.ENOTDIR}
+	}
+	n, _, err := pruneDir(fsys, root)
+	return n, err
+}
+
+// pruneDir - removes the empty directories below `dir`, and reports how many
+// were removed and whether `dir` is now empty.
+func pruneDir(fsys FileSystem, dir string) (removed int, empty bool, err error) {
+	names, err := readDirNames(fsys, dir)
+	if err != nil {
+		return 0, false, err
+	}
+	remaining := len(names)
+	for _, name := range names {
+		p := Join(fsys, dir, name)
+		info, err := Lstat(fsys, p)
+		if err != nil {
+			return removed, false, err
+		}
+		if !info.IsDir() {
+			continue
+		}
+		n, empty, err := pruneDir(fsys, p)
+		removed += n
+		if err != nil {
+			return removed, false, err
+		}
+		if empty {
+			if err := fsys.Remove(p); err != nil {
+				return removed, false, err
+			}
+			removed++
+			remaining--
+		}
+	}
+	return removed, remaining == 0, nil
+}
+
+// readdirBatch - is the number of entries requested per call by
+// `ReaddirAll` and `ReaddirnamesAll`.
+const readdirBatch = 512
+
+// ReaddirAll - returns the `os.FileInfo` of every remaining entry in the
+// directory `f`, in directory order. Backends differ in whether `Readdir(0)`
+// or `Readdir(-1)` really returns everything, so the listing is read in
+// batches with `Readdir(512)` until `io.EOF`, which works with any backend
+// that pages correctly. A batch that is empty without an error also ends the
+// listing. On error the entries read so far are returned with it.
+func ReaddirAll(f File) ([]os.FileInfo, error) {
+	var all []os.FileInfo
+	for {
+		infos, err := f.Readdir(readdirBatch)
+		all = append(all, infos...)
+		if err == io.EOF {
+			return all, nil
+		}
+		if err != nil || len(infos) == 0 {
+			return all, err
+		}
+	}
+}
+
+// ReaddirnamesAll - is like `ReaddirAll`, but returns the names of the
+// entries using `Readdirnames`.
+func ReaddirnamesAll(f File) ([]string, error) {
+	var all []string
+	for {
+		names, err := f.Readdirnames(readdirBatch)
+		all = append(all, names...)
+		if err == io.EOF {
+			return all, nil
+		}
+		if err != nil || len(names) == 0 {
+			return all, err
+		}
+	}
+}
+
+// FileReadDir - reads the contents of the directory associated with `f` and
+// returns a slice of up to `n` `os.DirEntry` values in directory order. If
+// `f` provides it's own `ReadDir` method it is used, otherwise the results of
+// `Readdir`, or of `ReaddirAll` when n <= 0, are converted.
+//
+// If n > 0, FileReadDir returns at most n entries. In this case, if
+// FileReadDir returns an empty slice, it will return a non-nil error
+// explaining why. At the end of a directory, the error is io.EOF.
+//
+// If n <= 0, FileReadDir returns all the entries from the directory in a
+// single slice, and a nil error if it reads all the way to the end of the
+// directory.
+func FileReadDir(f File, n int) ([]os.DirEntry, error) {
+	if d, ok := f.(dirreader); ok {
+		return d.ReadDir(n)
+	}
+
+	var infos []os.FileInfo
+	var err error
+	if n <= 0 {
+		infos, err = ReaddirAll(f)
+	} else {
+		infos, err = f.Readdir(n)
+	}
+	entries := make([]os.DirEntry, len(infos))
+	for i, info := range infos {
+		entries[i] = FileInfoDirEntry(info)
+	}
+	return entries, err
+}
+
+type dirreader interface {
+	ReadDir(n int) ([]os.DirEntry, error)
+}
+
+// ReadDirSorted - returns the `os.FileInfo` of every entry in the directory
+// `name`, sorted by name in byte order. Use `Readdir` directly when the
+// backend's native order is acceptable.
+func ReadDirSorted(fsys FileSystem, name string) ([]os.FileInfo, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	infos, err := ReaddirAll(f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Name() < infos[j].Name() })
+	return infos, nil
+}
+
+// ReadDirNamesSorted - returns the names of the entries in the directory
+// `name`, sorted in byte order.
+func ReadDirNamesSorted(fsys FileSystem, name string) ([]string, error) {
+	return readDirNames(fsys, name)
+}
+
+// FileInfoDirEntry - returns an `os.DirEntry` that reports the information in
+// `fi`. `Name` and `IsDir` come from `fi`, `Type` is `fi.Mode().Type()`, and
+// `Info` returns `fi` itself. If `fi` is nil, it returns nil.
+func FileInfoDirEntry(fi os.FileInfo) os.DirEntry {
+	if fi == nil {
+		return nil
+	}
+	return fileInfoDirEntry{fi}
+}
+
+type fileInfoDirEntry struct {
+	fi os.FileInfo
+}
+
+func (e fileInfoDirEntry) Name() string               { return e.fi.Name() }
+func (e fileInfoDirEntry) IsDir() bool                { return e.fi.IsDir() }
+func (e fileInfoDirEntry) Type() os.FileMode          { return e.fi.Mode().Type() }
+func (e fileInfoDirEntry) Info() (os.FileInfo, error) { return e.fi, nil }
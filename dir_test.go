@@ -0,0 +1,311 @@
+package absfs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+)
+
+func TestIsEmptyDir(t *testing.T) {
+	fsys := newTestFS()
+	if err := fsys.MkdirAll("/empty", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.MkdirAll("/full", 0755); err != nil {
+		t.Fatal(err)
+	}
+	createFiles(t, fsys, "/full/a", "/full/b")
+
+	if empty, err := IsEmptyDir(fsys, "/empty"); err != nil || !empty {
+		t.Errorf("empty dir: got %t, %v", empty, err)
+	}
+	if empty, err := IsEmptyDir(fsys, "/full"); err != nil || empty {
+		t.Errorf("non-empty dir: got %t, %v", empty, err)
+	}
+	if _, err := IsEmptyDir(fsys, "/full/a"); !errors.Is(err, syscall.ENOTDIR) {
+		t.Errorf("file: got %v, expected ENOTDIR", err)
+	}
+}
+
+func TestFileReadDir(t *testing.T) {
+	fsys := newTestFS()
+	if err := fsys.MkdirAll("/dir/sub", 0755); err != nil {
+		t.Fatal(err)
+	}
+	createFiles(t, fsys, "/dir/a", "/dir/b")
+
+	f, err := fsys.Open("/dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var names []string
+	for {
+		entries, err := FileReadDir(f, 2)
+		for _, e := range entries {
+			names = append(names, e.Name())
+			if e.Name() == "sub" && !e.IsDir() {
+				t.Error("sub is not reported as a directory")
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) == 0 || len(entries) > 2 {
+			t.Fatalf("got %d entries, expected 1 or 2", len(entries))
+		}
+	}
+	if !reflect.DeepEqual(names, []string{"a", "b", "sub"}) {
+		t.Errorf("got %v", names)
+	}
+
+	f2, err := fsys.Open("/dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f2.Close()
+	entries, err := FileReadDir(f2, -1)
+	if err != nil || len(entries) != 3 {
+		t.Errorf("got %d entries, %v", len(entries), err)
+	}
+}
+
+// reverseDirFS - lists directories in reverse order to simulate a backend
+// with unsorted directory reads.
+type reverseDirFS struct {
+	FileSystem
+}
+
+func (fs reverseDirFS) Open(name string) (File, error) {
+	f, err := fs.FileSystem.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return reverseDirFile{f}, nil
+}
+
+type reverseDirFile struct {
+	File
+}
+
+func (f reverseDirFile) Readdir(n int) ([]os.FileInfo, error) {
+	infos, err := f.File.Readdir(n)
+	for i, j := 0, len(infos)-1; i < j; i, j = i+1, j-1 {
+		infos[i], infos[j] = infos[j], infos[i]
+	}
+	return infos, err
+}
+
+func (f reverseDirFile) Readdirnames(n int) ([]string, error) {
+	names, err := f.File.Readdirnames(n)
+	for i, j := 0, len(names)-1; i < j; i, j = i+1, j-1 {
+		names[i], names[j] = names[j], names[i]
+	}
+	return names, err
+}
+
+func TestReadDirSorted(t *testing.T) {
+	fsys := reverseDirFS{newTestFS()}
+	createFiles(t, fsys, "/B", "/a", "/c")
+	expected := []string{"B", "a", "c"}
+
+	infos, err := ReadDirSorted(fsys, "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("ReadDirSorted: got %v, expected %v", names, expected)
+	}
+
+	names, err = ReadDirNamesSorted(fsys, "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("ReadDirNamesSorted: got %v, expected %v", names, expected)
+	}
+
+	if _, err := ReadDirSorted(fsys, "/missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrNotExist)
+	}
+}
+
+func TestFileInfoDirEntry(t *testing.T) {
+	fsys := newTestSymlinkFS()
+	if err := fsys.Mkdir("/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+	createFiles(t, fsys, "/file")
+	if err := fsys.Symlink("/file", "/link"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		isDir bool
+		typ   os.FileMode
+	}{
+		{"/dir", true, os.ModeDir},
+		{"/file", false, 0},
+		{"/link", false, os.ModeSymlink},
+	}
+	for _, test := range tests {
+		fi, err := fsys.Lstat(test.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		d := FileInfoDirEntry(fi)
+		if d.Name() != fi.Name() || d.IsDir() != test.isDir || d.Type() != test.typ {
+			t.Errorf("%s: got name %q, isDir %t, type %s", test.name, d.Name(), d.IsDir(), d.Type())
+		}
+		if info, err := d.Info(); err != nil || info != fi {
+			t.Errorf("%s: Info returned %v, %v", test.name, info, err)
+		}
+	}
+
+	if FileInfoDirEntry(nil) != nil {
+		t.Error("expected nil for a nil FileInfo")
+	}
+}
+
+// oneEntryDirFS - returns directory handles that list a single entry per
+// `Readdir` or `Readdirnames` call, whatever the count asked for.
+type oneEntryDirFS struct {
+	FileSystem
+}
+
+func (fs oneEntryDirFS) Open(name string) (File, error) {
+	return fs.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (fs oneEntryDirFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	f, err := fs.FileSystem.OpenFile(name, flag, perm)
+	if err != nil {
+		return nil, err
+	}
+	return oneEntryDirFile{f}, nil
+}
+
+type oneEntryDirFile struct {
+	File
+}
+
+func (f oneEntryDirFile) Readdir(n int) ([]os.FileInfo, error) {
+	return f.File.Readdir(1)
+}
+
+func (f oneEntryDirFile) Readdirnames(n int) ([]string, error) {
+	return f.File.Readdirnames(1)
+}
+
+func TestReaddirAll(t *testing.T) {
+	fsys := oneEntryDirFS{newTestFS()}
+	createFiles(t, fsys, "/a", "/b", "/c")
+	expected := []string{"a", "b", "c"}
+
+	f, err := fsys.Open("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	infos, err := ReaddirAll(f)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, info := range infos {
+		names = append(names, info.Name())
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("got %v, expected %v", names, expected)
+	}
+
+	f, err = fsys.Open("/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	names, err = ReaddirnamesAll(f)
+	f.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(names, expected) {
+		t.Errorf("got %v, expected %v", names, expected)
+	}
+
+	// the helpers built on them see the whole listing too
+	matches, err := Glob(fsys, "/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(matches) != 3 {
+		t.Errorf("Glob got %v, expected 3 matches", matches)
+	}
+
+	f, err = fsys.Open("/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := ReaddirnamesAll(f); !errors.Is(err, syscall.ENOTDIR) {
+		t.Errorf("got %v, expected %v", err, syscall.ENOTDIR)
+	}
+}
+
+func TestPruneEmptyDirs(t *testing.T) {
+	fsys := newTestSymlinkFS()
+	for _, dir := range []string{"/root/a/b/c", "/root/d", "/root/e/f", "/root/g", "/elsewhere"} {
+		if err := fsys.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	createFiles(t, fsys, "/root/e/file")
+	if err := fsys.Symlink("/elsewhere", "/root/g/link"); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := PruneEmptyDirs(fsys, "/root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// a, a/b, a/b/c, d and e/f are removed
+	if n != 5 {
+		t.Errorf("removed %d directories, expected 5", n)
+	}
+	for _, dir := range []string{"/root/a", "/root/d", "/root/e/f"} {
+		if _, err := fsys.Stat(dir); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("%s: got %v, expected %v", dir, err, os.ErrNotExist)
+		}
+	}
+	for _, name := range []string{"/root/e/file", "/root/g", "/elsewhere"} {
+		if _, err := fsys.Stat(name); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+
+	// root is kept even when it ends up empty
+	if err := fsys.MkdirAll("/only/x/y", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := PruneEmptyDirs(fsys, "/only"); n != 2 || err != nil {
+		t.Errorf("got %d, %v, expected 2, nil", n, err)
+	}
+	if empty, err := IsEmptyDir(fsys, "/only"); !empty || err != nil {
+		t.Errorf("got %t, %v, expected the root to remain and be empty", empty, err)
+	}
+
+	if _, err := PruneEmptyDirs(fsys, "/root/e/file"); !errors.Is(err, syscall.ENOTDIR) {
+		t.Errorf("got %v, expected %v", err, syscall.ENOTDIR)
+	}
+}
@@ -0,0 +1,46 @@
+package absfs
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// jsonEntry - is the record written by `StreamDirJSON` for each entry.
+type jsonEntry struct {
+	Path    string `json:"path"`
+	Size    int64  `json:"size"`
+	Mode    string `json:"mode"`
+	ModTime string `json:"mtime"`
+	IsDir   bool   `json:"isDir"`
+}
+
+// StreamDirJSON - walks `root` in `fsys` and writes one JSON object per line
+// to `w` for every entry below `root`. Each object carries the slash separated
+// path relative to `root`, the size, the mode string, the modification time in
+// RFC 3339 format, and whether the entry is a directory. The output is
+// suitable for `jq` and other newline-delimited JSON tools.
+func StreamDirJSON(w io.Writer, fsys FileSystem, root string) error {
+	enc := json.NewEncoder(w)
+	return Walk(fsys, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if rel == "." {
+			return nil
+		}
+		return enc.Encode(&jsonEntry{
+			Path:    filepath.ToSlash(rel),
+			Size:    info.Size(),
+			Mode:    info.Mode().String(),
+			ModTime: info.ModTime().Format(time.RFC3339),
+			IsDir:   info.IsDir(),
+		})
+	})
+}
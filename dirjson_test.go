@@ -0,0 +1,55 @@
+package absfs
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestStreamDirJSON(t *testing.T) {
+	fsys := newTestFS()
+	if err := fsys.MkdirAll("/root/sub", 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fsys.Create("/root/sub/file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.WriteString("hello")
+	f.Close()
+	mtime := time.Date(2021, 6, 7, 8, 9, 10, 0, time.UTC)
+	if err := fsys.Chtimes("/root/sub/file.txt", mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := StreamDirJSON(&buf, fsys, "/root"); err != nil {
+		t.Fatal(err)
+	}
+
+	var entries []jsonEntry
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var e jsonEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			t.Fatalf("%s: %q", err, scanner.Text())
+		}
+		entries = append(entries, e)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("got %d entries, expected 2", len(entries))
+	}
+	if e := entries[0]; e.Path != "sub" || !e.IsDir || e.Mode != "drwxr-xr-x" {
+		t.Errorf("unexpected directory entry %+v", e)
+	}
+	e := entries[1]
+	if e.Path != "sub/file.txt" || e.IsDir || e.Size != 5 || e.Mode != "-rw-rw-rw-" {
+		t.Errorf("unexpected file entry %+v", e)
+	}
+	if e.ModTime != "2021-06-07T08:09:10Z" {
+		t.Errorf("got mtime %q, expected %q", e.ModTime, "2021-06-07T08:09:10Z")
+	}
+}
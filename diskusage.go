@@ -0,0 +1,49 @@
+package absfs
+
+import "os"
+
+// DiskUsageInfo - is the result of `DiskUsageDetailed`.
+type DiskUsageInfo struct {
+	Bytes    int64 // total size of the regular files
+	Files    int   // number of regular files
+	Dirs     int   // number of directories, including the root
+	Symlinks int   // number of symbolic links
+	Other    int   // number of other entries, such as devices and sockets
+}
+
+// DiskUsage - returns the total size of all regular files in the tree rooted
+// at `root`. See `DiskUsageDetailed` for what is counted.
+func DiskUsage(fsys FileSystem, root string) (int64, error) {
+	u, err := DiskUsageDetailed(fsys, root)
+	return u.Bytes, err
+}
+
+// DiskUsageDetailed - walks the tree rooted at `root` with `Walk` and returns
+// the total size of the regular files in it, together with the number of
+// entries of each kind. The sizes of directories themselves are not counted,
+// since they vary between backends and say nothing about their contents.
+// Symbolic links are not followed: each link is counted as a link, and
+// neither its own size nor that of its target is included in `Bytes`. The
+// walk stops at the first error, which is returned with the usage counted so
+// far.
+func DiskUsageDetailed(fsys FileSystem, root string) (DiskUsageInfo, error) {
+	var u DiskUsageInfo
+	err := Walk(fsys, root, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		switch mode := info.Mode(); {
+		case mode.IsRegular():
+			u.Files++
+			u.Bytes += info.Size()
+		case mode.IsDir():
+			u.Dirs++
+		case mode&os.ModeSymlink != 0:
+			u.Symlinks++
+		default:
+			u.Other++
+		}
+		return nil
+	})
+	return u, err
+}
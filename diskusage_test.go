@@ -0,0 +1,46 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestDiskUsage(t *testing.T) {
+	fsys := newTestSymlinkFS()
+	if err := fsys.MkdirAll("/root/a/b", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, fsys, "/root/one", "1")
+	writeTestFile(t, fsys, "/root/a/two", "22")
+	writeTestFile(t, fsys, "/root/a/b/three", "333")
+	writeTestFile(t, fsys, "/outside", strings.Repeat("x", 100))
+	if err := fsys.Symlink("/outside", "/root/link"); err != nil {
+		t.Fatal(err)
+	}
+
+	n, err := DiskUsage(fsys, "/root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 6 {
+		t.Errorf("got %d bytes, expected 6", n)
+	}
+
+	u, err := DiskUsageDetailed(fsys, "/root")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := DiskUsageInfo{Bytes: 6, Files: 3, Dirs: 3, Symlinks: 1}
+	if u != expected {
+		t.Errorf("got %+v, expected %+v", u, expected)
+	}
+
+	if n, err := DiskUsage(fsys, "/root/one"); n != 1 || err != nil {
+		t.Errorf("got %d, %v, expected 1, nil", n, err)
+	}
+	if _, err := DiskUsage(fsys, "/missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrNotExist)
+	}
+}
@@ -0,0 +1,265 @@
+package absfs
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// DryRun - returns a FileSystem that reads from `fsys` but records every
+// mutation in the returned `Plan` instead of executing it, so a CLI can offer
+// a `--dry-run` flag without special casing each call site. The plan can be
+// printed, inspected with `Steps`, and executed later with `Apply`.
+//
+// Reads always reflect the real state of `fsys`; they do not see the effect
+// of recorded mutations. For example, `Stat` of a directory recorded by
+// `Mkdir` fails with `os.ErrNotExist`, and a file recorded by `Remove` can
+// still be opened. The dry run has its own working directory, initially that
+// of `fsys`, so `Chdir` does not change `fsys`; every recorded path is made
+// absolute when it is recorded, and `Apply` does not depend on the working
+// directory of the FileSystem it is given.
+//
+// Opening a file for writing checks that the open would succeed and returns
+// an in-memory copy of the file. Changes made through the handle are recorded
+// as one "write" step, replacing the whole file, when it is closed.
+func DryRun(fsys FileSystem) (FileSystem, *Plan) {
+	cwd, err := fsys.Getwd()
+	if err != nil {
+		cwd = string(fsys.Separator())
+	}
+	p := &Plan{}
+	return &dryRunFS{FileSystem: fsys, plan: p, cwd: cwd}, p
+}
+
+// Plan - is the ordered list of mutations recorded by `DryRun`. It is safe
+// for concurrent use.
+type Plan struct {
+	mu    sync.Mutex
+	steps []PlanStep
+}
+
+// PlanStep - is one mutation recorded by `DryRun`.
+type PlanStep struct {
+	Op      string // the name of the FileSystem method, e.g. "mkdir".
+	Path    string // the absolute path given to the method.
+	NewPath string // the new path of a "rename", otherwise empty.
+	Args    string // a description of the other arguments, e.g. "0755".
+
+	apply func(FileSystem) error
+}
+
+func (s PlanStep) String() string {
+	str := s.Op + " " + s.Path
+	if s.NewPath != "" {
+		str += " " + s.NewPath
+	}
+	if s.Args != "" {
+		str += " " + s.Args
+	}
+	return str
+}
+
+func (p *Plan) record(step PlanStep) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.steps = append(p.steps, step)
+}
+
+// Steps - returns a copy of the recorded steps in the order they were
+// recorded.
+func (p *Plan) Steps() []PlanStep {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]PlanStep(nil), p.steps...)
+}
+
+// String - returns the recorded steps, one per line.
+func (p *Plan) String() string {
+	var b strings.Builder
+	for _, step := range p.Steps() {
+		b.WriteString(step.String())
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// Apply - executes the recorded steps on `fsys` in order, stopping at and
+// returning the first error. Steps are not removed from the plan.
+func (p *Plan) Apply(fsys FileSystem) error {
+	for _, step := range p.Steps() {
+		if err := step.apply(fsys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type dryRunFS struct {
+	FileSystem
+	plan *Plan
+
+	mu  sync.Mutex
+	cwd string
+}
+
+// abs - returns the cleaned absolute form of `name`, resolved against the
+// working directory of the dry run.
+func (fs *dryRunFS) abs(name string) string {
+	if IsAbs(name) {
+		return Clean(fs.FileSystem, name)
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return Join(fs.FileSystem, fs.cwd, name)
+}
+
+func (fs *dryRunFS) Getwd() (string, error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return fs.cwd, nil
+}
+
+func (fs *dryRunFS) Chdir(dir string) error {
+	p := fs.abs(dir)
+	info, err := fs.FileSystem.Stat(p)
+	if err != nil {
+		return &os.PathError{Op: "chdir", Path: dir, Err: unwrapPathError(err)}
+	}
+	if !info.IsDir() {
+		return &os.PathError{Op: "chdir", Path: dir, Err: syscall.ENOTDIR}
+	}
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	fs.cwd = p
+	return nil
+}
+
+func (fs *dryRunFS) Stat(name string) (os.FileInfo, error) {
+	return fs.FileSystem.Stat(fs.abs(name))
+}
+
+func (fs *dryRunFS) Open(name string) (File, error) {
+	return fs.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (fs *dryRunFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	name = fs.abs(name)
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) == 0 {
+		return fs.FileSystem.OpenFile(name, flag, perm)
+	}
+
+	var data []byte
+	info, err := fs.FileSystem.Stat(name)
+	switch {
+	case err == nil && flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0:
+		err = &os.PathError{Op: "open", Path: name, Err: os.ErrExist}
+		return &InvalidFile{Path: name, Err: err}, err
+	case err == nil && info.IsDir():
+		err = &os.PathError{Op: "open", Path: name, Err: ErrIsDirectory}
+		return &InvalidFile{Path: name, Err: err}, err
+	case err == nil:
+		perm = info.Mode().Perm()
+		if flag&os.O_TRUNC == 0 {
+			if data, err = ReadFile(fs.FileSystem, name); err != nil {
+				return &InvalidFile{Path: name, Err: err}, err
+			}
+		}
+	case flag&os.O_CREATE == 0:
+		return &InvalidFile{Path: name, Err: err}, err
+	}
+
+	f := &dryRunFile{memFile: NewMemFile(name, data, perm).(*memFile), plan: fs.plan, perm: perm}
+	if flag&os.O_APPEND != 0 {
+		f.offset = int64(len(data))
+	}
+	return f, nil
+}
+
+func (fs *dryRunFS) Create(name string) (File, error) {
+	return fs.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, 0666)
+}
+
+func (fs *dryRunFS) Mkdir(name string, perm os.FileMode) error {
+	name = fs.abs(name)
+	fs.plan.record(PlanStep{Op: "mkdir", Path: name, Args: fmt.Sprintf("%#o", perm),
+		apply: func(fsys FileSystem) error { return fsys.Mkdir(name, perm) }})
+	return nil
+}
+
+func (fs *dryRunFS) MkdirAll(name string, perm os.FileMode) error {
+	name = fs.abs(name)
+	fs.plan.record(PlanStep{Op: "mkdirall", Path: name, Args: fmt.Sprintf("%#o", perm),
+		apply: func(fsys FileSystem) error { return fsys.MkdirAll(name, perm) }})
+	return nil
+}
+
+func (fs *dryRunFS) Remove(name string) error {
+	name = fs.abs(name)
+	fs.plan.record(PlanStep{Op: "remove", Path: name,
+		apply: func(fsys FileSystem) error { return fsys.Remove(name) }})
+	return nil
+}
+
+func (fs *dryRunFS) RemoveAll(name string) error {
+	name = fs.abs(name)
+	fs.plan.record(PlanStep{Op: "removeall", Path: name,
+		apply: func(fsys FileSystem) error { return fsys.RemoveAll(name) }})
+	return nil
+}
+
+func (fs *dryRunFS) Rename(oldpath, newpath string) error {
+	oldpath, newpath = fs.abs(oldpath), fs.abs(newpath)
+	fs.plan.record(PlanStep{Op: "rename", Path: oldpath, NewPath: newpath,
+		apply: func(fsys FileSystem) error { return fsys.Rename(oldpath, newpath) }})
+	return nil
+}
+
+func (fs *dryRunFS) Chmod(name string, mode os.FileMode) error {
+	name = fs.abs(name)
+	fs.plan.record(PlanStep{Op: "chmod", Path: name, Args: fmt.Sprintf("%#o", mode),
+		apply: func(fsys FileSystem) error { return fsys.Chmod(name, mode) }})
+	return nil
+}
+
+func (fs *dryRunFS) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	name = fs.abs(name)
+	fs.plan.record(PlanStep{Op: "chtimes", Path: name,
+		Args:  atime.Format(time.RFC3339) + " " + mtime.Format(time.RFC3339),
+		apply: func(fsys FileSystem) error { return fsys.Chtimes(name, atime, mtime) }})
+	return nil
+}
+
+func (fs *dryRunFS) Chown(name string, uid, gid int) error {
+	name = fs.abs(name)
+	fs.plan.record(PlanStep{Op: "chown", Path: name, Args: fmt.Sprintf("%d:%d", uid, gid),
+		apply: func(fsys FileSystem) error { return fsys.Chown(name, uid, gid) }})
+	return nil
+}
+
+func (fs *dryRunFS) Truncate(name string, size int64) error {
+	name = fs.abs(name)
+	fs.plan.record(PlanStep{Op: "truncate", Path: name, Args: fmt.Sprint(size),
+		apply: func(fsys FileSystem) error { return fsys.Truncate(name, size) }})
+	return nil
+}
+
+// dryRunFile - an in-memory copy of a file opened for writing through
+// `DryRun`, recorded as a "write" step when closed.
+type dryRunFile struct {
+	*memFile
+	plan *Plan
+	perm os.FileMode
+}
+
+func (f *dryRunFile) Close() error {
+	if err := f.memFile.Close(); err != nil {
+		return err
+	}
+	name, data, perm := f.name, f.data, f.perm
+	f.plan.record(PlanStep{Op: "write", Path: name, Args: fmt.Sprintf("%d bytes", len(data)),
+		apply: func(fsys FileSystem) error { return WriteFile(fsys, name, data, perm) }})
+	return nil
+}
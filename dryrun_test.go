@@ -0,0 +1,126 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestDryRun(t *testing.T) {
+	backend := newTestFS()
+	writeTestFile(t, backend, "/existing", "old")
+	writeTestFile(t, backend, "/doomed", "bye")
+
+	fsys, plan := DryRun(backend)
+	if err := fsys.Mkdir("/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fsys.OpenFile("/existing", os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString(" and new"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, fsys, "/created", "fresh")
+	if err := fsys.Rename("/created", "/dir/created"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Remove("/doomed"); err != nil {
+		t.Fatal(err)
+	}
+
+	// nothing has changed, and reads see the real backend
+	if got := readTestFile(t, fsys, "/existing"); got != "old" {
+		t.Errorf("got %q, expected %q", got, "old")
+	}
+	if _, err := fsys.Stat("/dir"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrNotExist)
+	}
+	if _, err := fsys.OpenFile("/existing", os.O_RDWR|os.O_CREATE|os.O_EXCL, 0644); !errors.Is(err, os.ErrExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrExist)
+	}
+	if _, err := fsys.OpenFile("/missing", os.O_WRONLY, 0); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrNotExist)
+	}
+
+	expected := "mkdir /dir 0755\n" +
+		"write /existing 11 bytes\n" +
+		"write /created 5 bytes\n" +
+		"rename /created /dir/created\n" +
+		"remove /doomed\n"
+	if plan.String() != expected {
+		t.Errorf("got plan\n%s\nexpected\n%s", plan, expected)
+	}
+
+	if err := plan.Apply(backend); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, backend, "/existing"); got != "old and new" {
+		t.Errorf("got %q, expected %q", got, "old and new")
+	}
+	if got := readTestFile(t, backend, "/dir/created"); got != "fresh" {
+		t.Errorf("got %q, expected %q", got, "fresh")
+	}
+	if _, err := backend.Stat("/doomed"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrNotExist)
+	}
+
+	// a failing step stops Apply
+	if err := plan.Apply(backend); !errors.Is(err, os.ErrExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrExist)
+	}
+}
+
+func TestDryRunChdir(t *testing.T) {
+	backend := newTestFS()
+	if err := backend.Mkdir("/a", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, backend, "/a/file", "data")
+
+	fsys, plan := DryRun(backend)
+	if err := fsys.Chdir("/a"); err != nil {
+		t.Fatal(err)
+	}
+	if wd, _ := backend.Getwd(); wd != "/" {
+		t.Errorf("Chdir changed the backend's working directory to %q", wd)
+	}
+	if wd, _ := fsys.Getwd(); wd != "/a" {
+		t.Errorf("Getwd: got %q, expected %q", wd, "/a")
+	}
+	if got := readTestFile(t, fsys, "file"); got != "data" {
+		t.Errorf("got %q, expected %q", got, "data")
+	}
+	if err := fsys.Mkdir("sub", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Rename("file", "../moved"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Chdir("/"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Chdir("/a/file"); !errors.Is(err, syscall.ENOTDIR) {
+		t.Errorf("got %v, expected %v", err, syscall.ENOTDIR)
+	}
+
+	expected := "mkdir /a/sub 0755\n" +
+		"rename /a/file /moved\n"
+	if plan.String() != expected {
+		t.Errorf("got plan\n%s\nexpected\n%s", plan, expected)
+	}
+	if err := plan.Apply(backend); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := backend.Stat("/a/sub"); err != nil || !info.IsDir() {
+		t.Errorf("Apply did not create /a/sub: %v", err)
+	}
+	if got := readTestFile(t, backend, "/moved"); got != "data" {
+		t.Errorf("got %q, expected %q", got, "data")
+	}
+}
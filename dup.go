@@ -0,0 +1,55 @@
+package absfs
+
+import (
+	"io"
+	"os"
+)
+
+// Duper - is implemented by File handles that can produce a second handle to
+// the same underlying file. The new handle must have its own offset, starting
+// at the offset of the original, so that seeking or reading through one
+// handle does not move the other. Because it refers to the open file rather
+// than its name, it keeps working after the file is renamed or removed.
+type Duper interface {
+	Dup() (File, error)
+}
+
+// Dup - returns a new handle to the open file `f` with its own offset, so
+// that concurrent readers don't have to coordinate their seeks. Both handles
+// must be closed.
+//
+// If `f` implements `Duper` the handle is duplicated natively. Otherwise, if
+// `fsys` is not nil, `f.Name()` is reopened read-only on `fsys` and the new
+// handle is positioned at the current offset of `f`. The reopened handle
+// refers to whatever file has that name at the time of the call, so it fails
+// or sees a different file if `f` has since been renamed, removed, or
+// replaced. Wrap `f` with `StableFile` to have `Dup` detect this and fail
+// with an `*os.PathError` wrapping `ErrFileChanged` instead. With neither
+// available it returns an `*os.PathError` wrapping `ErrNotImplemented`.
+func Dup(fsys FileSystem, f File) (File, error) {
+	if d, ok := f.(Duper); ok {
+		return d.Dup()
+	}
+	if fsys == nil {
+		return nil, &os.PathError{Op: "dup", Path: f.Name(), Err: ErrNotImplemented}
+	}
+	offset, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, err
+	}
+	dup, err := fsys.Open(f.Name())
+	if err != nil {
+		return nil, err
+	}
+	if v, ok := f.(verifier); ok {
+		if err := v.verify("dup", dup); err != nil {
+			dup.Close()
+			return nil, err
+		}
+	}
+	if _, err := dup.Seek(offset, io.SeekStart); err != nil {
+		dup.Close()
+		return nil, err
+	}
+	return dup, nil
+}
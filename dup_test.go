@@ -0,0 +1,69 @@
+package absfs
+
+import (
+	"errors"
+	"io"
+	"testing"
+)
+
+// dupFile - duplicates itself natively.
+type dupFile struct {
+	File
+	dups int
+}
+
+func (f *dupFile) Dup() (File, error) {
+	f.dups++
+	return NewMemFile(f.Name(), []byte("native"), 0644), nil
+}
+
+func TestDup(t *testing.T) {
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/file", "0123456789")
+	f, err := fsys.Open("/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Seek(4, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+
+	dup, err := Dup(fsys, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer dup.Close()
+
+	// the new handle starts at the same offset, but moves independently
+	buf := make([]byte, 3)
+	if _, err := io.ReadFull(dup, buf); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "456" {
+		t.Errorf("got %q, expected %q", buf, "456")
+	}
+	if _, err := io.ReadFull(f, buf); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf) != "456" {
+		t.Errorf("got %q, expected %q", buf, "456")
+	}
+
+	native := &dupFile{File: f}
+	dup, err = Dup(nil, native)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := io.ReadAll(dup)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "native" || native.dups != 1 {
+		t.Errorf("got %q after %d dups, expected the native Dup", data, native.dups)
+	}
+
+	if _, err := Dup(nil, f); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("got %v, expected %v", err, ErrNotImplemented)
+	}
+}
@@ -0,0 +1,73 @@
+package absfs
+
+import (
+	"os"
+	"syscall"
+)
+
+// EnforceFlags - returns a File that checks every call against the access
+// mode in `flags`, the flags `f` was opened with. `Write`, `WriteAt`,
+// `WriteString`, and `Truncate` fail unless the handle was opened with
+// `O_WRONLY` or `O_RDWR`, and `Read` and `ReadAt` fail unless it was opened
+// with `O_RDONLY` or `O_RDWR`. The errors are `*os.PathError`s wrapping
+// `syscall.EBADF`, as they would be for an OS file, so behavior is consistent
+// regardless of how lenient the backend is.
+func EnforceFlags(f File, flags Flags) File {
+	return &enforcedFile{
+		File:     f,
+		readable: flags.IsReadable(),
+		writable: flags.IsWritable(),
+	}
+}
+
+type enforcedFile struct {
+	File
+	readable bool
+	writable bool
+}
+
+func (f *enforcedFile) badf(op string) error {
+	return &os.PathError{Op: op, Path: f.File.Name(), Err: syscall.EBADF}
+}
+
+func (f *enforcedFile) Read(b []byte) (int, error) {
+	if !f.readable {
+		return 0, f.badf("read")
+	}
+	return f.File.Read(b)
+}
+
+func (f *enforcedFile) ReadAt(b []byte, off int64) (int, error) {
+	if !f.readable {
+		return 0, f.badf("read")
+	}
+	return f.File.ReadAt(b, off)
+}
+
+func (f *enforcedFile) Write(b []byte) (int, error) {
+	if !f.writable {
+		return 0, f.badf("write")
+	}
+	return f.File.Write(b)
+}
+
+func (f *enforcedFile) WriteAt(b []byte, off int64) (int, error) {
+	if !f.writable {
+		return 0, f.badf("write")
+	}
+	return f.File.WriteAt(b, off)
+}
+
+func (f *enforcedFile) WriteString(s string) (int, error) {
+	if !f.writable {
+		return 0, f.badf("write")
+	}
+	return f.File.WriteString(s)
+}
+
+func (f *enforcedFile) Truncate(size int64) error {
+	if !f.writable {
+		return f.badf("truncate")
+	}
+	return f.File.Truncate(size)
+}
@@ -0,0 +1,66 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestEnforceFlags(t *testing.T) {
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/file", "data")
+
+	f, err := fsys.OpenFile("/file", os.O_RDONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	ro := EnforceFlags(f, Flags(os.O_RDONLY))
+	if _, err := ro.Write([]byte("x")); !errors.Is(err, syscall.EBADF) {
+		t.Errorf("Write: got %v, expected %v", err, syscall.EBADF)
+	}
+	if _, err := ro.WriteAt([]byte("x"), 0); !errors.Is(err, syscall.EBADF) {
+		t.Errorf("WriteAt: got %v, expected %v", err, syscall.EBADF)
+	}
+	if _, err := ro.WriteString("x"); !errors.Is(err, syscall.EBADF) {
+		t.Errorf("WriteString: got %v, expected %v", err, syscall.EBADF)
+	}
+	if err := ro.Truncate(0); !errors.Is(err, syscall.EBADF) {
+		t.Errorf("Truncate: got %v, expected %v", err, syscall.EBADF)
+	}
+	buf := make([]byte, 4)
+	if n, err := ro.Read(buf); err != nil || string(buf[:n]) != "data" {
+		t.Errorf("Read: got %q, %v", buf[:n], err)
+	}
+
+	wo := EnforceFlags(f, Flags(os.O_WRONLY|os.O_APPEND))
+	if _, err := wo.Read(buf); !errors.Is(err, syscall.EBADF) {
+		t.Errorf("Read: got %v, expected %v", err, syscall.EBADF)
+	}
+	if _, err := wo.ReadAt(buf, 0); !errors.Is(err, syscall.EBADF) {
+		t.Errorf("ReadAt: got %v, expected %v", err, syscall.EBADF)
+	}
+
+	rw := EnforceFlags(f, Flags(os.O_RDWR))
+	if _, err := rw.WriteAt([]byte("D"), 0); err != nil {
+		t.Errorf("WriteAt: %v", err)
+	}
+	if _, err := rw.ReadAt(buf, 0); err != nil || string(buf) != "Data" {
+		t.Errorf("ReadAt: got %q, %v", buf, err)
+	}
+}
+
+func TestEnforceAccessMode(t *testing.T) {
+	fsys := ExtendFilerWithOptions(newMockFiler(), EnforceAccessMode())
+	writeTestFile(t, fsys, "/file", "data")
+
+	f, err := fsys.Open("/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Write([]byte("x")); !errors.Is(err, syscall.EBADF) {
+		t.Errorf("got %v, expected %v", err, syscall.EBADF)
+	}
+}
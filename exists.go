@@ -0,0 +1,85 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+)
+
+// Exists - reports whether `name` exists in `fsys`. A not-exist error from
+// `Stat` is translated to `(false, nil)`; any other error is returned.
+func Exists(fsys FileSystem, name string) (bool, error) {
+	_, err := fsys.Stat(name)
+	if err == nil {
+		return true, nil
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return false, err
+}
+
+// IsDir - reports whether `name` exists in `fsys` and is a directory. A
+// not-exist error from `Stat` is translated to `(false, nil)`; any other error
+// is returned.
+func IsDir(fsys FileSystem, name string) (bool, error) {
+	info, err := fsys.Stat(name)
+	if err == nil {
+		return info.IsDir(), nil
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return false, err
+}
+
+// IsRegular - reports whether `name` exists in `fsys` and is a regular file.
+// A not-exist error from `Stat` is translated to `(false, nil)`; any other
+// error is returned.
+func IsRegular(fsys FileSystem, name string) (bool, error) {
+	info, err := fsys.Stat(name)
+	if err == nil {
+		return info.Mode().IsRegular(), nil
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return false, err
+}
+
+// PathState - is the result of `Probe`.
+type PathState int
+
+const (
+	PathMissing   PathState = iota // the path does not exist.
+	PathExists                     // the path exists.
+	PathForbidden                  // the path could not be checked because permission was denied.
+)
+
+// String - returns the name of the `PathState`.
+func (s PathState) String() string {
+	switch s {
+	case PathMissing:
+		return "missing"
+	case PathExists:
+		return "exists"
+	case PathForbidden:
+		return "forbidden"
+	}
+	return "unknown"
+}
+
+// Probe - is like `Exists` but also translates permission errors, so that
+// sandboxed callers can tell a missing path from a forbidden one. Errors
+// other than not-exist and permission errors are returned.
+func Probe(fsys FileSystem, name string) (PathState, error) {
+	_, err := fsys.Stat(name)
+	switch {
+	case err == nil:
+		return PathExists, nil
+	case errors.Is(err, os.ErrNotExist):
+		return PathMissing, nil
+	case errors.Is(err, os.ErrPermission):
+		return PathForbidden, nil
+	}
+	return PathMissing, err
+}
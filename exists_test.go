@@ -0,0 +1,58 @@
+package absfs
+
+import (
+	"os"
+	"testing"
+)
+
+// forbiddenFS - denies `Stat` of the path `forbidden`.
+type forbiddenFS struct {
+	FileSystem
+	forbidden string
+}
+
+func (fs *forbiddenFS) Stat(name string) (os.FileInfo, error) {
+	if name == fs.forbidden {
+		return nil, &os.PathError{Op: "stat", Path: name, Err: os.ErrPermission}
+	}
+	return fs.FileSystem.Stat(name)
+}
+
+func TestExists(t *testing.T) {
+	fsys := &forbiddenFS{newTestFS(), "/secret"}
+	if err := fsys.Mkdir("/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+	createFiles(t, fsys, "/dir/file")
+
+	tests := []struct {
+		Name    string
+		Exists  bool
+		Dir     bool
+		Regular bool
+	}{
+		{"/dir", true, true, false},
+		{"/dir/file", true, false, true},
+		{"/missing", false, false, false},
+	}
+	for _, test := range tests {
+		if ok, err := Exists(fsys, test.Name); ok != test.Exists || err != nil {
+			t.Errorf("Exists(%q) = %t, %v", test.Name, ok, err)
+		}
+		if ok, err := IsDir(fsys, test.Name); ok != test.Dir || err != nil {
+			t.Errorf("IsDir(%q) = %t, %v", test.Name, ok, err)
+		}
+		if ok, err := IsRegular(fsys, test.Name); ok != test.Regular || err != nil {
+			t.Errorf("IsRegular(%q) = %t, %v", test.Name, ok, err)
+		}
+	}
+
+	if _, err := Exists(fsys, "/secret"); err == nil {
+		t.Error("Exists: expected a permission error")
+	}
+	for name, exp := range map[string]PathState{"/dir": PathExists, "/missing": PathMissing, "/secret": PathForbidden} {
+		if state, err := Probe(fsys, name); state != exp || err != nil {
+			t.Errorf("Probe(%q) = %s, %v, expected %s", name, state, err, exp)
+		}
+	}
+}
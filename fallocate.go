@@ -0,0 +1,46 @@
+package absfs
+
+import (
+	"os"
+	"syscall"
+)
+
+// Preallocator - is implemented by filesystems that can reserve space for a
+// file without writing data, like `fallocate(2)`.
+type Preallocator interface {
+	Fallocate(name string, size int64) error
+}
+
+// Fallocate - reserves space so that the file `name` is at least `size` bytes
+// long, letting databases and downloaders that know the final size of a file
+// claim the space up front and fail early with `ENOSPC` rather than part way
+// through writing. Files already at least `size` bytes long are unchanged.
+//
+// If `fsys` implements `Preallocator` the call is delegated to it. Otherwise
+// the file is extended with `Truncate`; depending on the backend the added
+// range may be zero filled and allocated, or sparse, in which case no space
+// is actually reserved.
+func Fallocate(fsys FileSystem, name string, size int64) error {
+	if size < 0 {
+		return &os.PathError{Op: "fallocate", Path: name, Err: syscall.EINVAL}
+	}
+	if p, ok := fsys.(Preallocator); ok {
+		return p.Fallocate(name, size)
+	}
+	return truncateGrow(fsys, name, size)
+}
+
+// truncateGrow - implements `Fallocate` with `Truncate`.
+func truncateGrow(fsys FileSystem, name string, size int64) error {
+	info, err := fsys.Stat(name)
+	if err != nil {
+		return err
+	}
+	if info.IsDir() {
+		return &os.PathError{Op: "fallocate", Path: name, Err: ErrIsDirectory}
+	}
+	if info.Size() >= size {
+		return nil
+	}
+	return fsys.Truncate(name, size)
+}
@@ -0,0 +1,56 @@
+package absfs
+
+import (
+	"errors"
+	"testing"
+)
+
+// fallocateFiler - records calls to `Fallocate`.
+type fallocateFiler struct {
+	*mockFiler
+	name string
+	size int64
+}
+
+func (m *fallocateFiler) Fallocate(name string, size int64) error {
+	m.name, m.size = name, size
+	return nil
+}
+
+func TestFallocate(t *testing.T) {
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/file", "data")
+
+	if err := Fallocate(fsys, "/file", 16); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, fsys, "/file"); got != "data"+string(make([]byte, 12)) {
+		t.Errorf("got %q, expected data followed by 12 zero bytes", got)
+	}
+
+	// never shrinks
+	if err := Fallocate(fsys, "/file", 2); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := fsys.Stat("/file"); err != nil || info.Size() != 16 {
+		t.Errorf("got %v, %v, expected size 16", info, err)
+	}
+
+	if err := fsys.Mkdir("/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := Fallocate(fsys, "/dir", 16); !errors.Is(err, ErrIsDirectory) {
+		t.Errorf("got %v, expected %v", err, ErrIsDirectory)
+	}
+	if err := Fallocate(fsys, "/file", -1); err == nil {
+		t.Error("expected an error for a negative size")
+	}
+
+	m := &fallocateFiler{mockFiler: newMockFiler()}
+	if err := Fallocate(ExtendFiler(m), "file", 1<<20); err != nil {
+		t.Fatal(err)
+	}
+	if m.name != "/file" || m.size != 1<<20 {
+		t.Errorf("got %q, %d, expected the Filer to be called", m.name, m.size)
+	}
+}
@@ -116,5 +116,33 @@ func ExtendSeekable(sf Seekable) File {
 		return f
 	}
 
-	return &fileadapter{sf}
+	return &fileadapter{sf: sf}
+}
+
+// ExtendSeekableAppend - is like `ExtendSeekable` but gives the returned File
+// `O_APPEND` semantics for backends that do not track them: every `Write`,
+// `WriteString`, and `ReadFrom` first seeks to the end of the file. As with
+// an `*os.File` opened with `O_APPEND`, `WriteAt` returns an error. The
+// argument is always wrapped, even if it already implements `File`.
+func ExtendSeekableAppend(sf Seekable) File {
+	return &fileadapter{sf: sf, append: true}
+}
+
+// AsReadSeeker - returns `f` as an `io.ReadSeeker`, for libraries such as
+// `http.ServeContent` that accept one. The data is not copied: the result
+// shares the handle, and its offset, with `f`.
+func AsReadSeeker(f File) io.ReadSeeker {
+	return f
+}
+
+// AsReadWriteSeeker - returns `f` as an `io.ReadWriteSeeker`, sharing the
+// handle with `f` as `AsReadSeeker` does.
+func AsReadWriteSeeker(f File) io.ReadWriteSeeker {
+	return f
+}
+
+// AsReadSeekCloser - returns `f` as an `io.ReadSeekCloser`, sharing the handle
+// with `f` as `AsReadSeeker` does. Closing the result closes `f`.
+func AsReadSeekCloser(f File) io.ReadSeekCloser {
+	return f
 }
@@ -0,0 +1,42 @@
+package absfs
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAsReadSeeker(t *testing.T) {
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/file.txt", "hello world")
+	f, err := fsys.Open("/file.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest("GET", "/file.txt", nil)
+	req.Header.Set("Range", "bytes=6-")
+	rec := httptest.NewRecorder()
+	http.ServeContent(rec, req, "file.txt", time.Time{}, AsReadSeeker(f))
+	if rec.Code != http.StatusPartialContent || rec.Body.String() != "world" {
+		t.Errorf("got %d %q, expected %d %q", rec.Code, rec.Body.String(), http.StatusPartialContent, "world")
+	}
+
+	// the handle and its offset are shared
+	rws := AsReadWriteSeeker(f)
+	if _, err := rws.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 5)
+	if _, err := io.ReadFull(AsReadSeekCloser(f), buf); err != nil || string(buf) != "hello" {
+		t.Errorf("got %q, %v", buf, err)
+	}
+	if pos, err := Tell(f); pos != 5 || err != nil {
+		t.Errorf("got offset %d, %v, expected 5", pos, err)
+	}
+	if err := AsReadSeekCloser(f).Close(); err != nil {
+		t.Error(err)
+	}
+}
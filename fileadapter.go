@@ -3,13 +3,25 @@ package absfs
 import (
 	"io"
 	"os"
+	"sync"
 )
 
 // fileadapter - is an implementation of the `File` interface that wraps a
 // `Seekable` type and uses the `Seekable` interface to implement the additional
 // functions.
 type fileadapter struct {
-	sf Seekable
+	sf     Seekable
+	append bool
+}
+
+// seekEnd - moves the offset to the end of the file if `f` was created by
+// `ExtendSeekableAppend`.
+func (f *fileadapter) seekEnd() error {
+	if !f.append {
+		return nil
+	}
+	_, err := f.sf.Seek(0, io.SeekEnd)
+	return err
 }
 
 // Name - is a pass through function to the nested `Seekable` interface.
@@ -56,15 +68,25 @@ func (f *fileadapter) ReadAt(b []byte, off int64) (n int, err error) {
 	return f.sf.Read(b)
 }
 
-// Stat - is a pass through function to the nested `Seekable` interface.
+// Write - is a pass through function to the nested `Seekable` interface,
+// except that in append mode it first seeks to the end of the file.
 func (f *fileadapter) Write(p []byte) (int, error) {
+	if err := f.seekEnd(); err != nil {
+		return 0, err
+	}
 	return f.sf.Write(p)
 }
 
 // WriteAt - first checks to see if the nested `Seekable` type provides it's
 // own implementations of `ReadAt` and `WriteAt`. If not `WriteAt` is
 // implemented with `Seek` and `Write`.
+//
+// In append mode `WriteAt` returns an `*os.PathError` wrapping
+// `os.ErrInvalid`, as it does for an `*os.File` opened with `O_APPEND`.
 func (f *fileadapter) WriteAt(b []byte, off int64) (n int, err error) {
+	if f.append {
+		return 0, &os.PathError{Op: "writeat", Path: f.sf.Name(), Err: os.ErrInvalid}
+	}
 	if file, ok := f.sf.(ater); ok {
 		return file.WriteAt(b, off)
 	}
@@ -78,35 +100,67 @@ func (f *fileadapter) WriteAt(b []byte, off int64) (n int, err error) {
 // WriteString - is a convenience function that calls `Write` with `s` after
 // casting it to []byte.
 func (f *fileadapter) WriteString(s string) (n int, err error) {
-	return f.sf.Write([]byte(s))
+	return f.Write([]byte(s))
 }
 
 // Truncate - first checks to see of the nested `Seekable` type provides it's
-// own implementation of `Truncate`.  If not `Truncate` first seeks to the
-// beginning of the file, then writes `size` zero value bytes.
+// own implementation of `Truncate`.  If not, and `size` is larger than the
+// current size of the file, `Truncate` seeks to the end of the file and writes
+// zero value bytes up to `size` before restoring the I/O offset.
+//
+// A `Seekable` cannot be shrunk by writing, so real truncation requires the
+// nested type to implement `Truncate`. Without it, a `size` smaller than the
+// current size returns an `*os.PathError` wrapping `ErrNotImplemented`.
 func (f *fileadapter) Truncate(size int64) error {
 	if file, ok := f.sf.(filetruncater); ok {
 		return file.Truncate(size)
 	}
 
-	_, err := f.sf.Seek(0, io.SeekStart)
+	info, err := f.sf.Stat()
 	if err != nil {
 		return err
 	}
+	if size < info.Size() {
+		return &os.PathError{Op: "truncate", Path: f.sf.Name(), Err: ErrNotImplemented}
+	}
+	if size == info.Size() {
+		return nil
+	}
 
-	bufsize := 4096
-	buf := make([]byte, bufsize)
-	for i := 0; int64(i) < int64(size); i += bufsize {
-		if size-int64(i) < int64(bufsize) {
-			bufsize = int(size - int64(i))
-		}
+	offset, err := f.sf.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	_, err = f.sf.Seek(info.Size(), io.SeekStart)
+	if err != nil {
+		return err
+	}
 
-		_, err = f.sf.Write(buf[:bufsize])
+	buf := zeroBufPool.Get().(*[]byte)
+	defer zeroBufPool.Put(buf)
+	for remaining := size - info.Size(); remaining > 0; {
+		n := int64(len(*buf))
+		if remaining < n {
+			n = remaining
+		}
+		_, err = f.sf.Write((*buf)[:n])
 		if err != nil {
 			return err
 		}
+		remaining -= n
 	}
-	return nil
+
+	_, err = f.sf.Seek(offset, io.SeekStart)
+	return err
+}
+
+// zeroBufPool - holds the zero value buffers written by `Truncate`. The
+// buffers are never written to, so they remain zeroed.
+var zeroBufPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 4096)
+		return &b
+	},
 }
 
 // Readdirnames - first checks to see of the nested `Seekable` type provides
@@ -137,6 +191,31 @@ func (f *fileadapter) Close() error {
 	return f.sf.Close()
 }
 
+// ReadFrom - implements `io.ReaderFrom`. If the nested `Seekable` type
+// provides it's own implementation of `ReadFrom` it is used, otherwise `r` is
+// copied directly into the nested `Seekable` so that `io.Copy` can still use
+// a `WriterTo` implemented by `r`.
+func (f *fileadapter) ReadFrom(r io.Reader) (n int64, err error) {
+	if err := f.seekEnd(); err != nil {
+		return 0, err
+	}
+	if file, ok := f.sf.(io.ReaderFrom); ok {
+		return file.ReadFrom(r)
+	}
+	return io.Copy(f.sf, r)
+}
+
+// WriteTo - implements `io.WriterTo`. If the nested `Seekable` type provides
+// it's own implementation of `WriteTo` it is used, otherwise the nested
+// `Seekable` is copied directly to `w` so that `io.Copy` can still use a
+// `ReaderFrom` implemented by `w`.
+func (f *fileadapter) WriteTo(w io.Writer) (n int64, err error) {
+	if file, ok := f.sf.(io.WriterTo); ok {
+		return file.WriteTo(w)
+	}
+	return io.Copy(w, f.sf)
+}
+
 // interfaces for easy interface typing
 
 type ater interface {
@@ -0,0 +1,186 @@
+package absfs
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+// seekableOnly - hides every method of the embedded value that is not part
+// of the `Seekable` interface.
+type seekableOnly struct {
+	Seekable
+}
+
+// readerSeekable - is a read-only `Seekable` backed by a `bytes.Reader`,
+// which implements `io.WriterTo`.
+type readerSeekable struct {
+	*bytes.Reader
+}
+
+func (r *readerSeekable) Name() string                       { return "reader" }
+func (r *readerSeekable) Write(p []byte) (int, error)        { return 0, os.ErrPermission }
+func (r *readerSeekable) Close() error                       { return nil }
+func (r *readerSeekable) Sync() error                        { return nil }
+func (r *readerSeekable) Stat() (os.FileInfo, error)         { return nil, ErrNotImplemented }
+func (r *readerSeekable) Readdir(int) ([]os.FileInfo, error) { return nil, ErrNotImplemented }
+
+func newAdaptedMockFile(t testing.TB) File {
+	f, err := newTestFS().Create("/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ExtendSeekable(seekableOnly{f})
+}
+
+func TestFileAdapterReadFromWriteTo(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 10000)
+
+	f := newAdaptedMockFile(t)
+	n, err := io.Copy(f, bytes.NewReader(data))
+	if err != nil || n != int64(len(data)) {
+		t.Fatalf("ReadFrom copied %d, %v", n, err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	n, err = io.Copy(&buf, f)
+	if err != nil || n != int64(len(data)) {
+		t.Fatalf("WriteTo copied %d, %v", n, err)
+	}
+	if !bytes.Equal(buf.Bytes(), data) {
+		t.Error("copied data does not match")
+	}
+
+	// delegates to the nested WriterTo
+	src := ExtendSeekable(&readerSeekable{bytes.NewReader(data)})
+	buf.Reset()
+	n, err = src.(io.WriterTo).WriteTo(&buf)
+	if err != nil || n != int64(len(data)) || !bytes.Equal(buf.Bytes(), data) {
+		t.Errorf("delegated WriteTo copied %d, %v", n, err)
+	}
+}
+
+const benchCopySize = 10 << 20
+
+func BenchmarkFileAdapterCopyGeneric(b *testing.B) {
+	data := make([]byte, benchCopySize)
+	b.SetBytes(benchCopySize)
+	for i := 0; i < b.N; i++ {
+		src := ExtendSeekable(&readerSeekable{bytes.NewReader(data)})
+		if _, err := io.Copy(onlyWriter{io.Discard}, onlyReader{src}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkFileAdapterCopyWriteTo(b *testing.B) {
+	data := make([]byte, benchCopySize)
+	b.SetBytes(benchCopySize)
+	for i := 0; i < b.N; i++ {
+		src := ExtendSeekable(&readerSeekable{bytes.NewReader(data)})
+		if _, err := io.Copy(io.Discard, src); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestFileAdapterTruncateGrow(t *testing.T) {
+	f := newAdaptedMockFile(t)
+	if _, err := f.WriteString("abc"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Truncate(10000); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 10000 {
+		t.Errorf("got size %d, expected 10000", info.Size())
+	}
+	if offset, _ := f.Seek(0, io.SeekCurrent); offset != 3 {
+		t.Errorf("offset changed to %d", offset)
+	}
+
+	buf := make([]byte, 10000)
+	if _, err := f.ReadAt(buf, 0); err != nil {
+		t.Fatal(err)
+	}
+	if string(buf[:3]) != "abc" || !bytes.Equal(buf[3:], make([]byte, 9997)) {
+		t.Error("unexpected file contents")
+	}
+}
+
+func BenchmarkFileAdapterTruncate(b *testing.B) {
+	mf, err := newTestFS().Create("/file")
+	if err != nil {
+		b.Fatal(err)
+	}
+	f := ExtendSeekable(seekableOnly{mf})
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		mf.Truncate(0)
+		if err := f.Truncate(64); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestFileAdapterTruncateShrink(t *testing.T) {
+	f := newAdaptedMockFile(t)
+	if _, err := f.Write(make([]byte, 100)); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Truncate(100); err != nil {
+		t.Errorf("truncate to the current size: %v", err)
+	}
+	if err := f.Truncate(10); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("got %v, expected %v", err, ErrNotImplemented)
+	}
+	if info, _ := f.Stat(); info.Size() != 100 {
+		t.Errorf("got size %d, expected 100", info.Size())
+	}
+}
+
+func TestExtendSeekableAppend(t *testing.T) {
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/log", "start\n")
+	mf, err := fsys.OpenFile("/log", os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer mf.Close()
+
+	// the mockFiler ignores O_APPEND, so the offset starts at 0
+	f := ExtendSeekableAppend(seekableOnly{mf})
+	if _, err := f.Write([]byte("one\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("two\n"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.Copy(f, strings.NewReader("three\n")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteAt([]byte("x"), 0); !errors.Is(err, os.ErrInvalid) {
+		t.Errorf("WriteAt: got %v, expected %v", err, os.ErrInvalid)
+	}
+
+	if got, expected := readTestFile(t, fsys, "/log"), "start\none\ntwo\nthree\n"; got != expected {
+		t.Errorf("got %q, expected %q", got, expected)
+	}
+}
@@ -0,0 +1,65 @@
+package absfs
+
+import (
+	"os"
+	"time"
+)
+
+// FileChmod - is implemented by File handles that can change their own mode,
+// like `fchmod(2)`.
+type FileChmod interface {
+	Chmod(mode os.FileMode) error
+}
+
+// FileChown - is implemented by File handles that can change their own owner,
+// like `fchown(2)`.
+type FileChown interface {
+	Chown(uid, gid int) error
+}
+
+// FileChtimes - is implemented by File handles that can change their own
+// access and modification times, like `futimens(2)`.
+type FileChtimes interface {
+	Chtimes(atime time.Time, mtime time.Time) error
+}
+
+// FChmod - changes the mode of the open file `f`. If `f` implements
+// `FileChmod` the handle is used directly, which avoids the race between
+// checking a file and changing it by name. Otherwise, if `fsys` is not nil,
+// it falls back to `fsys.Chmod(f.Name(), mode)`. With neither available it
+// returns an `*os.PathError` wrapping `ErrNotImplemented`.
+func FChmod(fsys FileSystem, f File, mode os.FileMode) error {
+	if c, ok := f.(FileChmod); ok {
+		return c.Chmod(mode)
+	}
+	if fsys == nil {
+		return &os.PathError{Op: "chmod", Path: f.Name(), Err: ErrNotImplemented}
+	}
+	return fsys.Chmod(f.Name(), mode)
+}
+
+// FChown - changes the owner of the open file `f`, using the handle when `f`
+// implements `FileChown`, and otherwise falling back to `fsys` as `FChmod`
+// does.
+func FChown(fsys FileSystem, f File, uid, gid int) error {
+	if c, ok := f.(FileChown); ok {
+		return c.Chown(uid, gid)
+	}
+	if fsys == nil {
+		return &os.PathError{Op: "chown", Path: f.Name(), Err: ErrNotImplemented}
+	}
+	return fsys.Chown(f.Name(), uid, gid)
+}
+
+// FChtimes - changes the access and modification times of the open file `f`,
+// using the handle when `f` implements `FileChtimes`, and otherwise falling
+// back to `fsys` as `FChmod` does.
+func FChtimes(fsys FileSystem, f File, atime time.Time, mtime time.Time) error {
+	if c, ok := f.(FileChtimes); ok {
+		return c.Chtimes(atime, mtime)
+	}
+	if fsys == nil {
+		return &os.PathError{Op: "chtimes", Path: f.Name(), Err: ErrNotImplemented}
+	}
+	return fsys.Chtimes(f.Name(), atime, mtime)
+}
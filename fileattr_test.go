@@ -0,0 +1,99 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// attrFile - records handle based metadata changes.
+type attrFile struct {
+	File
+	mode  os.FileMode
+	uid   int
+	mtime time.Time
+}
+
+func (f *attrFile) Chmod(mode os.FileMode) error {
+	f.mode = mode
+	return nil
+}
+
+func (f *attrFile) Chown(uid, gid int) error {
+	f.uid = uid
+	return nil
+}
+
+func (f *attrFile) Chtimes(atime time.Time, mtime time.Time) error {
+	f.mtime = mtime
+	return nil
+}
+
+func TestFileAttr(t *testing.T) {
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/file", "data")
+	f, err := fsys.Open("/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	t0 := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// handle methods
+	af := &attrFile{File: f}
+	if err := FChmod(nil, af, 0600); err != nil || af.mode != 0600 {
+		t.Errorf("FChmod: got %s, %v", af.mode, err)
+	}
+	if err := FChown(nil, af, 42, 42); err != nil || af.uid != 42 {
+		t.Errorf("FChown: got %d, %v", af.uid, err)
+	}
+	if err := FChtimes(nil, af, t0, t0); err != nil || !af.mtime.Equal(t0) {
+		t.Errorf("FChtimes: got %s, %v", af.mtime, err)
+	}
+
+	// path fallback
+	if err := FChmod(fsys, f, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := FChtimes(fsys, f, t0, t0); err != nil {
+		t.Fatal(err)
+	}
+	info, err := fsys.Stat("/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 || !info.ModTime().Equal(t0) {
+		t.Errorf("got %s, %s, expected %s, %s", info.Mode(), info.ModTime(), os.FileMode(0600), t0)
+	}
+
+	// neither
+	for _, err := range []error{
+		FChmod(nil, f, 0600),
+		FChown(nil, f, 0, 0),
+		FChtimes(nil, f, t0, t0),
+	} {
+		if !errors.Is(err, ErrNotImplemented) {
+			t.Errorf("got %v, expected %v", err, ErrNotImplemented)
+		}
+	}
+}
+
+func TestFileAttrOSFiler(t *testing.T) {
+	fsys := ExtendFiler(OSFiler(t.TempDir()))
+	f, err := fsys.Create("/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := FChmod(nil, f, 0600); err != nil {
+		t.Fatal(err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("got %s, expected %s", info.Mode(), os.FileMode(0600))
+	}
+}
@@ -3,11 +3,20 @@ package absfs
 import (
 	"fmt"
 	"os"
-	"strings"
+	"strconv"
 )
 
 // ParseFileMode - parses a unix style file mode string and returns an
 // `os.FileMode` and an error.
+//
+// The last 9 characters are the user, group, and others permissions, and
+// permission letters are matched case-insensitively. In addition to `x`, the
+// user and group execute positions accept the `ls` style `s` (setuid or
+// setgid, and executable) and `S` (setuid or setgid only), and the others
+// execute position accepts `t` (sticky, and executable) and `T` (sticky
+// only). The characters before the permissions are the file type and mode
+// letters as produced by `os.FileMode.String`, or a single "-" for a regular
+// file.
 func ParseFileMode(input string) (os.FileMode, error) {
 	var mode os.FileMode
 
@@ -15,66 +24,72 @@ func ParseFileMode(input string) (os.FileMode, error) {
 		return 0, fmt.Errorf("unable to parse file mode string too short length == %d", len(input))
 	}
 
-	switch string(input[:1]) {
-	case "-":
-	case "d":
-		mode = os.ModeDir // d: is a directory
-	case "a":
-		mode = os.ModeAppend // a: append-only
-	case "l":
-		mode = os.ModeExclusive // l: exclusive use
-	case "T":
-		mode = os.ModeTemporary // T: temporary file; Plan 9 only
-	case "L":
-		mode = os.ModeSymlink // L: symbolic link
-	case "D":
-		mode = os.ModeDevice // D: device file
-	case "p":
-		mode = os.ModeNamedPipe // p: named pipe (FIFO)
-	case "S":
-		mode = os.ModeSocket // S: Unix domain socket
-	case "u":
-		mode = os.ModeSetuid // u: setuid
-	case "g":
-		mode = os.ModeSetgid // g: setgid
-	case "c":
-		mode = os.ModeCharDevice // c: Unix character device, when ModeDevice is set
-	case "t":
-		mode = os.ModeSticky // t: sticky
-	default:
-		return 0, fmt.Errorf("unable to parse file mode string unrecognized character %q", input[:1])
-	}
-
-	permissions := [][2]int{
-		// user permissions
-		{'r', OS_USER_R},
-		{'w', OS_USER_W},
-		{'x', OS_USER_X},
-
-		// group permissions
-		{'r', OS_GROUP_R},
-		{'w', OS_GROUP_W},
-		{'x', OS_GROUP_X},
-
-		// others permissions
-		{'r', OS_OTH_R},
-		{'w', OS_OTH_W},
-		{'x', OS_OTH_X},
-	}
-
-	for i, c := range strings.ToLower(input[1:]) {
-		if c == '-' {
+	n := len(input) - 9
+	for i := 0; i < n; i++ {
+		c := input[i]
+		if c == '-' && n == 1 {
 			continue
 		}
-		if int(c) != permissions[i][0] {
-			return 0, fmt.Errorf("unable to parse file mode string unrecognized character %q at %d.", string(input[i+1]), i+1)
+		if c >= 128 || fileModeTypes[c] == 0 {
+			return 0, fmt.Errorf("unable to parse file mode string unrecognized character %q", input[i:i+1])
+		}
+		mode |= fileModeTypes[c]
+	}
+
+	for i := 0; i < 9; i++ {
+		c := input[n+i]
+		switch {
+		case c == '-':
+		case c|0x20 == filePermLetters[i]:
+			mode |= filePermBits[i]
+		case i == 2 && c == 's':
+			mode |= os.ModeSetuid | OS_USER_X
+		case i == 2 && c == 'S':
+			mode |= os.ModeSetuid
+		case i == 5 && c == 's':
+			mode |= os.ModeSetgid | OS_GROUP_X
+		case i == 5 && c == 'S':
+			mode |= os.ModeSetgid
+		case i == 8 && c == 't':
+			mode |= os.ModeSticky | OS_OTH_X
+		case i == 8 && c == 'T':
+			mode |= os.ModeSticky
+		default:
+			return 0, fmt.Errorf("unable to parse file mode string unrecognized character %q at %d.", input[n+i:n+i+1], n+i)
 		}
-		mode |= os.FileMode(permissions[i][1])
 	}
 
 	return mode, nil
 }
 
+// fileModeTypes - maps the type and mode letters of `os.FileMode.String` to
+// their mode bits.
+var fileModeTypes = [128]os.FileMode{
+	'd': os.ModeDir,        // d: is a directory
+	'a': os.ModeAppend,     // a: append-only
+	'l': os.ModeExclusive,  // l: exclusive use
+	'T': os.ModeTemporary,  // T: temporary file; Plan 9 only
+	'L': os.ModeSymlink,    // L: symbolic link
+	'D': os.ModeDevice,     // D: device file
+	'p': os.ModeNamedPipe,  // p: named pipe (FIFO)
+	'S': os.ModeSocket,     // S: Unix domain socket
+	'u': os.ModeSetuid,     // u: setuid
+	'g': os.ModeSetgid,     // g: setgid
+	'c': os.ModeCharDevice, // c: Unix character device, when ModeDevice is set
+	't': os.ModeSticky,     // t: sticky
+	'?': os.ModeIrregular,  // ?: non-regular file; nothing else is known about this file
+}
+
+// filePermLetters and filePermBits - are the permission letter and bit
+// expected at each of the 9 permission positions.
+const filePermLetters = "rwxrwxrwx"
+
+var filePermBits = [9]os.FileMode{
+	OS_USER_R, OS_USER_W, OS_USER_X,
+	OS_GROUP_R, OS_GROUP_W, OS_GROUP_X,
+	OS_OTH_R, OS_OTH_W, OS_OTH_X,
+}
+
 // Permission flags not provided by the standard library.
 const (
 	OS_READ        = 04
@@ -108,3 +123,54 @@ const (
 	OS_ALL_RW  = OS_ALL_R | OS_ALL_W
 	OS_ALL_RWX = OS_ALL_RW | OS_GROUP_X
 )
+
+// MarshalFileMode - returns the canonical text form of `m`. Modes made up of
+// permission bits and the setuid, setgid and sticky bits are written in
+// `chmod` style octal, e.g. "0755" or "4755"; modes with any other bits set,
+// such as `os.ModeDir`, can't be expressed in octal and are written in the
+// symbolic form of `os.FileMode.String`.
+func MarshalFileMode(m os.FileMode) ([]byte, error) {
+	if m&^(os.ModePerm|os.ModeSetuid|os.ModeSetgid|os.ModeSticky) != 0 {
+		return []byte(m.String()), nil
+	}
+	bits := uint32(m.Perm())
+	if m&os.ModeSetuid != 0 {
+		bits |= 04000
+	}
+	if m&os.ModeSetgid != 0 {
+		bits |= 02000
+	}
+	if m&os.ModeSticky != 0 {
+		bits |= 01000
+	}
+	return []byte(fmt.Sprintf("%04o", bits)), nil
+}
+
+// UnmarshalFileMode - parses a file mode produced by `MarshalFileMode`. Input
+// made up only of octal digits is read as a `chmod` style octal mode, anything
+// else is parsed with `ParseFileMode`.
+func UnmarshalFileMode(data []byte) (os.FileMode, error) {
+	if len(data) == 0 {
+		return 0, fmt.Errorf("unable to parse file mode empty string")
+	}
+	for _, c := range data {
+		if c < '0' || c > '7' {
+			return ParseFileMode(string(data))
+		}
+	}
+	bits, err := strconv.ParseUint(string(data), 8, 32)
+	if err != nil || bits > 07777 {
+		return 0, fmt.Errorf("unable to parse file mode octal value %q out of range", data)
+	}
+	m := os.FileMode(bits).Perm()
+	if bits&04000 != 0 {
+		m |= os.ModeSetuid
+	}
+	if bits&02000 != 0 {
+		m |= os.ModeSetgid
+	}
+	if bits&01000 != 0 {
+		m |= os.ModeSticky
+	}
+	return m, nil
+}
@@ -61,3 +61,100 @@ func TestParseFileMode(t *testing.T) {
 		}
 	}
 }
+
+func TestParseFileModeSpecial(t *testing.T) {
+	tests := []struct {
+		In  string
+		Exp os.FileMode
+	}{
+		{"-rwsr-xr-x", os.ModeSetuid | 0755},
+		{"-rwSr--r--", os.ModeSetuid | 0644},
+		{"-rwxr-sr-x", os.ModeSetgid | 0755},
+		{"-rw-r-Sr--", os.ModeSetgid | 0644},
+		{"drwxrwxrwt", os.ModeDir | os.ModeSticky | 0777},
+		{"drwxrwxrwT", os.ModeDir | os.ModeSticky | 0776},
+		{"-RWXr-xr-x", 0755},
+		{(os.ModeDir | os.ModeSticky | 0755).String(), os.ModeDir | os.ModeSticky | 0755},
+		{(os.ModeSetuid | os.ModeSetgid | 0700).String(), os.ModeSetuid | os.ModeSetgid | 0700},
+	}
+	for _, test := range tests {
+		m, err := ParseFileMode(test.In)
+		if err != nil {
+			t.Errorf("%q: %s", test.In, err)
+			continue
+		}
+		if m != test.Exp {
+			t.Errorf("got %s, expected %s from %q", m, test.Exp, test.In)
+		}
+	}
+
+	for _, in := range []string{"-rwx", "xrwxrwxrwx", "-rwxrwxrwq", "drwxr-xr-xx", "-rwsrwxrws"} {
+		if _, err := ParseFileMode(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
+
+func BenchmarkParseFileMode(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := ParseFileMode("drwxr-xr-x"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestMarshalFileMode(t *testing.T) {
+	tests := []struct {
+		Mode os.FileMode
+		Text string
+	}{
+		{0, "0000"},
+		{0644, "0644"},
+		{0755, "0755"},
+		{os.ModeSetuid | 0755, "4755"},
+		{os.ModeSetgid | os.ModeSticky | 0770, "3770"},
+		{os.ModeDir | 0755, "drwxr-xr-x"},
+		{os.ModeSymlink | 0777, "Lrwxrwxrwx"},
+		{os.ModeDir | os.ModeSticky | 0777, "dtrwxrwxrwx"},
+	}
+	for _, test := range tests {
+		data, err := MarshalFileMode(test.Mode)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != test.Text {
+			t.Errorf("MarshalFileMode(%s) = %q, expected %q", test.Mode, data, test.Text)
+		}
+		m, err := UnmarshalFileMode(data)
+		if err != nil {
+			t.Errorf("UnmarshalFileMode(%q): %s", data, err)
+			continue
+		}
+		if m != test.Mode {
+			t.Errorf("UnmarshalFileMode(%q) = %s, expected %s", data, m, test.Mode)
+		}
+	}
+
+	for in, exp := range map[string]os.FileMode{
+		"755":        0755,
+		"0600":       0600,
+		"-rw-r--r--": 0644,
+		"-rwsr-xr-x": os.ModeSetuid | 0755,
+	} {
+		m, err := UnmarshalFileMode([]byte(in))
+		if err != nil {
+			t.Errorf("%q: %s", in, err)
+			continue
+		}
+		if m != exp {
+			t.Errorf("UnmarshalFileMode(%q) = %s, expected %s", in, m, exp)
+		}
+	}
+
+	for _, in := range []string{"", "0800", "17777", "0x755", "rwxr-xr-x"} {
+		if _, err := UnmarshalFileMode([]byte(in)); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
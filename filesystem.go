@@ -4,13 +4,23 @@ import (
 	"errors"
 	"io"
 	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"syscall"
 	"time"
 )
 
 var ErrNotImplemented = errors.New("not implemented")
 
+// ErrInvalidName - is returned when a path name contains bytes that are not
+// accepted by `ValidPath`.
+var ErrInvalidName = errors.New("invalid file name")
+
+// ErrIsDirectory - is returned when an operation that requires a regular file
+// is given a directory.
+var ErrIsDirectory = errors.New("is a directory")
+
 type ReadOnlyFiler interface {
 	Open(name string) (io.ReadCloser, error)
 }
@@ -99,94 +109,213 @@ type SymlinkFileSystem interface {
 
 // ExtendFiler adds the FileSystem convenience functions to any Filer implementation.
 func ExtendFiler(filer Filer) FileSystem {
-	return &fs{"/", filer}
+	return ExtendFilerWithOptions(filer)
+}
+
+// ExtendFilerWithOptions is like ExtendFiler, but the behavior of the returned
+// FileSystem can be adjusted with options. The initial working directory is
+// the virtual root "/" on every platform, regardless of the host separator;
+// OS backed wrappers can change it with WithInitialDir.
+func ExtendFilerWithOptions(filer Filer, opts ...Option) FileSystem {
+	fs := &fs{cwd: "/", filer: filer, validate: true}
+	for _, opt := range opts {
+		opt(fs)
+	}
+	if fs.cwd != "/" {
+		fs.cwd = Clean(fs, fs.cwd)
+	}
+	return fs
+}
+
+// Option configures the FileSystem returned by ExtendFilerWithOptions.
+type Option func(*fs)
+
+// WithPathValidation enables or disables checking path names with ValidPath
+// before they are passed to the Filer. Validation is enabled by default;
+// disable it for backends that legitimately accept unusual bytes in names.
+func WithPathValidation(enabled bool) Option {
+	return func(fs *fs) {
+		fs.validate = enabled
+	}
+}
+
+// WithInitialDir sets the initial working directory, which is "/" by default.
+// It has no effect if the Filer tracks its own working directory.
+func WithInitialDir(dir string) Option {
+	return func(fs *fs) {
+		fs.cwd = dir
+	}
+}
+
+// WithSeparator overrides the path separator reported by `Separator`, which
+// otherwise comes from the Filer or defaults to `filepath.Separator`. Paths
+// resolved against the working directory and the directories created by
+// `MkdirAll` use the same separator.
+func WithSeparator(sep uint8) Option {
+	return func(fs *fs) {
+		fs.sep = sep
+	}
+}
+
+// SyncOnClose causes files opened for writing, with `O_WRONLY` or `O_RDWR`, to
+// be synced automatically when they are closed. An error from `Sync` is
+// returned by `Close`, after the file has been closed.
+func SyncOnClose() Option {
+	return func(fs *fs) {
+		fs.syncOnClose = true
+	}
+}
+
+// EnforceAccessMode causes every file to be wrapped with EnforceFlags, so
+// reading from a write-only handle or writing to a read-only handle fails
+// even if the Filer does not check the access mode itself.
+func EnforceAccessMode() Option {
+	return func(fs *fs) {
+		fs.enforce = true
+	}
+}
+
+// WithUmask causes the permission bits in `mask` to be cleared from the mode
+// passed to the Filer whenever a file or directory is created, so that
+// `Create`, `OpenFile`, `Mkdir`, and `MkdirAll` produce the same modes on
+// every backend, whether or not it applies a umask of its own.
+func WithUmask(mask os.FileMode) Option {
+	return func(fs *fs) {
+		fs.umask = mask & os.ModePerm
+	}
+}
+
+// syncCloseFile - calls `Sync` before `Close`.
+type syncCloseFile struct {
+	File
+}
+
+func (f *syncCloseFile) Close() error {
+	return CloseSync(f.File)
+}
+
+// ValidPath returns an *os.PathError wrapping ErrInvalidName if name contains
+// a NUL byte or any other ASCII control character, and nil otherwise. Such
+// names are rejected because backends may truncate or misinterpret them.
+func ValidPath(name string) error {
+	for i := 0; i < len(name); i++ {
+		if c := name[i]; c < 0x20 || c == 0x7f {
+			return &os.PathError{Op: "validpath", Path: name, Err: ErrInvalidName}
+		}
+	}
+	return nil
 }
 
 type fs struct {
-	cwd   string
-	filer Filer
+	cwd         string
+	filer       Filer
+	sep         uint8
+	validate    bool
+	syncOnClose bool
+	enforce     bool
+	umask       os.FileMode
+	sym         SymLinker // set by ExtendSymlinkFiler
+}
+
+// wrap - applies the per-handle options to a file opened with `flag`.
+func (fs *fs) wrap(f File, err error, flag int) (File, error) {
+	if err != nil {
+		return f, err
+	}
+	if fs.enforce {
+		f = EnforceFlags(f, Flags(flag))
+	}
+	if fs.syncOnClose && Flags(flag).IsWritable() {
+		f = &syncCloseFile{f}
+	}
+	return f, nil
+}
+
+// validPath - checks name with ValidPath if validation is enabled, reporting
+// any error as op.
+func (fs *fs) validPath(op, name string) error {
+	if !fs.validate || ValidPath(name) == nil {
+		return nil
+	}
+	return &os.PathError{Op: op, Path: name, Err: ErrInvalidName}
 }
 
 func (fs *fs) OpenFile(name string, flag int, perm os.FileMode) (f File, err error) {
-	if !filepath.IsAbs(name) {
-		if _, ok := fs.filer.(dirnavigator); !ok {
-			name = filepath.Clean(filepath.Join(fs.cwd, name))
-		}
+	if err := fs.validPath("open", name); err != nil {
+		return &InvalidFile{Path: name, Err: err}, err
+	}
+	name = fs.path(name)
+	if err := fs.checkNotDir(name, flag); err != nil {
+		return &InvalidFile{Path: name, Err: err}, err
 	}
-	return fs.filer.OpenFile(name, flag, perm)
+	f, err = fs.filer.OpenFile(name, flag, perm&^fs.umask)
+	return fs.wrap(f, err, flag)
+}
+
+// checkNotDir - returns an `*os.PathError` wrapping `ErrIsDirectory` if
+// `flag` opens `name` for writing or truncation and `name` is a directory.
+// Some Filers do not check this themselves, and writing to a directory handle
+// may corrupt the directory.
+func (fs *fs) checkNotDir(name string, flag int) error {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_TRUNC) == 0 {
+		return nil
+	}
+	if info, err := fs.filer.Stat(name); err == nil && info.IsDir() {
+		return &os.PathError{Op: "open", Path: name, Err: ErrIsDirectory}
+	}
+	return nil
 }
 
 func (fs *fs) Mkdir(name string, perm os.FileMode) error {
-	if !filepath.IsAbs(name) {
-		if _, ok := fs.filer.(dirnavigator); !ok {
-			name = filepath.Clean(filepath.Join(fs.cwd, name))
-		}
+	if err := fs.validPath("mkdir", name); err != nil {
+		return err
 	}
-	return fs.filer.Mkdir(name, perm)
+	return fs.filer.Mkdir(fs.path(name), perm&^fs.umask)
 }
 
 func (fs *fs) Remove(name string) error {
-	if !filepath.IsAbs(name) {
-		if _, ok := fs.filer.(dirnavigator); !ok {
-			name = filepath.Clean(filepath.Join(fs.cwd, name))
-		}
+	if err := fs.validPath("remove", name); err != nil {
+		return err
 	}
-	return fs.filer.Remove(name)
+	return fs.filer.Remove(fs.path(name))
 }
 
 func (fs *fs) Rename(oldpath, newpath string) error {
-	if !filepath.IsAbs(oldpath) {
-		if _, ok := fs.filer.(dirnavigator); !ok {
-			oldpath = filepath.Clean(filepath.Join(fs.cwd, oldpath))
-		}
+	if err := fs.validPath("rename", oldpath); err != nil {
+		return linkError("rename", oldpath, newpath, err)
 	}
-	if !filepath.IsAbs(newpath) {
-		if _, ok := fs.filer.(dirnavigator); !ok {
-			newpath = filepath.Clean(filepath.Join(fs.cwd, newpath))
-		}
+	if err := fs.validPath("rename", newpath); err != nil {
+		return linkError("rename", oldpath, newpath, err)
 	}
+	oldpath = fs.path(oldpath)
+	newpath = fs.path(newpath)
 
-	return fs.filer.Rename(oldpath, newpath)
+	return linkError("rename", oldpath, newpath, fs.filer.Rename(oldpath, newpath))
 }
 
 func (fs *fs) Stat(name string) (os.FileInfo, error) {
-	if !filepath.IsAbs(name) {
-		if _, ok := fs.filer.(dirnavigator); !ok {
-			name = filepath.Clean(filepath.Join(fs.cwd, name))
-		}
+	if err := fs.validPath("stat", name); err != nil {
+		return nil, err
 	}
-	return fs.filer.Stat(name)
+	return fs.filer.Stat(fs.path(name))
 }
 
 func (fs *fs) Chmod(name string, mode os.FileMode) error {
-	if !filepath.IsAbs(name) {
-		if _, ok := fs.filer.(dirnavigator); !ok {
-			name = filepath.Clean(filepath.Join(fs.cwd, name))
-		}
-	}
-	return fs.filer.Chmod(name, mode)
+	return fs.filer.Chmod(fs.path(name), mode)
 }
 
 func (fs *fs) Chtimes(name string, atime time.Time, mtime time.Time) error {
-	if !filepath.IsAbs(name) {
-		if _, ok := fs.filer.(dirnavigator); !ok {
-			name = filepath.Clean(filepath.Join(fs.cwd, name))
-		}
-	}
-
-	return fs.filer.Chtimes(name, atime, mtime)
+	return fs.filer.Chtimes(fs.path(name), atime, mtime)
 }
 
 func (fs *fs) Chown(name string, uid, gid int) error {
-	if !filepath.IsAbs(name) {
-		if _, ok := fs.filer.(dirnavigator); !ok {
-			name = filepath.Clean(filepath.Join(fs.cwd, name))
-		}
-	}
-	return fs.filer.Chown(name, uid, gid)
+	return fs.filer.Chown(fs.path(name), uid, gid)
 }
 
 func (fs *fs) Separator() uint8 {
+	if fs.sep != 0 {
+		return fs.sep
+	}
 	if filer, ok := fs.filer.(separator); ok {
 		return filer.Separator()
 	}
@@ -217,13 +346,23 @@ func (fs *fs) Chdir(dir string) error {
 	if !info.IsDir() {
 		return &os.PathError{Op: "chdir", Path: dir, Err: errors.New("not a directory")}
 	}
-	fs.cwd = filepath.Clean(dir)
+	fs.cwd = fs.path(dir)
 	return nil
 }
 
+// Getwd - returns the working directory, cleaned. A working directory reported
+// by the Filer that is not virtual-absolute is an error, so the result is
+// always safe to join paths onto.
 func (fs *fs) Getwd() (dir string, err error) {
 	if filer, ok := fs.filer.(dirnavigator); ok {
-		return filer.Getwd()
+		dir, err = filer.Getwd()
+		if err != nil {
+			return "", err
+		}
+		if !IsAbs(dir) {
+			return "", &os.PathError{Op: "getwd", Path: dir, Err: errors.New("working directory is not absolute")}
+		}
+		return Clean(fs, dir), nil
 	}
 	return fs.cwd, nil
 }
@@ -237,122 +376,158 @@ func (fs *fs) TempDir() string {
 }
 
 func (fs *fs) Open(name string) (File, error) {
-	if filer, ok := fs.filer.(opener); ok {
-		return filer.Open(name)
+	if err := fs.validPath("open", name); err != nil {
+		return &InvalidFile{Path: name, Err: err}, err
 	}
-	if !filepath.IsAbs(name) {
-		if _, ok := fs.filer.(dirnavigator); !ok {
-			name = filepath.Clean(filepath.Join(fs.cwd, name))
-		}
+	if filer, ok := fs.filer.(opener); ok {
+		f, err := filer.Open(name)
+		return fs.wrap(f, err, os.O_RDONLY)
 	}
-	return fs.filer.OpenFile(name, os.O_RDONLY, 0)
+	name = fs.path(name)
+	f, err := fs.filer.OpenFile(name, os.O_RDONLY, 0)
+	return fs.wrap(f, err, os.O_RDONLY)
 }
 
 func (fs *fs) Create(name string) (File, error) {
-	if filer, ok := fs.filer.(creator); ok {
-		return filer.Create(name)
+	if err := fs.validPath("open", name); err != nil {
+		return &InvalidFile{Path: name, Err: err}, err
 	}
-	if !filepath.IsAbs(name) {
-		if _, ok := fs.filer.(dirnavigator); !ok {
-			name = filepath.Clean(filepath.Join(fs.cwd, name))
-		}
+	flag := os.O_CREATE | os.O_RDWR | os.O_TRUNC
+	if err := fs.checkNotDir(fs.path(name), flag); err != nil {
+		return &InvalidFile{Path: name, Err: err}, err
+	}
+	if filer, ok := fs.filer.(creator); ok && fs.umask == 0 {
+		f, err := filer.Create(name)
+		return fs.wrap(f, err, flag)
 	}
-	return fs.filer.OpenFile(name, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0666)
+	name = fs.path(name)
+	f, err := fs.filer.OpenFile(name, flag, 0666&^fs.umask)
+	return fs.wrap(f, err, flag)
 }
 
 func (fs *fs) MkdirAll(name string, perm os.FileMode) error {
 	if filer, ok := fs.filer.(mkaller); ok {
-		return filer.MkdirAll(name, perm)
-	}
-	if !filepath.IsAbs(name) {
-		if _, ok := fs.filer.(dirnavigator); !ok {
-			name = filepath.Clean(filepath.Join(fs.cwd, name))
-		}
+		return filer.MkdirAll(name, perm&^fs.umask)
 	}
+	name = fs.path(name)
 
-	path := string(fs.Separator())
-	for _, p := range strings.Split(name, string(fs.Separator())) {
+	sep := fs.Separator()
+	var dir string
+	if IsAbs(name) {
+		dir = "/"
+	}
+	for _, p := range strings.Split(toSlash(sep, name), "/") {
 		if p == "" {
 			continue
 		}
-		path = filepath.Join(path, p)
-		if path == "/" {
+		dir = path.Join(dir, p)
+		name := fromSlash(sep, dir)
+		if info, err := fs.filer.Stat(name); err == nil {
+			if !info.IsDir() {
+				return &os.PathError{Op: "mkdir", Path: name, Err: syscall.ENOTDIR}
+			}
 			continue
 		}
-		fs.Mkdir(path, perm)
+		if err := fs.Mkdir(name, perm); err != nil && !errors.Is(err, os.ErrExist) {
+			return err
+		}
 	}
-
 	return nil
 }
 
+// removeFrame - is a directory being emptied by `removeAll`.
+type removeFrame struct {
+	path  string
+	names []string // names listed but not yet removed
+}
+
+// removeAll - removes `path` and any children it contains. Directories are
+// emptied with an explicit stack rather than recursion, so the depth of the
+// tree is not limited by the call stack. The full listing of each directory
+// is read with `ReaddirnamesAll` before any of its entries are removed, and a
+// directory is removed only after all of its contents have been removed.
 func (fs *fs) removeAll(path string) (err error) {
-	// open the file
-	var f File
-	f, err = fs.Open(path) // .filer.Open(path)
+	info, err := fs.lstat(path)
 	if err != nil {
 		return err
 	}
+	if !info.IsDir() {
+		return fs.filer.Remove(path)
+	}
 
-	// defer close with error checking, and remove the file after closing.
-	defer func() {
+	var stack []removeFrame
+	push := func(path string) error {
+		f, err := fs.filer.OpenFile(path, os.O_RDONLY, 0)
 		if err != nil {
-			f.Close()
-			// return err
+			return err
 		}
-		closer := f.Close()
-		err = fs.filer.Remove(path)
-		if err == nil {
-			err = closer
+		names, err := ReaddirnamesAll(f)
+		f.Close()
+		if err != nil {
+			return err
 		}
-		// return err
-		return
-	}()
-
-	// get FileInfo
-	info, err := f.Stat()
-	if err != nil {
-		return err
+		stack = append(stack, removeFrame{path: path, names: names})
+		return nil
 	}
-
-	// if it's not a directory remove it and return
-	if !info.IsDir() {
-		return fs.Remove(path)
+	if err := push(path); err != nil {
+		return err
 	}
 
-	// get and loop through each directory entry calling remove all recursively.
-	err = nil
-	var names []string
-	for err != io.EOF {
-		names, err = f.Readdirnames(512)
-		if err != nil && len(names) == 0 {
-			if err != io.EOF {
+	for len(stack) > 0 {
+		top := &stack[len(stack)-1]
+		if len(top.names) == 0 {
+			// the directory is empty; remove it.
+			stack = stack[:len(stack)-1]
+			if err := fs.filer.Remove(top.path); err != nil {
 				return err
 			}
+			continue
 		}
-		for _, name := range names {
-			if name == "." || name == ".." {
+
+		name := top.names[0]
+		top.names = top.names[1:]
+		if name == "." || name == ".." {
+			continue
+		}
+		child := Join(fs, top.path, name)
+		info, err := fs.lstat(child)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
 				continue
 			}
-			err := fs.removeAll(filepath.Join(path, name))
-			if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if err := push(child); err != nil {
 				return err
 			}
+			continue
+		}
+		if err := fs.filer.Remove(child); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
 		}
 	}
-
 	return nil
 }
 
+// lstat - calls `Lstat` on the `Filer` if it has one, so that `removeAll`
+// removes symbolic links rather than following them, otherwise `Stat`.
+func (fs *fs) lstat(name string) (os.FileInfo, error) {
+	if fs.sym != nil {
+		return fs.sym.Lstat(name)
+	}
+	if filer, ok := fs.filer.(lstater); ok {
+		return filer.Lstat(name)
+	}
+	return fs.filer.Stat(name)
+}
+
 func (fs *fs) RemoveAll(name string) (err error) {
 
 	if filer, ok := fs.filer.(remover); ok {
 		return filer.RemoveAll(name)
 	}
-	if !filepath.IsAbs(name) {
-		if _, ok := fs.filer.(dirnavigator); !ok {
-			name = filepath.Clean(filepath.Join(fs.cwd, name))
-		}
-	}
+	name = fs.path(name)
 	return fs.removeAll(name)
 }
 
@@ -360,21 +535,174 @@ func (fs *fs) Truncate(name string, size int64) error {
 	if filer, ok := fs.filer.(truncater); ok {
 		return filer.Truncate(name, size)
 	}
-	if !filepath.IsAbs(name) {
-		if _, ok := fs.filer.(dirnavigator); !ok {
-			name = filepath.Clean(filepath.Join(fs.cwd, name))
+	name = fs.path(name)
+
+	// truncating to zero only needs O_TRUNC, which works even with File
+	// implementations that can't shrink a file.
+	if size == 0 {
+		f, err := fs.filer.OpenFile(name, os.O_WRONLY|os.O_TRUNC, 0666)
+		if err != nil {
+			return err
 		}
+		return f.Close()
 	}
-
-	f, err := fs.filer.OpenFile(name, os.O_WRONLY|os.O_TRUNC, 0666)
+	f, err := fs.filer.OpenFile(name, os.O_WRONLY, 0666)
 	if err != nil {
 		return err
 	}
-	return f.Close()
+	err = f.Truncate(size)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Mmap - delegates to the `Filer` if it implements `Mmapper`, otherwise it
+// reads the region with `ReadAt`.
+func (fs *fs) Mmap(name string, offset, length int64) ([]byte, func() error, error) {
+	if filer, ok := fs.filer.(Mmapper); ok {
+		return filer.Mmap(fs.path(name), offset, length)
+	}
+	return readRegion(fs, name, offset, length)
+}
+
+// Sync - delegates to the `Filer` if it implements `Syncer`, otherwise it does
+// nothing.
+func (fs *fs) Sync() error {
+	if filer, ok := fs.filer.(Syncer); ok {
+		return filer.Sync()
+	}
+	return nil
+}
+
+// StatFS - delegates to the `Filer` if it implements `StatFSer`, otherwise it
+// returns an `*os.PathError` wrapping `ErrNotImplemented`.
+func (fs *fs) StatFS(name string) (FSStat, error) {
+	if filer, ok := fs.filer.(StatFSer); ok {
+		return filer.StatFS(fs.path(name))
+	}
+	return FSStat{}, &os.PathError{Op: "statfs", Path: name, Err: ErrNotImplemented}
+}
+
+// Getxattr - delegates to the `Filer` if it implements `XattrFiler`,
+// otherwise it returns an `*os.PathError` wrapping `ErrNotImplemented`.
+func (fs *fs) Getxattr(name, attr string) ([]byte, error) {
+	if filer, ok := fs.filer.(XattrFiler); ok {
+		return filer.Getxattr(fs.path(name), attr)
+	}
+	return nil, &os.PathError{Op: "getxattr", Path: name, Err: ErrNotImplemented}
+}
+
+// Setxattr - delegates to the `Filer` if it implements `XattrFiler`,
+// otherwise it returns an `*os.PathError` wrapping `ErrNotImplemented`.
+func (fs *fs) Setxattr(name, attr string, value []byte) error {
+	if filer, ok := fs.filer.(XattrFiler); ok {
+		return filer.Setxattr(fs.path(name), attr, value)
+	}
+	return &os.PathError{Op: "setxattr", Path: name, Err: ErrNotImplemented}
+}
+
+// Listxattr - delegates to the `Filer` if it implements `XattrFiler`,
+// otherwise it returns an `*os.PathError` wrapping `ErrNotImplemented`.
+func (fs *fs) Listxattr(name string) ([]string, error) {
+	if filer, ok := fs.filer.(XattrFiler); ok {
+		return filer.Listxattr(fs.path(name))
+	}
+	return nil, &os.PathError{Op: "listxattr", Path: name, Err: ErrNotImplemented}
+}
+
+// Removexattr - delegates to the `Filer` if it implements `XattrFiler`,
+// otherwise it returns an `*os.PathError` wrapping `ErrNotImplemented`.
+func (fs *fs) Removexattr(name, attr string) error {
+	if filer, ok := fs.filer.(XattrFiler); ok {
+		return filer.Removexattr(fs.path(name), attr)
+	}
+	return &os.PathError{Op: "removexattr", Path: name, Err: ErrNotImplemented}
+}
+
+// OpenTmp - delegates to the `Filer` if it implements `TmpFiler`, otherwise it
+// emulates an anonymous file with a named one.
+func (fs *fs) OpenTmp(dir string, perm os.FileMode) (File, error) {
+	if filer, ok := fs.filer.(TmpFiler); ok {
+		return filer.OpenTmp(fs.path(dir), perm)
+	}
+	return openTmp(fs, dir, perm)
+}
+
+// Fallocate - delegates to the `Filer` if it implements `Preallocator`,
+// otherwise it extends the file with `Truncate`.
+func (fs *fs) Fallocate(name string, size int64) error {
+	if filer, ok := fs.filer.(Preallocator); ok {
+		return filer.Fallocate(fs.path(name), size)
+	}
+	return truncateGrow(fs, name, size)
+}
+
+// Watch - delegates to the `Filer` if it implements `Watcher`, otherwise it
+// returns an `*os.PathError` wrapping `ErrNotImplemented`. The cancel function
+// may be called more than once, as with the package level `Watch`.
+func (fs *fs) Watch(name string) (<-chan FsEvent, func() error, error) {
+	if filer, ok := fs.filer.(Watcher); ok {
+		events, cancel, err := filer.Watch(fs.path(name))
+		if err != nil {
+			return nil, nil, err
+		}
+		return events, onceFunc(cancel), nil
+	}
+	return nil, nil, &os.PathError{Op: "watch", Path: name, Err: ErrNotImplemented}
+}
+
+// StatMany - delegates to the `Filer` if it implements `BulkStater`,
+// otherwise it calls `Stat` for each name concurrently.
+func (fs *fs) StatMany(names []string) ([]os.FileInfo, []error) {
+	if filer, ok := fs.filer.(BulkStater); ok {
+		paths := make([]string, len(names))
+		for i, name := range names {
+			paths[i] = fs.path(name)
+		}
+		return filer.StatMany(paths)
+	}
+	return statMany(fs, names)
+}
+
+// path - resolves `name` against the working directory unless the `Filer`
+// tracks its own.
+func (fs *fs) path(name string) string {
+	if !IsAbs(name) {
+		if _, ok := fs.filer.(dirnavigator); !ok {
+			name = Join(fs, fs.cwd, name)
+		}
+	}
+	return name
+}
+
+// Glob - delegates to the `Filer` if it implements `Glob`, otherwise it
+// matches `pattern` by enumerating each directory level.
+func (fs *fs) Glob(pattern string) ([]string, error) {
+	if filer, ok := fs.filer.(globber); ok {
+		return filer.Glob(pattern)
+	}
+	return glob(fs, pattern)
 }
 
 type FastWalkFunc func(string, os.FileMode) error
 
+// linkError - normalizes a non-nil `err` returned by a two path operation to
+// an `*os.LinkError`. Errors that are already an `*os.LinkError` are returned
+// unchanged, and the cause of an `*os.PathError` is re-wrapped.
+func linkError(op, oldpath, newpath string, err error) error {
+	if err == nil {
+		return nil
+	}
+	switch e := err.(type) {
+	case *os.LinkError:
+		return e
+	case *os.PathError:
+		err = e.Err
+	}
+	return &os.LinkError{Op: op, Old: oldpath, New: newpath, Err: err}
+}
+
 // interfaces for easy method typing
 
 type opener interface {
@@ -410,6 +738,10 @@ type temper interface {
 	TempDir() string
 }
 
+type lstater interface {
+	Lstat(name string) (os.FileInfo, error)
+}
+
 type truncater interface {
 	Truncate(name string, size int64) error
 }
@@ -0,0 +1,518 @@
+package absfs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+// sloppyFiler - returns bare errors from `Rename`.
+type sloppyFiler struct {
+	*mockFiler
+}
+
+var errSloppy = errors.New("sloppy error")
+
+func (f *sloppyFiler) Rename(oldpath, newpath string) error {
+	return errSloppy
+}
+
+func TestRenameLinkError(t *testing.T) {
+	fsys := ExtendFiler(&sloppyFiler{newMockFiler()})
+	if err := fsys.Chdir("/"); err != nil {
+		t.Fatal(err)
+	}
+	err := fsys.Rename("a", "b")
+	var lerr *os.LinkError
+	if !errors.As(err, &lerr) {
+		t.Fatalf("got %#v, expected an *os.LinkError", err)
+	}
+	if lerr.Op != "rename" || lerr.Old != "/a" || lerr.New != "/b" || lerr.Err != errSloppy {
+		t.Errorf("unexpected error %#v", lerr)
+	}
+
+	// errors that are already *os.LinkError are unchanged
+	fsys = newTestFS()
+	err = fsys.Rename("/missing", "/b")
+	if !errors.As(err, &lerr) || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %#v", err)
+	}
+}
+
+func TestOpenDirForWriting(t *testing.T) {
+	fsys := newTestFS()
+	if err := fsys.Mkdir("/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, flag := range []int{os.O_WRONLY, os.O_RDWR, os.O_RDONLY | os.O_TRUNC, os.O_WRONLY | os.O_CREATE} {
+		f, err := fsys.OpenFile("/dir", flag, 0644)
+		var perr *os.PathError
+		if !errors.As(err, &perr) || perr.Op != "open" || !errors.Is(err, ErrIsDirectory) {
+			t.Errorf("flag %s: got %v, expected an open error wrapping %v", Flags(flag), err, ErrIsDirectory)
+		}
+		if _, ok := f.(*InvalidFile); !ok {
+			t.Errorf("flag %s: got %T, expected an *InvalidFile", Flags(flag), f)
+		}
+	}
+
+	f, err := fsys.Create("/dir")
+	var perr *os.PathError
+	if !errors.As(err, &perr) || perr.Op != "open" || !errors.Is(err, ErrIsDirectory) {
+		t.Errorf("Create: got %v, expected an open error wrapping %v", err, ErrIsDirectory)
+	}
+	if _, ok := f.(*InvalidFile); !ok {
+		t.Errorf("Create: got %T, expected an *InvalidFile", f)
+	}
+
+	f, err = fsys.OpenFile("/dir", os.O_RDONLY, 0)
+	if err != nil {
+		t.Fatalf("read only: %v", err)
+	}
+	f.Close()
+}
+
+func TestGetwd(t *testing.T) {
+	m := &navFiler{mockFiler: newMockFiler()}
+	fsys := ExtendFiler(m)
+
+	for wd, expected := range map[string]string{
+		"/a/../b":  "/b",
+		"/a//b/./": "/a/b",
+		"/":        "/",
+	} {
+		m.cwd = wd
+		if dir, err := fsys.Getwd(); err != nil || dir != expected {
+			t.Errorf("%q: got %q, %v, expected %q", wd, dir, err, expected)
+		}
+	}
+
+	m.cwd = "relative/dir"
+	if dir, err := fsys.Getwd(); err == nil {
+		t.Errorf("got %q, expected an error for a relative working directory", dir)
+	}
+
+	// relative Chdir on the wrapper's own working directory
+	fsys = newTestFS()
+	if err := fsys.MkdirAll("/a/b", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Chdir("/a"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Chdir("b/../b"); err != nil {
+		t.Fatal(err)
+	}
+	if dir, err := fsys.Getwd(); err != nil || dir != "/a/b" {
+		t.Errorf("got %q, %v, expected %q", dir, err, "/a/b")
+	}
+}
+
+func TestValidPath(t *testing.T) {
+	fsys := newTestFS()
+	bad := "/bad\x00name"
+	if _, err := fsys.Stat(bad); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("Stat: got %v, expected %v", err, ErrInvalidName)
+	}
+	if _, err := fsys.OpenFile(bad, os.O_CREATE|os.O_RDWR, 0666); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("OpenFile: got %v, expected %v", err, ErrInvalidName)
+	}
+	if _, err := fsys.Open(bad); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("Open: got %v, expected %v", err, ErrInvalidName)
+	}
+	if _, err := fsys.Create(bad); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("Create: got %v, expected %v", err, ErrInvalidName)
+	}
+	if err := fsys.Mkdir(bad, 0755); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("Mkdir: got %v, expected %v", err, ErrInvalidName)
+	}
+	if err := fsys.Remove(bad); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("Remove: got %v, expected %v", err, ErrInvalidName)
+	}
+	var lerr *os.LinkError
+	if err := fsys.Rename("/a", bad); !errors.As(err, &lerr) || !errors.Is(err, ErrInvalidName) {
+		t.Errorf("Rename: got %v, expected an *os.LinkError wrapping %v", err, ErrInvalidName)
+	}
+
+	fsys = ExtendFilerWithOptions(newMockFiler(), WithPathValidation(false))
+	if err := fsys.Mkdir("/new\nline", 0755); err != nil {
+		t.Errorf("validation disabled: %v", err)
+	}
+
+	// names are checked before they reach Open and Create on the Filer
+	fsys = ExtendFiler(openCreateFiler{newMockFiler()})
+	if _, err := fsys.Open(bad); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("Filer Open: got %v, expected %v", err, ErrInvalidName)
+	}
+	if _, err := fsys.Create(bad); !errors.Is(err, ErrInvalidName) {
+		t.Errorf("Filer Create: got %v, expected %v", err, ErrInvalidName)
+	}
+}
+
+// openCreateFiler - implements `Open` and `Create`, and fails if either is
+// reached.
+type openCreateFiler struct {
+	*mockFiler
+}
+
+func (m openCreateFiler) Open(name string) (File, error) {
+	return nil, errors.New("open reached the filer")
+}
+
+func (m openCreateFiler) Create(name string) (File, error) {
+	return nil, errors.New("create reached the filer")
+}
+
+func FuzzValidPath(f *testing.F) {
+	for _, seed := range []string{"", "/", "/a/b", "a\x00b", "tab\there", "\x7f", "ünïcödé"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		valid := true
+		for i := 0; i < len(name); i++ {
+			if name[i] < 0x20 || name[i] == 0x7f {
+				valid = false
+			}
+		}
+		err := ValidPath(name)
+		if valid != (err == nil) {
+			t.Fatalf("ValidPath(%q) = %v", name, err)
+		}
+		if err != nil && !errors.Is(err, ErrInvalidName) {
+			t.Fatalf("ValidPath(%q) = %v, expected %v", name, err, ErrInvalidName)
+		}
+		if !valid {
+			if _, err := newTestFS().Stat(name); !errors.Is(err, ErrInvalidName) {
+				t.Fatalf("Stat(%q) = %v, expected %v", name, err, ErrInvalidName)
+			}
+		}
+	})
+}
+
+func TestRemoveAll(t *testing.T) {
+	fsys := newTestFS()
+	if err := fsys.MkdirAll("/tree/a/b", 0755); err != nil {
+		t.Fatal(err)
+	}
+	createFiles(t, fsys, "/tree/f", "/tree/a/g", "/tree/a/b/h", "/keep")
+	for i := 0; i < 1100; i++ {
+		createFiles(t, fsys, fmt.Sprintf("/tree/a/wide%04d", i))
+	}
+
+	if err := fsys.RemoveAll("/tree"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fsys.Stat("/tree"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrNotExist)
+	}
+	if _, err := fsys.Stat("/keep"); err != nil {
+		t.Error(err)
+	}
+
+	if err := fsys.RemoveAll("/keep"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fsys.Stat("/keep"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrNotExist)
+	}
+}
+
+func TestRemoveAllDeep(t *testing.T) {
+	fsys := newTestFS()
+	deep := "/deep" + strings.Repeat("/d", 1000)
+	if err := fsys.MkdirAll(deep, 0755); err != nil {
+		t.Fatal(err)
+	}
+	createFiles(t, fsys, deep+"/f")
+
+	if err := fsys.RemoveAll("/deep"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fsys.Stat("/deep"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrNotExist)
+	}
+}
+
+// syncCountFiler - counts calls to `Sync` on the files it opens.
+type syncCountFiler struct {
+	*mockFiler
+	syncs int
+	err   error
+}
+
+func (m *syncCountFiler) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	f, err := m.mockFiler.OpenFile(name, flag, perm)
+	if err != nil {
+		return f, err
+	}
+	return &syncCountFile{f, m}, nil
+}
+
+type syncCountFile struct {
+	File
+	m *syncCountFiler
+}
+
+func (f *syncCountFile) Sync() error {
+	f.m.syncs++
+	return f.m.err
+}
+
+func TestSyncOnClose(t *testing.T) {
+	m := &syncCountFiler{mockFiler: newMockFiler()}
+	fsys := ExtendFilerWithOptions(m, SyncOnClose())
+
+	f, err := fsys.Create("/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	f, err = fsys.OpenFile("/a", os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if m.syncs != 2 {
+		t.Errorf("got %d syncs, expected 2", m.syncs)
+	}
+
+	f, err = fsys.Open("/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if m.syncs != 2 {
+		t.Errorf("got %d syncs, expected read-only files not to be synced", m.syncs)
+	}
+
+	m.err = errors.New("sync failed")
+	f, err = fsys.OpenFile("/a", os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != m.err {
+		t.Errorf("got %v, expected %v", err, m.err)
+	}
+
+	m.syncs = 0
+	f, err = ExtendFiler(m).Create("/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if m.syncs != 0 {
+		t.Errorf("got %d syncs, expected none without the option", m.syncs)
+	}
+}
+
+func TestWithUmask(t *testing.T) {
+	fsys := ExtendFilerWithOptions(newMockFiler(), WithUmask(022))
+
+	mode := func(name string) os.FileMode {
+		t.Helper()
+		info, err := fsys.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return info.Mode().Perm()
+	}
+
+	f, err := fsys.Create("/created")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if m := mode("/created"); m != 0644 {
+		t.Errorf("Create: got %s, expected %s", m, os.FileMode(0644))
+	}
+
+	f, err = CreateMode(fsys, "/script", 0777)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if m := mode("/script"); m != 0755 {
+		t.Errorf("CreateMode: got %s, expected %s", m, os.FileMode(0755))
+	}
+
+	if err := fsys.Mkdir("/dir", 0777); err != nil {
+		t.Fatal(err)
+	}
+	if m := mode("/dir"); m != 0755 {
+		t.Errorf("Mkdir: got %s, expected %s", m, os.FileMode(0755))
+	}
+	if err := fsys.MkdirAll("/a/b", 0770); err != nil {
+		t.Fatal(err)
+	}
+	if m := mode("/a/b"); m != 0750 {
+		t.Errorf("MkdirAll: got %s, expected %s", m, os.FileMode(0750))
+	}
+
+	// modes already inside the umask are unchanged
+	f, err = CreateMode(fsys, "/private", 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if m := mode("/private"); m != 0600 {
+		t.Errorf("got %s, expected %s", m, os.FileMode(0600))
+	}
+}
+
+// backslashFiler - is a `mockFiler` whose paths are separated by backslashes.
+// Names containing a slash are rejected.
+type backslashFiler struct {
+	*mockFiler
+}
+
+// slash - converts `name` to the slash separated form used by the mock.
+func (m backslashFiler) slash(op, name string) (string, error) {
+	if strings.Contains(name, "/") {
+		return "", &os.PathError{Op: op, Path: name, Err: ErrInvalidName}
+	}
+	return toSlash('\\', name), nil
+}
+
+func (m backslashFiler) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	p, err := m.slash("open", name)
+	if err != nil {
+		return &InvalidFile{Path: name, Err: err}, err
+	}
+	return m.mockFiler.OpenFile(p, flag, perm)
+}
+
+func (m backslashFiler) Mkdir(name string, perm os.FileMode) error {
+	p, err := m.slash("mkdir", name)
+	if err != nil {
+		return err
+	}
+	return m.mockFiler.Mkdir(p, perm)
+}
+
+func (m backslashFiler) Stat(name string) (os.FileInfo, error) {
+	p, err := m.slash("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return m.mockFiler.Stat(p)
+}
+
+func TestExtendFilerWithOptions(t *testing.T) {
+	m := newMockFiler()
+	if err := m.Mkdir("/home", 0755); err != nil {
+		t.Fatal(err)
+	}
+	fsys := ExtendFilerWithOptions(backslashFiler{m}, WithInitialDir("/home/"), WithSeparator('\\'))
+
+	if wd, err := fsys.Getwd(); err != nil || wd != `\home` {
+		t.Errorf("Getwd: got %q, %v, expected %q", wd, err, `\home`)
+	}
+	if sep := fsys.Separator(); sep != '\\' {
+		t.Errorf("Separator: got %q, expected %q", sep, '\\')
+	}
+	f, err := fsys.Create("file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if _, err := m.Stat("/home/file"); err != nil {
+		t.Errorf("relative paths should resolve against the initial directory: %v", err)
+	}
+
+	for _, name := range []string{`\x\y`, `sub\dir`} {
+		if err := fsys.MkdirAll(name, 0755); err != nil {
+			t.Fatalf("MkdirAll(%q): %v", name, err)
+		}
+	}
+	for _, name := range []string{"/x/y", "/home/sub/dir"} {
+		if info, err := m.Stat(name); err != nil || !info.IsDir() {
+			t.Errorf("MkdirAll did not create %q: %v", name, err)
+		}
+	}
+	if err := fsys.MkdirAll(`\home\file\dir`, 0755); !errors.Is(err, syscall.ENOTDIR) {
+		t.Errorf("MkdirAll through a file: got %v, expected %v", err, syscall.ENOTDIR)
+	}
+}
+
+// mkdirFailFiler - is a `mockFiler` that refuses to create directories.
+type mkdirFailFiler struct {
+	*mockFiler
+}
+
+func (m mkdirFailFiler) Mkdir(name string, perm os.FileMode) error {
+	return &os.PathError{Op: "mkdir", Path: name, Err: os.ErrPermission}
+}
+
+func TestMkdirAllError(t *testing.T) {
+	fsys := ExtendFiler(mkdirFailFiler{newMockFiler()})
+	if err := fsys.MkdirAll("/a/b", 0755); !errors.Is(err, os.ErrPermission) {
+		t.Errorf("got %v, expected %v", err, os.ErrPermission)
+	}
+}
+
+// noTruncateFiler - returns files whose `Truncate` method always fails.
+type noTruncateFiler struct {
+	*mockFiler
+}
+
+func (m noTruncateFiler) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	f, err := m.mockFiler.OpenFile(name, flag, perm)
+	if err != nil {
+		return f, err
+	}
+	return noTruncateFile{f}, nil
+}
+
+type noTruncateFile struct {
+	File
+}
+
+func (f noTruncateFile) Truncate(size int64) error {
+	return &os.PathError{Op: "truncate", Path: f.Name(), Err: ErrNotImplemented}
+}
+
+func TestTruncate(t *testing.T) {
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/file", "0123456789")
+
+	if err := fsys.Truncate("/file", 4); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, fsys, "/file"); got != "0123" {
+		t.Errorf("shrink: got %q, expected %q", got, "0123")
+	}
+	if err := fsys.Truncate("/file", 6); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, fsys, "/file"); got != "0123\x00\x00" {
+		t.Errorf("grow: got %q, expected %q", got, "0123\x00\x00")
+	}
+	if err := fsys.Truncate("/file", 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, fsys, "/file"); got != "" {
+		t.Errorf("zero: got %q, expected an empty file", got)
+	}
+
+	// truncating to zero doesn't need File.Truncate
+	fsys = ExtendFiler(noTruncateFiler{newMockFiler()})
+	writeTestFile(t, fsys, "/file", "data")
+	if err := fsys.Truncate("/file", 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, fsys, "/file"); got != "" {
+		t.Errorf("got %q, expected an empty file", got)
+	}
+	if err := fsys.Truncate("/file", 2); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("got %v, expected %v", err, ErrNotImplemented)
+	}
+}
@@ -0,0 +1,15 @@
+//go:build windows
+
+package absfs
+
+import "testing"
+
+func TestInitialDirIsVirtualRoot(t *testing.T) {
+	wd, err := newTestFS().Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if wd != "/" {
+		t.Errorf("got %q, expected the virtual root %q", wd, "/")
+	}
+}
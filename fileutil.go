@@ -0,0 +1,199 @@
+package absfs
+
+import (
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"math/rand"
+	"os"
+	"strconv"
+)
+
+// Append - writes `data` to the end of the file `name`, creating it with mode
+// 0666 if it does not exist. The file is opened with
+// `O_WRONLY|O_APPEND|O_CREATE`, but since not every `Filer` honors `O_APPEND`
+// the handle is also positioned at `Stat().Size()` before writing.
+func Append(fsys FileSystem, name string, data []byte) error {
+	f, err := fsys.OpenFile(name, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		return err
+	}
+
+	info, err := f.Stat()
+	if err == nil {
+		_, err = f.Seek(info.Size(), io.SeekStart)
+	}
+	if err == nil {
+		_, err = f.Write(data)
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// ReadFile - reads the file `name` and returns its contents. A successful
+// call returns a nil error, not `io.EOF`.
+func ReadFile(fsys FileSystem, name string) ([]byte, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var size int
+	if info, err := f.Stat(); err == nil && info.Size() > 0 {
+		size = int(info.Size())
+	}
+	data := make([]byte, 0, size+512)
+	for {
+		if len(data) == cap(data) {
+			data = append(data, 0)[:len(data)]
+		}
+		n, err := f.Read(data[len(data):cap(data)])
+		data = data[:len(data)+n]
+		if err != nil {
+			if err == io.EOF {
+				err = nil
+			}
+			return data, err
+		}
+	}
+}
+
+// WriteFile - writes `data` to the file `name`, creating it with `perm` if
+// it does not exist and truncating it otherwise. An error from `Close` is
+// returned if the write succeeded.
+func WriteFile(fsys FileSystem, name string, data []byte, perm os.FileMode) error {
+	f, err := fsys.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// ReadString - is like `ReadFile` but returns the contents as a string.
+func ReadString(fsys FileSystem, name string) (string, error) {
+	data, err := ReadFile(fsys, name)
+	return string(data), err
+}
+
+// WriteString - is like `WriteFile` but writes the string `content`.
+func WriteString(fsys FileSystem, name, content string, perm os.FileMode) error {
+	return WriteFile(fsys, name, []byte(content), perm)
+}
+
+// CreateMode - is like `fsys.Create` but creates the file with mode `perm`
+// rather than 0666. The mode is still subject to any umask applied by the
+// FileSystem, such as one set with `WithUmask`.
+func CreateMode(fsys FileSystem, name string, perm os.FileMode) (File, error) {
+	return fsys.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_TRUNC, perm)
+}
+
+// OpenFlags - is like `fsys.OpenFile` but takes the flags as `Flags`, for
+// example as parsed by `ParseFlags`, converting them with `Flags.ToOS`.
+func OpenFlags(fsys FileSystem, name string, flags Flags, perm os.FileMode) (File, error) {
+	return fsys.OpenFile(name, flags.ToOS(), perm)
+}
+
+// SafeCreate - creates a temporary file in the directory of `name` and returns
+// a handle to it together with a `commit` function. `commit` syncs and closes
+// the handle and then renames the temporary file to `name`, so readers never
+// observe a partially written file. Because the temporary file lives in the
+// same directory, the `Rename` stays within one backend.
+//
+// If the handle is closed without calling `commit`, the temporary file is
+// removed. Closing the handle after a successful `commit` is a no-op, so
+// `defer f.Close()` is safe. If `commit` fails the temporary file is removed.
+func SafeCreate(fsys FileSystem, name string, perm os.FileMode) (File, func() error, error) {
+	dir, base := Split(fsys, name)
+	f, tmp, err := createTemp(fsys, dir, "."+base+".tmp", perm)
+	if err != nil {
+		return nil, nil, err
+	}
+	sf := &safeFile{File: f, fsys: fsys, tmp: tmp, name: name}
+	return sf, sf.commit, nil
+}
+
+// createTemp - creates a new file in `dir` whose name begins with `prefix`
+// followed by a random number, and returns it with its name.
+func createTemp(fsys FileSystem, dir, prefix string, perm os.FileMode) (File, string, error) {
+	for i := 0; ; i++ {
+		name := Join(fsys, dir, prefix+strconv.FormatUint(uint64(rand.Uint32()), 10))
+		f, err := fsys.OpenFile(name, os.O_RDWR|os.O_CREATE|os.O_EXCL, perm)
+		if errors.Is(err, os.ErrExist) && i < 10000 {
+			continue
+		}
+		if err != nil {
+			return nil, "", err
+		}
+		return f, name, nil
+	}
+}
+
+// safeFile - a temporary file that is renamed into place by `commit` or
+// removed by `Close`.
+type safeFile struct {
+	File
+	fsys FileSystem
+	tmp  string
+	name string
+	done bool
+}
+
+func (f *safeFile) commit() error {
+	if f.done {
+		return &os.PathError{Op: "commit", Path: f.name, Err: os.ErrClosed}
+	}
+	f.done = true
+	err := CloseSync(f.File)
+	if err == nil {
+		err = f.fsys.Rename(f.tmp, f.name)
+	}
+	if err != nil {
+		f.fsys.Remove(f.tmp)
+	}
+	return err
+}
+
+func (f *safeFile) Close() error {
+	if f.done {
+		return nil
+	}
+	f.done = true
+	err := f.File.Close()
+	if rerr := f.fsys.Remove(f.tmp); err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// WriteDedup - streams `r` to the file `name`, creating or truncating it, and
+// hashes the data as it is written with a hash from `hasher`. It returns the
+// hex encoded digest and the number of bytes written, so content addressed
+// stores can be built without reading the data a second time. If writing or
+// closing the file fails, the file is removed.
+func WriteDedup(fsys FileSystem, name string, r io.Reader, hasher func() hash.Hash) (digest string, n int64, err error) {
+	f, err := fsys.Create(name)
+	if err != nil {
+		return "", 0, err
+	}
+
+	h := hasher()
+	buf := copyBufPool.Get().(*[]byte)
+	n, err = io.CopyBuffer(io.MultiWriter(f, h), r, *buf)
+	copyBufPool.Put(buf)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		fsys.Remove(name)
+		return "", n, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), n, nil
+}
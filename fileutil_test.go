@@ -0,0 +1,229 @@
+package absfs
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAppend(t *testing.T) {
+	// the mockFiler does not honor O_APPEND
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/log", "start\n")
+
+	for i := 0; i < 3; i++ {
+		if err := Append(fsys, "/log", []byte("a\n")); err != nil {
+			t.Fatal(err)
+		}
+		if err := Append(fsys, "/log", []byte("b\n")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := Append(fsys, "/new", []byte("created")); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "start\na\nb\na\nb\na\nb\n"
+	if got := readTestFile(t, fsys, "/log"); got != expected {
+		t.Errorf("got %q, expected %q", got, expected)
+	}
+	if got := readTestFile(t, fsys, "/new"); got != "created" {
+		t.Errorf("got %q, expected %q", got, "created")
+	}
+}
+
+func TestAppendInterleaved(t *testing.T) {
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/log", "start\n")
+
+	// the mockFiler ignores O_APPEND, so each handle is given append
+	// semantics with ExtendSeekableAppend
+	var handles [2]File
+	for i := range handles {
+		f, err := fsys.OpenFile("/log", os.O_WRONLY|os.O_APPEND, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		handles[i] = ExtendSeekableAppend(seekableOnly{f})
+	}
+
+	var expected strings.Builder
+	expected.WriteString("start\n")
+	for i := 0; i < 3; i++ {
+		for j, f := range handles {
+			line := fmt.Sprintf("handle %d write %d\n", j, i)
+			if _, err := f.WriteString(line); err != nil {
+				t.Fatal(err)
+			}
+			expected.WriteString(line)
+		}
+		line := fmt.Sprintf("append %d\n", i)
+		if err := Append(fsys, "/log", []byte(line)); err != nil {
+			t.Fatal(err)
+		}
+		expected.WriteString(line)
+	}
+
+	if got := readTestFile(t, fsys, "/log"); got != expected.String() {
+		t.Errorf("got %q, expected %q", got, expected.String())
+	}
+}
+
+func TestSafeCreate(t *testing.T) {
+	fsys := newTestFS()
+	if err := fsys.Mkdir("/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, fsys, "/dir/config", "old")
+
+	f, commit, err := SafeCreate(fsys, "/dir/config", 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString("new"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, fsys, "/dir/config"); got != "old" {
+		t.Errorf("before commit got %q, expected %q", got, "old")
+	}
+	if err := commit(); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, fsys, "/dir/config"); got != "new" {
+		t.Errorf("after commit got %q, expected %q", got, "new")
+	}
+	if names := listTestDir(t, fsys, "/dir"); !reflect.DeepEqual(names, []string{"config"}) {
+		t.Errorf("got %v, expected the temporary file to be gone", names)
+	}
+	if err := commit(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("second commit: got %v, expected %v", err, os.ErrClosed)
+	}
+}
+
+func TestSafeCreateAbandon(t *testing.T) {
+	fsys := newTestFS()
+	if err := fsys.Mkdir("/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	f, commit, err := SafeCreate(fsys, "/dir/data", 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("partial"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if names := listTestDir(t, fsys, "/dir"); len(names) != 0 {
+		t.Errorf("got %v, expected an empty directory", names)
+	}
+	if err := commit(); err == nil {
+		t.Error("commit after Close should fail")
+	}
+}
+
+// errReader - returns `data` followed by `err`.
+type errReader struct {
+	data []byte
+	err  error
+}
+
+func (r *errReader) Read(p []byte) (int, error) {
+	if len(r.data) == 0 {
+		return 0, r.err
+	}
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+func TestWriteDedup(t *testing.T) {
+	fsys := newTestFS()
+	data := strings.Repeat("content", 10000)
+
+	digest, n, err := WriteDedup(fsys, "/blob", strings.NewReader(data), sha256.New)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := sha256.Sum256([]byte(data))
+	if expected := hex.EncodeToString(sum[:]); digest != expected {
+		t.Errorf("got digest %s, expected %s", digest, expected)
+	}
+	if n != int64(len(data)) {
+		t.Errorf("got %d bytes written, expected %d", n, len(data))
+	}
+	if got := readTestFile(t, fsys, "/blob"); got != data {
+		t.Errorf("wrote %d bytes of content, expected %d", len(got), len(data))
+	}
+
+	errRead := errors.New("read failed")
+	_, _, err = WriteDedup(fsys, "/partial", &errReader{[]byte("partial"), errRead}, sha256.New)
+	if err != errRead {
+		t.Errorf("got %v, expected %v", err, errRead)
+	}
+	if _, err := fsys.Stat("/partial"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected the partial file to be removed", err)
+	}
+}
+
+func TestReadWriteFile(t *testing.T) {
+	fsys := newTestFS()
+	large := strings.Repeat("0123456789", 1000)
+
+	if err := WriteFile(fsys, "/file", []byte(large), 0644); err != nil {
+		t.Fatal(err)
+	}
+	data, err := ReadFile(fsys, "/file")
+	if err != nil || string(data) != large {
+		t.Errorf("ReadFile: got %d bytes, %v, expected %d", len(data), err, len(large))
+	}
+
+	if err := WriteString(fsys, "/file", "short", 0644); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := ReadString(fsys, "/file"); err != nil || s != "short" {
+		t.Errorf("ReadString: got %q, %v, expected the file to be truncated", s, err)
+	}
+
+	if err := WriteString(fsys, "/empty", "", 0644); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := ReadString(fsys, "/empty"); err != nil || s != "" {
+		t.Errorf("ReadString: got %q, %v", s, err)
+	}
+
+	if _, err := ReadString(fsys, "/missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrNotExist)
+	}
+}
+
+func TestOpenFlags(t *testing.T) {
+	fsys := newTestFS()
+	flags, err := ParseFlags("O_WRONLY|O_CREATE|O_EXCL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := OpenFlags(fsys, "/file", flags, 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("data"); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if got := readTestFile(t, fsys, "/file"); got != "data" {
+		t.Errorf("got %q, expected %q", got, "data")
+	}
+	if _, err := OpenFlags(fsys, "/file", flags, 0600); !errors.Is(err, os.ErrExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrExist)
+	}
+}
@@ -9,7 +9,9 @@ import (
 const (
 	O_ACCESS = 0x3 // masks the access mode (O_RDONLY, O_WRONLY, or O_RDWR)
 
-	// Exactly one of O_RDONLY, O_WRONLY, or O_RDWR must be specified.
+	// Exactly one of O_RDONLY, O_WRONLY, or O_RDWR must be specified. They are
+	// values, not bits, and O_RDONLY is 0, so test them by masking with O_ACCESS
+	// and comparing, never by AND-ing against O_RDONLY.
 	O_RDONLY = os.O_RDONLY // open the file read-only.
 	O_WRONLY = os.O_WRONLY // open the file write-only.
 	O_RDWR   = os.O_RDWR   // open the file read-write.
@@ -26,28 +28,102 @@ const (
 // functions.
 type Flags int
 
+// FlagsFromOS - converts `flag`, an `os.O_*` value as passed to `OpenFile`,
+// to `Flags`. The `O_*` constants of this package have the same values as
+// those of the `os` package, so the conversion keeps every bit, including
+// platform specific flags that `String` does not name.
+func FlagsFromOS(flag int) Flags {
+	return Flags(flag)
+}
+
+// ToOS - returns `f` as the `int` expected by `OpenFile`. It is the inverse
+// of `FlagsFromOS`.
+func (f Flags) ToOS() int {
+	return int(f)
+}
+
+// AccessMode - returns the access mode of `f`, one of `O_RDONLY`, `O_WRONLY`,
+// or `O_RDWR`. Compare the result instead of testing access mode bits
+// directly; `O_RDONLY` is 0, so `f&O_RDONLY != 0` is always false.
+func (f Flags) AccessMode() int {
+	return int(f) & O_ACCESS
+}
+
+// IsReadable - reports whether `f` opens a file for reading, that is with
+// `O_RDONLY` or `O_RDWR`.
+func (f Flags) IsReadable() bool {
+	access := f.AccessMode()
+	return access == O_RDONLY || access == O_RDWR
+}
+
+// IsWritable - reports whether `f` opens a file for writing, that is with
+// `O_WRONLY` or `O_RDWR`.
+func (f Flags) IsWritable() bool {
+	access := f.AccessMode()
+	return access == O_WRONLY || access == O_RDWR
+}
+
+// Has - reports whether all of the non-access flags in `flag`, such as
+// `O_CREATE|O_TRUNC`, are set in `f`. Access mode bits in `flag` are ignored;
+// use `AccessMode`, `IsReadable`, or `IsWritable` for those.
+func (f Flags) Has(flag int) bool {
+	flag &^= O_ACCESS
+	return int(f)&flag == flag
+}
+
 // String - returns the list of values set in a `Flag` separated by "|".
 func (f Flags) String() string {
-	var out []string
-	// flags := int(f)
+	var b strings.Builder
+	b.Grow(flagsStringMax)
+
 	switch int(f) & O_ACCESS {
 	case O_RDONLY:
-		out = append(out, "O_RDONLY")
+		b.WriteString("O_RDONLY")
 	case O_RDWR:
-		out = append(out, "O_RDWR")
+		b.WriteString("O_RDWR")
 	case O_WRONLY:
-		out = append(out, "O_WRONLY")
+		b.WriteString("O_WRONLY")
 	}
 
-	names := []string{"O_APPEND", "O_CREATE", "O_EXCL", "O_SYNC", "O_TRUNC"}
-	for i, flag := range []Flags{Flags(O_APPEND), Flags(O_CREATE), Flags(O_EXCL), Flags(O_SYNC), Flags(O_TRUNC)} {
+	for i, flag := range flagValues {
 		if (flag & f) != 0 {
-			out = append(out, names[i])
+			if b.Len() > 0 {
+				b.WriteByte('|')
+			}
+			b.WriteString(flagNames[i])
 		}
 	}
-	return strings.Join(out, "|")
+	return b.String()
 }
 
+// MarshalText - implements `encoding.TextMarshaler` using the form returned by
+// `String`, so `Flags` values are stored as e.g. "O_RDWR|O_CREATE" in JSON or
+// YAML.
+func (f Flags) MarshalText() ([]byte, error) {
+	return []byte(f.String()), nil
+}
+
+// UnmarshalText - implements `encoding.TextUnmarshaler` using `ParseFlags`,
+// and returns its error for unrecognized input.
+func (f *Flags) UnmarshalText(text []byte) error {
+	flags, err := ParseFlags(string(text))
+	if err != nil {
+		return err
+	}
+	*f = flags
+	return nil
+}
+
+// flagNames and flagValues - list the non-access flags in the order they are
+// written by `String`.
+var flagNames = [...]string{"O_APPEND", "O_CREATE", "O_EXCL", "O_SYNC", "O_TRUNC"}
+
+var flagValues = [...]Flags{Flags(O_APPEND), Flags(O_CREATE), Flags(O_EXCL), Flags(O_SYNC), Flags(O_TRUNC)}
+
+// flagsStringMax - is the length of the longest string returned by `String`,
+// "O_WRONLY|O_APPEND|O_CREATE|O_EXCL|O_SYNC|O_TRUNC".
+const flagsStringMax = 48
+
 // ParseFlags - parses a string of flags separated by "|" and returns a `Flags`
 // value and an error.
 //
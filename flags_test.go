@@ -1,6 +1,8 @@
 package absfs
 
 import (
+	"encoding/json"
+	"os"
 	"strings"
 	"testing"
 )
@@ -79,3 +81,121 @@ func combine(out chan []testType, input []testType, size int, values ...testType
 		combine(out, input[j+1:], size, append(values, v)...)
 	}
 }
+
+func BenchmarkFlagsString(b *testing.B) {
+	f := Flags(O_WRONLY | O_APPEND | O_CREATE | O_EXCL | O_SYNC | O_TRUNC)
+	if len(f.String()) != flagsStringMax {
+		b.Fatalf("got length %d, expected %d", len(f.String()), flagsStringMax)
+	}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = f.String()
+	}
+}
+
+func TestFlagsText(t *testing.T) {
+	type config struct {
+		Flags Flags `json:"flags"`
+	}
+	in := config{Flags(O_RDWR | O_CREATE | O_TRUNC)}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := `{"flags":"O_RDWR|O_CREATE|O_TRUNC"}`; string(data) != expected {
+		t.Errorf("got %s, expected %s", data, expected)
+	}
+
+	var out config
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out != in {
+		t.Errorf("got %s, expected %s", out.Flags, in.Flags)
+	}
+
+	if err := json.Unmarshal([]byte(`{"flags":"O_RDWR|O_BOGUS"}`), &out); err == nil {
+		t.Error("expected an error for an unrecognized flag")
+	}
+}
+
+func TestFlagsAccessMode(t *testing.T) {
+	tests := []struct {
+		flags    Flags
+		access   int
+		readable bool
+		writable bool
+	}{
+		{Flags(O_RDONLY), O_RDONLY, true, false},
+		{Flags(O_WRONLY), O_WRONLY, false, true},
+		{Flags(O_RDWR), O_RDWR, true, true},
+		{Flags(O_RDONLY | O_CREATE | O_TRUNC), O_RDONLY, true, false},
+		{Flags(O_RDWR | O_APPEND), O_RDWR, true, true},
+	}
+	for _, tt := range tests {
+		if got := tt.flags.AccessMode(); got != tt.access {
+			t.Errorf("%s: AccessMode() = %d, expected %d", tt.flags, got, tt.access)
+		}
+		if got := tt.flags.IsReadable(); got != tt.readable {
+			t.Errorf("%s: IsReadable() = %t, expected %t", tt.flags, got, tt.readable)
+		}
+		if got := tt.flags.IsWritable(); got != tt.writable {
+			t.Errorf("%s: IsWritable() = %t, expected %t", tt.flags, got, tt.writable)
+		}
+	}
+
+	f := Flags(O_RDWR | O_CREATE | O_TRUNC)
+	if !f.Has(O_CREATE) || !f.Has(O_CREATE|O_TRUNC) {
+		t.Errorf("%s: expected O_CREATE and O_TRUNC to be set", f)
+	}
+	if f.Has(O_CREATE | O_EXCL) {
+		t.Errorf("%s: O_EXCL is not set", f)
+	}
+	if !Flags(O_WRONLY).Has(O_RDONLY) {
+		t.Error("access mode bits should be ignored by Has")
+	}
+}
+
+func TestFlagsOS(t *testing.T) {
+	f := FlagsFromOS(os.O_RDWR | os.O_CREATE)
+	if f.String() != "O_RDWR|O_CREATE" {
+		t.Errorf("got %q, expected %q", f.String(), "O_RDWR|O_CREATE")
+	}
+	if f.ToOS() != os.O_RDWR|os.O_CREATE {
+		t.Errorf("got %#x, expected %#x", f.ToOS(), os.O_RDWR|os.O_CREATE)
+	}
+
+	parsed, err := ParseFlags("O_WRONLY|O_CREATE|O_EXCL")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.ToOS() != os.O_WRONLY|os.O_CREATE|os.O_EXCL {
+		t.Errorf("got %#x, expected %#x", parsed.ToOS(), os.O_WRONLY|os.O_CREATE|os.O_EXCL)
+	}
+	if FlagsFromOS(parsed.ToOS()) != parsed {
+		t.Errorf("%s did not round trip", parsed)
+	}
+}
+
+func FuzzFlagsRoundTrip(f *testing.F) {
+	for _, seed := range []int{O_RDONLY, O_WRONLY | O_APPEND, O_RDWR | O_CREATE | O_EXCL | O_SYNC | O_TRUNC} {
+		f.Add(seed)
+	}
+	var known Flags = O_ACCESS
+	for _, v := range flagValues {
+		known |= v
+	}
+	f.Fuzz(func(t *testing.T, v int) {
+		flags := Flags(v) & known
+		if int(flags)&O_ACCESS == O_ACCESS {
+			t.Skip("invalid access mode")
+		}
+		parsed, err := ParseFlags(flags.String())
+		if err != nil {
+			t.Fatalf("ParseFlags(%q): %v", flags.String(), err)
+		}
+		if parsed != flags {
+			t.Fatalf("ParseFlags(%q) = %#x, expected %#x", flags.String(), int(parsed), int(flags))
+		}
+	})
+}
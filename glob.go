@@ -0,0 +1,115 @@
+package absfs
+
+import (
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Glob - returns the names of all files in `fsys` matching `pattern`, or nil
+// if there is no matching file. The syntax of patterns is the same as in
+// `filepath.Match`, with the separator of `fsys` separating path elements.
+// Each directory level is enumerated with `ReaddirnamesAll`. The only possible
+// returned error is `filepath.ErrBadPattern`, when pattern is malformed.
+//
+// If `fsys` (or the `Filer` extended by `ExtendFiler`) implements
+// `Glob(pattern string) ([]string, error)` the call is delegated to it.
+func Glob(fsys FileSystem, pattern string) (matches []string, err error) {
+	if g, ok := fsys.(globber); ok {
+		return g.Glob(pattern)
+	}
+	return glob(fsys, pattern)
+}
+
+func glob(fsys FileSystem, pattern string) (matches []string, err error) {
+	sep := fsys.Separator()
+	matches, err = globSlash(fsys, toSlash(sep, pattern), 0)
+	for i, m := range matches {
+		matches[i] = fromSlash(sep, m)
+	}
+	return matches, err
+}
+
+// globSlash - implements `Glob` for a slash separated `pattern`.
+func globSlash(fsys FileSystem, pattern string, depth int) (matches []string, err error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, filepath.ErrBadPattern
+	}
+	if depth > 10000 {
+		return nil, filepath.ErrBadPattern
+	}
+	sep := fsys.Separator()
+	if !hasGlobMeta(pattern) {
+		if _, err := fsys.Stat(fromSlash(sep, pattern)); err != nil {
+			return nil, nil
+		}
+		return []string{pattern}, nil
+	}
+
+	dir, file := path.Split(pattern)
+	switch dir {
+	case "":
+		dir = "."
+	case "/":
+	default:
+		dir = dir[:len(dir)-1] // chop off trailing separator
+	}
+
+	if !hasGlobMeta(dir) {
+		return globDir(fsys, dir, file, nil)
+	}
+	if dir == pattern {
+		return nil, filepath.ErrBadPattern
+	}
+
+	dirMatches, err := globSlash(fsys, dir, depth+1)
+	if err != nil {
+		return nil, err
+	}
+	for _, d := range dirMatches {
+		matches, err = globDir(fsys, d, file, matches)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return matches, nil
+}
+
+// globDir - appends the entries of `dir` matching `pattern` to `matches`.
+// Errors reading `dir` are ignored.
+func globDir(fsys FileSystem, dir, pattern string, matches []string) ([]string, error) {
+	sep := fsys.Separator()
+	info, err := fsys.Stat(fromSlash(sep, dir))
+	if err != nil || !info.IsDir() {
+		return matches, nil
+	}
+	f, err := fsys.Open(fromSlash(sep, dir))
+	if err != nil {
+		return matches, nil
+	}
+	names, _ := ReaddirnamesAll(f)
+	f.Close()
+	sort.Strings(names)
+
+	for _, n := range names {
+		matched, err := path.Match(pattern, n)
+		if err != nil {
+			return matches, filepath.ErrBadPattern
+		}
+		if matched {
+			matches = append(matches, path.Join(dir, n))
+		}
+	}
+	return matches, nil
+}
+
+// hasGlobMeta - reports whether `pattern` contains any of the magic
+// characters recognized by `path.Match`.
+func hasGlobMeta(pattern string) bool {
+	return strings.ContainsAny(pattern, `*?[\`)
+}
+
+type globber interface {
+	Glob(pattern string) ([]string, error)
+}
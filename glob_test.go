@@ -0,0 +1,74 @@
+package absfs
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestGlob(t *testing.T) {
+	fsys := newTestFS()
+	for _, dir := range []string{"/a/x", "/a/y", "/b"} {
+		if err := fsys.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	createFiles(t, fsys, "/a/x/1.txt", "/a/x/2.go", "/a/y/3.txt", "/b/4.txt")
+
+	tests := []struct {
+		Pattern string
+		Exp     []string
+	}{
+		{"/a/x/*.txt", []string{"/a/x/1.txt"}},
+		{"/a/*/*.txt", []string{"/a/x/1.txt", "/a/y/3.txt"}},
+		{"/*/?.txt", []string{"/b/4.txt"}},
+		{"/a/[xy]/[0-2].*", []string{"/a/x/1.txt", "/a/x/2.go"}},
+		{"/b/4.txt", []string{"/b/4.txt"}},
+		{"/b/*.md", nil},
+		{"/missing/*", nil},
+	}
+	for _, test := range tests {
+		matches, err := Glob(fsys, test.Pattern)
+		if err != nil {
+			t.Errorf("%q: %v", test.Pattern, err)
+			continue
+		}
+		if !reflect.DeepEqual(matches, test.Exp) {
+			t.Errorf("%q: got %q, expected %q", test.Pattern, matches, test.Exp)
+		}
+	}
+
+	if err := fsys.Chdir("/a"); err != nil {
+		t.Fatal(err)
+	}
+	matches, err := Glob(fsys, "*/3.*")
+	if err != nil || !reflect.DeepEqual(matches, []string{"y/3.txt"}) {
+		t.Errorf("relative: got %q, %v", matches, err)
+	}
+
+	if _, err := Glob(fsys, "/a/["); err != filepath.ErrBadPattern {
+		t.Errorf("got %v, expected %v", err, filepath.ErrBadPattern)
+	}
+}
+
+// globFiler - is a `mockFiler` with a native `Glob`.
+type globFiler struct {
+	*mockFiler
+	patterns []string
+}
+
+func (f *globFiler) Glob(pattern string) ([]string, error) {
+	f.patterns = append(f.patterns, pattern)
+	return []string{"native"}, nil
+}
+
+func TestGlobDelegates(t *testing.T) {
+	filer := &globFiler{mockFiler: newMockFiler()}
+	matches, err := Glob(ExtendFiler(filer), "/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(matches, []string{"native"}) || len(filer.patterns) != 1 {
+		t.Errorf("Glob was not delegated: got %q", matches)
+	}
+}
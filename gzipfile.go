@@ -0,0 +1,177 @@
+package absfs
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"syscall"
+)
+
+// GzipReadFile - opens the gzip compressed file `name` and returns a read-only
+// File that yields the decompressed data.
+//
+// A compressed stream can only be decoded from the start, so seeking is
+// emulated: a seek forward decompresses and discards the data in between,
+// and a seek backward rewinds the underlying file and decompresses again from
+// the beginning, so the cost of a seek grows with the target offset. Seeking
+// relative to the end is not supported, since the decompressed size is not
+// known without reading the whole stream, and neither is `ReadAt`. Both
+// return an `*os.PathError` wrapping `ErrNotImplemented`. Writes fail with
+// `syscall.EBADF`. `Stat` describes the compressed file.
+func GzipReadFile(fsys FileSystem, name string) (File, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return f, err
+	}
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		err = &os.PathError{Op: "open", Path: name, Err: err}
+		return &InvalidFile{Path: name, Err: err}, err
+	}
+	return &gzipReadFile{File: f, zr: zr}, nil
+}
+
+type gzipReadFile struct {
+	File
+	zr     *gzip.Reader
+	offset int64
+}
+
+func (f *gzipReadFile) pathError(op string, err error) error {
+	return &os.PathError{Op: op, Path: f.File.Name(), Err: err}
+}
+
+func (f *gzipReadFile) Read(b []byte) (int, error) {
+	n, err := f.zr.Read(b)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *gzipReadFile) ReadAt(b []byte, off int64) (int, error) {
+	return 0, f.pathError("read", ErrNotImplemented)
+}
+
+func (f *gzipReadFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		return f.offset, f.pathError("seek", ErrNotImplemented)
+	default:
+		return f.offset, f.pathError("seek", syscall.EINVAL)
+	}
+	if offset < 0 {
+		return f.offset, f.pathError("seek", syscall.EINVAL)
+	}
+	if offset < f.offset {
+		if _, err := f.File.Seek(0, io.SeekStart); err != nil {
+			return f.offset, err
+		}
+		if err := f.zr.Reset(f.File); err != nil {
+			return f.offset, f.pathError("seek", err)
+		}
+		f.offset = 0
+	}
+	n, err := io.CopyN(io.Discard, f.zr, offset-f.offset)
+	f.offset += n
+	if err != nil && err != io.EOF {
+		return f.offset, f.pathError("seek", err)
+	}
+	// like a regular file, seeking past the end is allowed
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *gzipReadFile) Write(b []byte) (int, error) {
+	return 0, f.pathError("write", syscall.EBADF)
+}
+
+func (f *gzipReadFile) WriteAt(b []byte, off int64) (int, error) {
+	return 0, f.pathError("write", syscall.EBADF)
+}
+
+func (f *gzipReadFile) WriteString(s string) (int, error) {
+	return 0, f.pathError("write", syscall.EBADF)
+}
+
+func (f *gzipReadFile) Truncate(size int64) error {
+	return f.pathError("truncate", syscall.EBADF)
+}
+
+func (f *gzipReadFile) Close() error {
+	err := f.zr.Close()
+	if cerr := f.File.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// GzipCreate - creates or truncates the file `name` with mode `perm` and
+// returns a write-only File that gzip compresses the data written to it.
+// `Sync` flushes the data written so far as a complete compressed block
+// before syncing the file, and `Close` writes the gzip footer before closing
+// it; the file is not a valid gzip stream until `Close` has returned nil.
+//
+// Only sequential writes are supported: `WriteAt`, `Seek`, and `Truncate`
+// fail with `syscall.ESPIPE`, and reads fail with `syscall.EBADF`.
+func GzipCreate(fsys FileSystem, name string, perm os.FileMode) (File, error) {
+	f, err := fsys.OpenFile(name, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, perm)
+	if err != nil {
+		return f, err
+	}
+	return &gzipWriteFile{File: f, zw: gzip.NewWriter(f)}, nil
+}
+
+type gzipWriteFile struct {
+	File
+	zw *gzip.Writer
+}
+
+func (f *gzipWriteFile) pathError(op string, err error) error {
+	return &os.PathError{Op: op, Path: f.File.Name(), Err: err}
+}
+
+func (f *gzipWriteFile) Write(b []byte) (int, error) {
+	return f.zw.Write(b)
+}
+
+func (f *gzipWriteFile) WriteString(s string) (int, error) {
+	return f.zw.Write([]byte(s))
+}
+
+func (f *gzipWriteFile) WriteAt(b []byte, off int64) (int, error) {
+	return 0, f.pathError("write", syscall.ESPIPE)
+}
+
+func (f *gzipWriteFile) Seek(offset int64, whence int) (int64, error) {
+	return 0, f.pathError("seek", syscall.ESPIPE)
+}
+
+func (f *gzipWriteFile) Truncate(size int64) error {
+	return f.pathError("truncate", syscall.ESPIPE)
+}
+
+func (f *gzipWriteFile) Read(b []byte) (int, error) {
+	return 0, f.pathError("read", syscall.EBADF)
+}
+
+func (f *gzipWriteFile) ReadAt(b []byte, off int64) (int, error) {
+	return 0, f.pathError("read", syscall.EBADF)
+}
+
+func (f *gzipWriteFile) Sync() error {
+	if err := f.zw.Flush(); err != nil {
+		return err
+	}
+	return f.File.Sync()
+}
+
+func (f *gzipWriteFile) Close() error {
+	err := f.zw.Close()
+	if cerr := f.File.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
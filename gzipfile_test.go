@@ -0,0 +1,96 @@
+package absfs
+
+import (
+	"compress/gzip"
+	"errors"
+	"io"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestGzipFile(t *testing.T) {
+	fsys := newTestFS()
+	content := strings.Repeat("line of log output\n", 100)
+
+	w, err := GzipCreate(fsys, "/log.gz", 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.WriteString(content[:1000]); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Sync(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Write([]byte(content[1000:])); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := w.Seek(0, io.SeekStart); !errors.Is(err, syscall.ESPIPE) {
+		t.Errorf("Seek: got %v, expected %v", err, syscall.ESPIPE)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the file is a standard gzip stream
+	raw, err := fsys.Open("/log.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	zr, err := gzip.NewReader(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, err := io.ReadAll(zr); err != nil || string(data) != content {
+		t.Errorf("gzip.Reader: got %d bytes, %v", len(data), err)
+	}
+	raw.Close()
+
+	r, err := GzipReadFile(fsys, "/log.gz")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	if err != nil || string(data) != content {
+		t.Fatalf("got %d bytes, %v", len(data), err)
+	}
+
+	buf := make([]byte, 4)
+	for _, seek := range []struct {
+		offset int64
+		whence int
+		pos    int64
+	}{
+		{19, io.SeekStart, 19},   // backward
+		{19, io.SeekCurrent, 42}, // forward, after reading 4 bytes
+		{0, io.SeekStart, 0},
+	} {
+		pos, err := r.Seek(seek.offset, seek.whence)
+		if err != nil || pos != seek.pos {
+			t.Fatalf("Seek(%d, %d): got %d, %v, expected %d", seek.offset, seek.whence, pos, err, seek.pos)
+		}
+		if _, err := io.ReadFull(r, buf); err != nil {
+			t.Fatal(err)
+		}
+		if expected := content[pos : pos+4]; string(buf) != expected {
+			t.Errorf("after Seek to %d: got %q, expected %q", pos, buf, expected)
+		}
+	}
+
+	if _, err := r.Seek(0, io.SeekEnd); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("Seek from end: got %v, expected %v", err, ErrNotImplemented)
+	}
+	if _, err := r.ReadAt(buf, 0); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("ReadAt: got %v, expected %v", err, ErrNotImplemented)
+	}
+	if _, err := r.Write(buf); !errors.Is(err, syscall.EBADF) {
+		t.Errorf("Write: got %v, expected %v", err, syscall.EBADF)
+	}
+
+	writeTestFile(t, fsys, "/plain", "not compressed")
+	if _, err := GzipReadFile(fsys, "/plain"); err == nil {
+		t.Error("expected an error opening an uncompressed file")
+	}
+}
@@ -0,0 +1,37 @@
+package absfs
+
+import (
+	"net/http"
+	"os"
+	"path"
+	"strings"
+)
+
+// AsHTTPFS - returns an `http.FileSystem` serving the contents of `fsys`, so
+// it can back an `http.FileServer` directly. The slash separated names used by
+// `net/http` are cleaned, so they can't escape the root, and converted to the
+// separator of `fsys`. Names containing the separator of `fsys`, when it is
+// not a slash, are rejected, as `http.Dir` does.
+//
+// absfs `File` handles already implement `http.File`. `http.FileServer`
+// serves the "index.html" of a directory when there is one, and otherwise
+// lists the directory with `Readdir`.
+func AsHTTPFS(fsys FileSystem) http.FileSystem {
+	return httpFS{fsys}
+}
+
+type httpFS struct {
+	fsys FileSystem
+}
+
+func (h httpFS) Open(name string) (http.File, error) {
+	sep := h.fsys.Separator()
+	if sep != '/' && strings.IndexByte(name, sep) >= 0 {
+		return nil, &os.PathError{Op: "open", Path: name, Err: ErrInvalidName}
+	}
+	f, err := h.fsys.Open(fromSlash(sep, path.Clean("/"+name)))
+	if err != nil {
+		return nil, pathErrorAt(err, name)
+	}
+	return f, nil
+}
@@ -0,0 +1,66 @@
+package absfs
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAsHTTPFS(t *testing.T) {
+	fsys := newTestFS()
+	for name, content := range map[string]string{
+		"/site/index.html":     "<h1>home</h1>",
+		"/site/css/style.css":  "body {}",
+		"/site/docs/guide.txt": "read me",
+	} {
+		if err := fsys.MkdirAll(Dir(fsys, name), 0755); err != nil {
+			t.Fatal(err)
+		}
+		writeTestFile(t, fsys, name, content)
+	}
+	server := httptest.NewServer(http.FileServer(AsHTTPFS(fsys)))
+	defer server.Close()
+
+	get := func(p string) (int, string) {
+		t.Helper()
+		resp, err := http.Get(server.URL + p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, string(body)
+	}
+
+	if code, body := get("/site/css/style.css"); code != 200 || body != "body {}" {
+		t.Errorf("file: got %d %q", code, body)
+	}
+	if code, body := get("/site/"); code != 200 || body != "<h1>home</h1>" {
+		t.Errorf("index: got %d %q", code, body)
+	}
+	if code, body := get("/site/docs/"); code != 200 || !strings.Contains(body, "guide.txt") {
+		t.Errorf("listing: got %d %q", code, body)
+	}
+	if code, _ := get("/site/missing.txt"); code != 404 {
+		t.Errorf("missing: got %d, expected 404", code)
+	}
+}
+
+func TestAsHTTPFSClean(t *testing.T) {
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/file", "data")
+	hfs := AsHTTPFS(fsys)
+	for _, name := range []string{"/file", "file", "/../../file", "/dir/../file"} {
+		f, err := hfs.Open(name)
+		if err != nil {
+			t.Errorf("%q: %v", name, err)
+			continue
+		}
+		f.Close()
+	}
+}
@@ -13,6 +13,24 @@ type InvalidFile struct {
 	// Path should be set to the file path provided to the function opening the
 	// file (i.e. OpenFile, Open, Create).
 	Path string
+
+	// Err is the cause wrapped in the `*os.PathError` values returned by
+	// InvalidFile methods, typically the error that prevented the file from
+	// being opened. If Err is nil `syscall.EBADF` is used.
+	Err error
+}
+
+// cause - returns the error to wrap in returned `*os.PathError` values. If
+// `Err` is itself an `*os.PathError` it's underlying error is used, so that
+// the operation and path are not reported twice.
+func (f *InvalidFile) cause() error {
+	if f.Err == nil {
+		return syscall.EBADF
+	}
+	if perr, ok := f.Err.(*os.PathError); ok {
+		return perr.Err
+	}
+	return f.Err
 }
 
 // Name - returns the name of the file path provided to the function that
@@ -21,14 +39,16 @@ func (f *InvalidFile) Name() string {
 	return f.Path
 }
 
-// Read - returns an *os.PathError indicating a bad file handle.
+// Read - returns an *os.PathError wrapping `Err`, or indicating a bad file
+// handle if `Err` is nil.
 func (f *InvalidFile) Read(p []byte) (int, error) {
-	return 0, &os.PathError{Op: "read", Path: f.Name(), Err: syscall.EBADF}
+	return 0, &os.PathError{Op: "read", Path: f.Name(), Err: f.cause()}
 }
 
-// Write - returns an *os.PathError indicating a bad file handle.
+// Write - returns an *os.PathError wrapping `Err`, or indicating a bad file
+// handle if `Err` is nil.
 func (f *InvalidFile) Write(p []byte) (int, error) {
-	return 0, &os.PathError{Op: "write", Path: f.Name(), Err: syscall.EBADF}
+	return 0, &os.PathError{Op: "write", Path: f.Name(), Err: f.cause()}
 }
 
 // Close - does nothing and does not return an error.
@@ -36,47 +56,56 @@ func (f *InvalidFile) Close() error {
 	return nil
 }
 
-// Sync - returns an *os.PathError indicating a bad file handle.
+// Sync - returns an *os.PathError wrapping `Err`, or indicating a bad file
+// handle if `Err` is nil.
 func (f *InvalidFile) Sync() error {
-	return &os.PathError{Op: "sync", Path: f.Name(), Err: syscall.EBADF}
+	return &os.PathError{Op: "sync", Path: f.Name(), Err: f.cause()}
 }
 
-// Stat - returns an *os.PathError indicating a bad file handle.
+// Stat - returns an *os.PathError wrapping `Err`, or indicating a bad file
+// handle if `Err` is nil.
 func (f *InvalidFile) Stat() (os.FileInfo, error) {
-	return nil, &os.PathError{Op: "stat", Path: f.Name(), Err: syscall.EBADF}
+	return nil, &os.PathError{Op: "stat", Path: f.Name(), Err: f.cause()}
 }
 
-// Readdir - returns an *os.PathError indicating a bad file handle.
+// Readdir - returns an *os.PathError wrapping `Err`, or indicating a bad file
+// handle if `Err` is nil.
 func (f *InvalidFile) Readdir(int) ([]os.FileInfo, error) {
-	return nil, &os.PathError{Op: "readdir", Path: f.Name(), Err: syscall.EBADF}
+	return nil, &os.PathError{Op: "readdir", Path: f.Name(), Err: f.cause()}
 }
 
-// Seek - returns an *os.PathError indicating a bad file handle.
+// Seek - returns an *os.PathError wrapping `Err`, or indicating a bad file
+// handle if `Err` is nil.
 func (f *InvalidFile) Seek(offset int64, whence int) (ret int64, err error) {
-	return 0, &os.PathError{Op: "seek", Path: f.Name(), Err: syscall.EBADF}
+	return 0, &os.PathError{Op: "seek", Path: f.Name(), Err: f.cause()}
 }
 
-// ReadAt - returns an *os.PathError indicating a bad file handle.
+// ReadAt - returns an *os.PathError wrapping `Err`, or indicating a bad file
+// handle if `Err` is nil.
 func (f *InvalidFile) ReadAt(b []byte, off int64) (n int, err error) {
-	return 0, &os.PathError{Op: "read", Path: f.Name(), Err: syscall.EBADF}
+	return 0, &os.PathError{Op: "read", Path: f.Name(), Err: f.cause()}
 }
 
-// WriteAt - returns an *os.PathError indicating a bad file handle.
+// WriteAt - returns an *os.PathError wrapping `Err`, or indicating a bad file
+// handle if `Err` is nil.
 func (f *InvalidFile) WriteAt(b []byte, off int64) (n int, err error) {
-	return 0, &os.PathError{Op: "write", Path: f.Name(), Err: syscall.EBADF}
+	return 0, &os.PathError{Op: "write", Path: f.Name(), Err: f.cause()}
 }
 
-// WriteString - returns an *os.PathError indicating a bad file handle.
+// WriteString - returns an *os.PathError wrapping `Err`, or indicating a bad file
+// handle if `Err` is nil.
 func (f *InvalidFile) WriteString(s string) (n int, err error) {
-	return 0, &os.PathError{Op: "write", Path: f.Name(), Err: syscall.EBADF}
+	return 0, &os.PathError{Op: "write", Path: f.Name(), Err: f.cause()}
 }
 
-// Truncate - returns an *os.PathError indicating a bad file handle.
+// Truncate - returns an *os.PathError wrapping `Err`, or indicating a bad file
+// handle if `Err` is nil.
 func (f *InvalidFile) Truncate(size int64) error {
-	return &os.PathError{Op: "truncate", Path: f.Name(), Err: syscall.EBADF}
+	return &os.PathError{Op: "truncate", Path: f.Name(), Err: f.cause()}
 }
 
-// Readdirnames - returns an *os.PathError indicating a bad file handle.
+// Readdirnames - returns an *os.PathError wrapping `Err`, or indicating a bad file
+// handle if `Err` is nil.
 func (f *InvalidFile) Readdirnames(n int) (names []string, err error) {
-	return nil, &os.PathError{Op: "readdirnames", Path: f.Name(), Err: syscall.EBADF}
+	return nil, &os.PathError{Op: "readdirnames", Path: f.Name(), Err: f.cause()}
 }
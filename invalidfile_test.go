@@ -0,0 +1,36 @@
+package absfs
+
+import (
+	"errors"
+	iofs "io/fs"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestInvalidFileErrors(t *testing.T) {
+	f := &InvalidFile{Path: "/file"}
+	if _, err := f.Read(nil); !errors.Is(err, syscall.EBADF) {
+		t.Errorf("got %v, expected EBADF", err)
+	}
+
+	f = &InvalidFile{Path: "/file", Err: iofs.ErrPermission}
+	_, err := f.Read(nil)
+	if !errors.Is(err, iofs.ErrPermission) {
+		t.Errorf("got %v, expected %v", err, iofs.ErrPermission)
+	}
+	var perr *os.PathError
+	if !errors.As(err, &perr) || perr.Op != "read" || perr.Path != "/file" {
+		t.Errorf("got %#v, expected an *os.PathError", err)
+	}
+
+	openErr := &os.PathError{Op: "open", Path: "/file", Err: os.ErrNotExist}
+	f = &InvalidFile{Path: "/file", Err: openErr}
+	err = f.Truncate(0)
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrNotExist)
+	}
+	if err.Error() != "truncate /file: file does not exist" {
+		t.Errorf("got %q", err)
+	}
+}
@@ -0,0 +1,160 @@
+package absfs
+
+import (
+	"errors"
+	iofs "io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// AsFS - returns an `fs.FS` view of `fsys` rooted at its root directory, so
+// an absfs `FileSystem` can be passed to `http.FS`, `template.ParseFS`, and
+// other consumers of `io/fs`. Names are validated with `fs.ValidPath` and
+// `ValidName`, failing with `fs.ErrInvalid`, and interpreted as slash
+// separated paths relative to the root.
+//
+// The returned value also implements `fs.StatFS`, `fs.ReadDirFS`,
+// `fs.GlobFS`, and `fs.SubFS`. `Glob` is delegated to the absfs `Glob`
+// helper, which in turn uses a native implementation when `fsys` has one.
+// Directories opened through it implement `fs.ReadDirFile`.
+func AsFS(fsys FileSystem) iofs.FS {
+	return &iofsAdapter{fsys: fsys, root: "/"}
+}
+
+type iofsAdapter struct {
+	fsys FileSystem
+	root string // slash separated and absolute
+}
+
+// path - converts the `io/fs` name to a path in `a.fsys`.
+func (a *iofsAdapter) path(op, name string) (string, error) {
+	if !validFSName(a.fsys, name) {
+		return "", &iofs.PathError{Op: op, Path: name, Err: iofs.ErrInvalid}
+	}
+	return fromSlash(a.fsys.Separator(), path.Join(a.root, name)), nil
+}
+
+// validFSName - reports whether the `io/fs` name `name` is valid for both
+// `fs.ValidPath` and `ValidName`. Names containing the separator of `fsys`,
+// when it is not a slash, are rejected too, since it would be read as a
+// path separator rather than part of a name.
+func validFSName(fsys FileSystem, name string) bool {
+	if !iofs.ValidPath(name) || !ValidName(fsys, name) {
+		return false
+	}
+	sep := fsys.Separator()
+	return sep == '/' || strings.IndexByte(name, sep) < 0
+}
+
+// pathError - reports `err` against the `io/fs` name rather than the
+// underlying path.
+func pathError(op, name string, err error) error {
+	var perr *os.PathError
+	if errors.As(err, &perr) {
+		op, err = perr.Op, perr.Err
+	}
+	return &iofs.PathError{Op: op, Path: name, Err: err}
+}
+
+func (a *iofsAdapter) Open(name string) (iofs.File, error) {
+	p, err := a.path("open", name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := a.fsys.Open(p)
+	if err != nil {
+		return nil, pathError("open", name, err)
+	}
+	return &iofsFile{f}, nil
+}
+
+func (a *iofsAdapter) Stat(name string) (iofs.FileInfo, error) {
+	p, err := a.path("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := a.fsys.Stat(p)
+	if err != nil {
+		return nil, pathError("stat", name, err)
+	}
+	return info, nil
+}
+
+func (a *iofsAdapter) ReadDir(name string) ([]iofs.DirEntry, error) {
+	p, err := a.path("readdir", name)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := readDirEntries(a.fsys, p)
+	if err != nil {
+		return entries, pathError("readdir", name, err)
+	}
+	return entries, nil
+}
+
+func (a *iofsAdapter) Glob(pattern string) ([]string, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	sep := a.fsys.Separator()
+	matches, err := Glob(a.fsys, fromSlash(sep, path.Join(escapeGlob(a.root), pattern)))
+	if err == filepath.ErrBadPattern {
+		return nil, path.ErrBadPattern
+	}
+	if err != nil {
+		return nil, err
+	}
+	prefix := a.root
+	if prefix != "/" {
+		prefix += "/"
+	}
+	for i, m := range matches {
+		matches[i] = strings.TrimPrefix(toSlash(sep, m), prefix)
+	}
+	return matches, nil
+}
+
+// escapeGlob - returns `name` with the characters recognized by `path.Match`
+// quoted, so that it matches only itself. `*`, `?` and `[` are wrapped in a
+// character class rather than escaped with a backslash, since a backslash is
+// the separator of some filesystems.
+func escapeGlob(name string) string {
+	if !hasGlobMeta(name) {
+		return name
+	}
+	var b strings.Builder
+	for _, r := range name {
+		switch r {
+		case '*', '?', '[':
+			b.WriteByte('[')
+			b.WriteRune(r)
+			b.WriteByte(']')
+		case '\\':
+			b.WriteString(`\\`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+func (a *iofsAdapter) Sub(dir string) (iofs.FS, error) {
+	if !validFSName(a.fsys, dir) {
+		return nil, &iofs.PathError{Op: "sub", Path: dir, Err: iofs.ErrInvalid}
+	}
+	if dir == "." {
+		return a, nil
+	}
+	return &iofsAdapter{fsys: a.fsys, root: path.Join(a.root, dir)}, nil
+}
+
+// iofsFile - adds `ReadDir` to a `File` so it satisfies `fs.ReadDirFile`.
+type iofsFile struct {
+	File
+}
+
+func (f *iofsFile) ReadDir(n int) ([]iofs.DirEntry, error) {
+	return FileReadDir(f.File, n)
+}
@@ -0,0 +1,111 @@
+package absfs
+
+import (
+	"errors"
+	iofs "io/fs"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func newIOFSTest(t *testing.T) FileSystem {
+	t.Helper()
+	fsys := newTestFS()
+	if err := fsys.MkdirAll("/dir/sub", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, fsys, "/a.txt", "a")
+	writeTestFile(t, fsys, "/b.md", "b")
+	writeTestFile(t, fsys, "/dir/c.txt", "c")
+	writeTestFile(t, fsys, "/dir/sub/d.txt", "d")
+	return fsys
+}
+
+func TestAsFS(t *testing.T) {
+	fsys := AsFS(newIOFSTest(t))
+	if err := fstest.TestFS(fsys, "a.txt", "b.md", "dir/c.txt", "dir/sub/d.txt"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fsys.Open("/a.txt"); err == nil {
+		t.Error("expected rooted names to be rejected")
+	}
+	for _, name := range []string{"", "new\nline.txt", "a\x00.txt"} {
+		if _, err := fsys.Open(name); !errors.Is(err, iofs.ErrInvalid) {
+			t.Errorf("Open(%q): got %v, expected %v", name, err, iofs.ErrInvalid)
+		}
+	}
+
+	// a backslash would be read as a separator by a backslash separated
+	// filesystem, so it can't be part of an io/fs name
+	fsys = AsFS(&sepFS{newIOFSTest(t), '\\'})
+	for _, name := range []string{`dir\c.txt`, "C:x"} {
+		if _, err := iofs.Stat(fsys, name); !errors.Is(err, iofs.ErrInvalid) {
+			t.Errorf("Stat(%q): got %v, expected %v", name, err, iofs.ErrInvalid)
+		}
+	}
+}
+
+func TestAsFSGlobAndSub(t *testing.T) {
+	fsys := AsFS(newIOFSTest(t))
+	if _, ok := fsys.(iofs.GlobFS); !ok {
+		t.Fatal("AsFS does not implement fs.GlobFS")
+	}
+	if _, ok := fsys.(iofs.SubFS); !ok {
+		t.Fatal("AsFS does not implement fs.SubFS")
+	}
+
+	matches, err := iofs.Glob(fsys, "*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"a.txt"}; !reflect.DeepEqual(matches, expected) {
+		t.Errorf("Glob: got %v, expected %v", matches, expected)
+	}
+
+	sub, err := iofs.Sub(fsys, "dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	matches, err = iofs.Glob(sub, "*/*.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"sub/d.txt"}; !reflect.DeepEqual(matches, expected) {
+		t.Errorf("Glob in Sub: got %v, expected %v", matches, expected)
+	}
+	data, err := iofs.ReadFile(sub, "c.txt")
+	if err != nil || string(data) != "c" {
+		t.Errorf("ReadFile in Sub: got %q, %v", data, err)
+	}
+	if err := fstest.TestFS(sub, "c.txt", "sub/d.txt"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := iofs.Glob(fsys, "["); err == nil {
+		t.Error("expected a bad pattern error")
+	}
+}
+
+func TestAsFSGlobSubMeta(t *testing.T) {
+	base := newTestFS()
+	for _, dir := range []string{"/[x]", "/x", "/a*b", `/c\d`} {
+		if err := base.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		writeTestFile(t, base, dir+"/file.txt", dir)
+	}
+
+	for _, dir := range []string{"[x]", "a*b", `c\d`} {
+		sub, err := iofs.Sub(AsFS(base), dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		matches, err := iofs.Glob(sub, "*.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := []string{"file.txt"}; !reflect.DeepEqual(matches, expected) {
+			t.Errorf("Glob in Sub(%q): got %v, expected %v", dir, matches, expected)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package absfs
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// ErrLineTooLong - is returned by `LineReader` when a line is longer than its
+// maximum line length.
+var ErrLineTooLong = errors.New("line too long")
+
+// LineReader - reads newline delimited records from a File.
+//
+//	r := absfs.NewLineReader(f, 0)
+//	for line, ok := r.Next(); ok; line, ok = r.Next() {
+//		...
+//	}
+//	if err := r.Err(); err != nil {
+//		...
+//	}
+type LineReader struct {
+	s    *bufio.Scanner
+	name string
+	max  int
+	n    int
+	err  error
+}
+
+// NewLineReader - returns a `LineReader` that reads lines from the current
+// offset of `f`. Lines may be at most `max` bytes long, not counting the line
+// ending; if `max` is 0 or less, `bufio.MaxScanTokenSize` is used.
+func NewLineReader(f File, max int) *LineReader {
+	if max <= 0 {
+		max = bufio.MaxScanTokenSize
+	}
+	s := bufio.NewScanner(f)
+	// the scanner needs room for the line ending as well as the line
+	size := max + 2
+	if size > 4096 {
+		s.Buffer(make([]byte, 4096), size)
+	} else {
+		s.Buffer(make([]byte, size), size)
+	}
+	return &LineReader{s: s, name: f.Name(), max: max}
+}
+
+// Next - returns the next line with its "\n" or "\r\n" line ending removed,
+// and true, or false once there are no more lines or an error has occurred.
+// The last line is returned even if the file does not end with a newline. The
+// returned slice is only valid until the next call to `Next`.
+func (r *LineReader) Next() ([]byte, bool) {
+	if r.err != nil {
+		return nil, false
+	}
+	if !r.s.Scan() {
+		r.err = r.s.Err()
+		if r.err == bufio.ErrTooLong {
+			r.err = r.tooLong()
+		}
+		return nil, false
+	}
+	line := r.s.Bytes()
+	if len(line) > r.max {
+		r.err = r.tooLong()
+		return nil, false
+	}
+	r.n++
+	return line, true
+}
+
+// Err - returns the first error encountered by `Next`, other than `io.EOF`.
+// A line longer than the maximum is reported as an `*os.PathError` wrapping
+// `ErrLineTooLong`, rather than being truncated or split.
+func (r *LineReader) Err() error {
+	return r.err
+}
+
+func (r *LineReader) tooLong() error {
+	return &os.PathError{Op: "read", Path: r.name, Err: fmt.Errorf("%w: line %d exceeds %d bytes", ErrLineTooLong, r.n+1, r.max)}
+}
@@ -0,0 +1,42 @@
+package absfs
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLineReader(t *testing.T) {
+	tests := []struct {
+		content  string
+		max      int
+		expected []string
+		tooLong  bool
+	}{
+		{"", 0, nil, false},
+		{"one\ntwo\n", 0, []string{"one", "two"}, false},
+		{"one\r\ntwo", 0, []string{"one", "two"}, false},
+		{"\n\nlast", 0, []string{"", "", "last"}, false},
+		{"12345\n123456\n", 5, []string{"12345"}, true},
+		{"12345\r\n1234", 5, []string{"12345", "1234"}, false},
+		{"ok\n" + strings.Repeat("x", 5000), 4096, []string{"ok"}, true},
+		{strings.Repeat("y", 5000) + "\n", 8192, []string{strings.Repeat("y", 5000)}, false},
+	}
+	for i, tt := range tests {
+		f := openTestFile(t, tt.content)
+		r := NewLineReader(f, tt.max)
+		var got []string
+		for line, ok := r.Next(); ok; line, ok = r.Next() {
+			got = append(got, string(line))
+		}
+		if strings.Join(got, "|") != strings.Join(tt.expected, "|") || len(got) != len(tt.expected) {
+			t.Errorf("%d: got %q, expected %q", i, got, tt.expected)
+		}
+		if err := r.Err(); errors.Is(err, ErrLineTooLong) != tt.tooLong {
+			t.Errorf("%d: got error %v, expected too long %t", i, err, tt.tooLong)
+		}
+		if _, ok := r.Next(); ok {
+			t.Errorf("%d: Next returned a line after the end", i)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package absfs
+
+import (
+	"io"
+	"os"
+	"syscall"
+)
+
+// MaxSizeFile - returns a File that never grows `f` past `max` bytes, to
+// guard against decompression bombs and untrusted uploads filling storage
+// through a single file. A `Write`, `WriteString`, or `WriteAt` that would
+// extend the file past `max` writes only the part that fits and returns
+// `io.ErrShortWrite`. `Truncate` to a size larger than `max` fails with an
+// `*os.PathError` wrapping `syscall.EFBIG`.
+//
+// The limit is checked against the offset each write would end at, so
+// seeking past `max` and writing, or calling `WriteAt` at a high offset, is
+// caught as well. Writes that don't fit at all are not passed to `f`, so they
+// can't extend the file with zeros on backends that grow it before writing.
+// `Write` finds the current offset with `Tell`, so it assumes writes happen
+// at the current offset and does not account for handles opened with
+// `O_APPEND`.
+func MaxSizeFile(f File, max int64) File {
+	if max < 0 {
+		max = 0
+	}
+	return &maxSizeFile{File: f, max: max}
+}
+
+type maxSizeFile struct {
+	File
+	max int64
+}
+
+// room - returns how many of `n` bytes written at `off` fit within the limit.
+func (f *maxSizeFile) room(off int64, n int) int {
+	if off >= f.max {
+		return 0
+	}
+	if avail := f.max - off; int64(n) > avail {
+		return int(avail)
+	}
+	return n
+}
+
+func (f *maxSizeFile) Write(b []byte) (int, error) {
+	off, err := Tell(f.File)
+	if err != nil {
+		return 0, err
+	}
+	m := f.room(off, len(b))
+	if m == 0 && len(b) > 0 {
+		return 0, io.ErrShortWrite
+	}
+	n, err := f.File.Write(b[:m])
+	if err == nil && m < len(b) {
+		err = io.ErrShortWrite
+	}
+	return n, err
+}
+
+func (f *maxSizeFile) WriteString(s string) (int, error) {
+	off, err := Tell(f.File)
+	if err != nil {
+		return 0, err
+	}
+	m := f.room(off, len(s))
+	if m == 0 && len(s) > 0 {
+		return 0, io.ErrShortWrite
+	}
+	n, err := f.File.WriteString(s[:m])
+	if err == nil && m < len(s) {
+		err = io.ErrShortWrite
+	}
+	return n, err
+}
+
+func (f *maxSizeFile) WriteAt(b []byte, off int64) (int, error) {
+	m := f.room(off, len(b))
+	if m == 0 && len(b) > 0 {
+		return 0, io.ErrShortWrite
+	}
+	n, err := f.File.WriteAt(b[:m], off)
+	if err == nil && m < len(b) {
+		err = io.ErrShortWrite
+	}
+	return n, err
+}
+
+func (f *maxSizeFile) Truncate(size int64) error {
+	if size > f.max {
+		return &os.PathError{Op: "truncate", Path: f.File.Name(), Err: syscall.EFBIG}
+	}
+	return f.File.Truncate(size)
+}
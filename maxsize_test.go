@@ -0,0 +1,63 @@
+package absfs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestMaxSizeFile(t *testing.T) {
+	fsys := newTestFS()
+	f, err := fsys.Create("/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	m := MaxSizeFile(f, 8)
+
+	if n, err := m.Write([]byte("12345")); n != 5 || err != nil {
+		t.Fatalf("got %d, %v, expected 5, nil", n, err)
+	}
+	if n, err := m.WriteString("67890"); n != 3 || err != io.ErrShortWrite {
+		t.Errorf("got %d, %v, expected 3, %v", n, err, io.ErrShortWrite)
+	}
+	if n, err := m.Write([]byte("x")); n != 0 || err != io.ErrShortWrite {
+		t.Errorf("got %d, %v, expected 0, %v", n, err, io.ErrShortWrite)
+	}
+
+	// overwriting within the limit is allowed
+	if n, err := m.WriteAt([]byte("ab"), 2); n != 2 || err != nil {
+		t.Errorf("got %d, %v, expected 2, nil", n, err)
+	}
+	if n, err := m.WriteAt([]byte("cd"), 7); n != 1 || err != io.ErrShortWrite {
+		t.Errorf("got %d, %v, expected 1, %v", n, err, io.ErrShortWrite)
+	}
+	if n, err := m.WriteAt([]byte("ef"), 1<<40); n != 0 || err != io.ErrShortWrite {
+		t.Errorf("got %d, %v, expected 0, %v", n, err, io.ErrShortWrite)
+	}
+
+	// seeking past the limit and writing is caught
+	if _, err := m.Seek(100, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if n, err := m.Write([]byte("z")); n != 0 || err != io.ErrShortWrite {
+		t.Errorf("got %d, %v, expected 0, %v", n, err, io.ErrShortWrite)
+	}
+
+	if err := m.Truncate(9); !errors.Is(err, syscall.EFBIG) {
+		t.Errorf("got %v, expected %v", err, syscall.EFBIG)
+	}
+	var perr *os.PathError
+	if err := m.Truncate(9); !errors.As(err, &perr) || perr.Path != "/file" {
+		t.Errorf("got %v, expected an *os.PathError for /file", err)
+	}
+	if err := m.Truncate(8); err != nil {
+		t.Error(err)
+	}
+
+	if got := readTestFile(t, fsys, "/file"); got != "12ab567c" {
+		t.Errorf("got %q, expected %q", got, "12ab567c")
+	}
+}
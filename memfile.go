@@ -0,0 +1,210 @@
+package absfs
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// NewMemFile - returns a File named `name` whose contents are held in memory,
+// starting with `data` and reporting `perm` as its mode. The file takes
+// ownership of `data`, as `bytes.NewBuffer` does, so the caller should not use
+// it afterwards.
+//
+// The file supports reading, writing, seeking, and truncation like a regular
+// file opened read-write. Writes past the end of the file, with `WriteAt` or
+// after seeking, zero fill the gap. Directory methods fail with
+// `syscall.ENOTDIR` and all methods fail with `os.ErrClosed` after `Close`.
+//
+// The returned File is not safe for concurrent use; see `NewSyncMemFile`.
+func NewMemFile(name string, data []byte, perm os.FileMode) File {
+	return &memFile{name: name, data: data, mode: perm &^ os.ModeType, mtime: time.Now()}
+}
+
+// NewSyncMemFile - is like `NewMemFile` but serializes all calls on the
+// returned File with a mutex, so it may be shared between goroutines.
+func NewSyncMemFile(name string, data []byte, perm os.FileMode) File {
+	// the file has no filesystem, so it is given an empty one for its lock
+	return &syncfile{fs: new(syncfs), f: NewMemFile(name, data, perm)}
+}
+
+type memFile struct {
+	name   string
+	data   []byte
+	offset int64
+	mode   os.FileMode
+	mtime  time.Time
+	closed bool
+}
+
+func (f *memFile) pathError(op string, err error) error {
+	return &os.PathError{Op: op, Path: f.name, Err: err}
+}
+
+// resize - sets the length of the file contents to `size`, zeroing any bytes
+// added.
+func (f *memFile) resize(size int64) {
+	n := int(size)
+	if n <= len(f.data) {
+		f.data = f.data[:n]
+		return
+	}
+	if n > cap(f.data) {
+		data := make([]byte, n, n+n/4)
+		copy(data, f.data)
+		f.data = data
+		return
+	}
+	tail := f.data[len(f.data):n]
+	for i := range tail {
+		tail[i] = 0
+	}
+	f.data = f.data[:n]
+}
+
+func (f *memFile) Name() string {
+	return f.name
+}
+
+func (f *memFile) Read(b []byte) (int, error) {
+	if f.closed {
+		return 0, f.pathError("read", os.ErrClosed)
+	}
+	n, err := f.readAt(b, f.offset)
+	f.offset += int64(n)
+	if err == io.EOF && n > 0 {
+		err = nil
+	}
+	return n, err
+}
+
+func (f *memFile) ReadAt(b []byte, off int64) (int, error) {
+	if f.closed {
+		return 0, f.pathError("read", os.ErrClosed)
+	}
+	if off < 0 {
+		return 0, f.pathError("readat", syscall.EINVAL)
+	}
+	return f.readAt(b, off)
+}
+
+func (f *memFile) readAt(b []byte, off int64) (int, error) {
+	if off >= int64(len(f.data)) {
+		return 0, io.EOF
+	}
+	n := copy(b, f.data[off:])
+	if n < len(b) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *memFile) Write(b []byte) (int, error) {
+	if f.closed {
+		return 0, f.pathError("write", os.ErrClosed)
+	}
+	n := f.writeAt(b, f.offset)
+	f.offset += int64(n)
+	return n, nil
+}
+
+func (f *memFile) WriteAt(b []byte, off int64) (int, error) {
+	if f.closed {
+		return 0, f.pathError("write", os.ErrClosed)
+	}
+	if off < 0 {
+		return 0, f.pathError("writeat", syscall.EINVAL)
+	}
+	return f.writeAt(b, off), nil
+}
+
+func (f *memFile) writeAt(b []byte, off int64) int {
+	if end := off + int64(len(b)); end > int64(len(f.data)) {
+		f.resize(end)
+	}
+	f.mtime = time.Now()
+	return copy(f.data[off:], b)
+}
+
+func (f *memFile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+func (f *memFile) Seek(offset int64, whence int) (int64, error) {
+	if f.closed {
+		return 0, f.pathError("seek", os.ErrClosed)
+	}
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += int64(len(f.data))
+	default:
+		return 0, f.pathError("seek", syscall.EINVAL)
+	}
+	if offset < 0 {
+		return 0, f.pathError("seek", syscall.EINVAL)
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *memFile) Truncate(size int64) error {
+	if f.closed {
+		return f.pathError("truncate", os.ErrClosed)
+	}
+	if size < 0 {
+		return f.pathError("truncate", syscall.EINVAL)
+	}
+	f.resize(size)
+	f.mtime = time.Now()
+	return nil
+}
+
+func (f *memFile) Readdir(n int) ([]os.FileInfo, error) {
+	return nil, f.pathError("readdirent", syscall.ENOTDIR)
+}
+
+func (f *memFile) Readdirnames(n int) ([]string, error) {
+	return nil, f.pathError("readdirent", syscall.ENOTDIR)
+}
+
+func (f *memFile) Stat() (os.FileInfo, error) {
+	if f.closed {
+		return nil, f.pathError("stat", os.ErrClosed)
+	}
+	return &memInfo{filepath.Base(f.name), int64(len(f.data)), f.mode, f.mtime}, nil
+}
+
+func (f *memFile) Sync() error {
+	if f.closed {
+		return f.pathError("sync", os.ErrClosed)
+	}
+	return nil
+}
+
+func (f *memFile) Close() error {
+	if f.closed {
+		return f.pathError("close", os.ErrClosed)
+	}
+	f.closed = true
+	return nil
+}
+
+// memInfo - is a snapshot of the metadata of a `NewMemFile` file.
+type memInfo struct {
+	name  string
+	size  int64
+	mode  os.FileMode
+	mtime time.Time
+}
+
+func (i *memInfo) Name() string       { return i.name }
+func (i *memInfo) Size() int64        { return i.size }
+func (i *memInfo) Mode() os.FileMode  { return i.mode }
+func (i *memInfo) ModTime() time.Time { return i.mtime }
+func (i *memInfo) IsDir() bool        { return false }
+func (i *memInfo) Sys() interface{}   { return nil }
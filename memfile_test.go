@@ -0,0 +1,130 @@
+package absfs
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"testing"
+)
+
+func TestMemFile(t *testing.T) {
+	f := NewMemFile("/dir/file.txt", []byte("hello"), 0640)
+	if f.Name() != "/dir/file.txt" {
+		t.Errorf("got name %q", f.Name())
+	}
+
+	data, err := io.ReadAll(f)
+	if err != nil || string(data) != "hello" {
+		t.Fatalf("got %q, %v", data, err)
+	}
+	if _, err := f.WriteString(" world"); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 5)
+	if n, err := f.ReadAt(buf, 6); n != 5 || err != nil || string(buf) != "world" {
+		t.Errorf("ReadAt: got %q, %v", buf[:n], err)
+	}
+	if n, err := f.ReadAt(buf, 8); n != 3 || err != io.EOF {
+		t.Errorf("ReadAt past end: got %d, %v", n, err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name() != "file.txt" || info.Size() != 11 || info.Mode() != 0640 || info.IsDir() {
+		t.Errorf("Stat: got %q, %d, %s", info.Name(), info.Size(), info.Mode())
+	}
+
+	if pos, err := f.Seek(-5, io.SeekEnd); pos != 6 || err != nil {
+		t.Errorf("Seek: got %d, %v", pos, err)
+	}
+	if _, err := f.Seek(-1, io.SeekStart); !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("Seek: got %v, expected %v", err, syscall.EINVAL)
+	}
+	if _, err := f.Readdirnames(-1); !errors.Is(err, syscall.ENOTDIR) {
+		t.Errorf("Readdirnames: got %v, expected %v", err, syscall.ENOTDIR)
+	}
+
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Read(buf); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Read after Close: got %v, expected %v", err, os.ErrClosed)
+	}
+}
+
+func TestMemFileSparse(t *testing.T) {
+	f := NewMemFile("sparse", nil, 0644)
+	if _, err := f.WriteAt([]byte("end"), 5); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Seek(10, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("!")); err != nil {
+		t.Fatal(err)
+	}
+	expected := []byte("\x00\x00\x00\x00\x00end\x00\x00!")
+	if data := readAllAt(t, f); !bytes.Equal(data, expected) {
+		t.Errorf("got %q, expected %q", data, expected)
+	}
+
+	// bytes dropped by a truncate must not reappear when the file grows
+	if err := f.Truncate(2); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Truncate(8); err != nil {
+		t.Fatal(err)
+	}
+	if data := readAllAt(t, f); !bytes.Equal(data, make([]byte, 8)) {
+		t.Errorf("got %q, expected 8 zero bytes", data)
+	}
+	if _, err := f.WriteAt([]byte("x"), 9); err != nil {
+		t.Fatal(err)
+	}
+	if data := readAllAt(t, f); !bytes.Equal(data, append(make([]byte, 9), 'x')) {
+		t.Errorf("got %q, expected 9 zero bytes and x", data)
+	}
+}
+
+func readAllAt(t *testing.T, f File) []byte {
+	t.Helper()
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := make([]byte, info.Size())
+	if _, err := f.ReadAt(data, 0); err != nil && err != io.EOF {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestSyncMemFile(t *testing.T) {
+	f := NewSyncMemFile("shared", nil, 0644)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if _, err := f.Write([]byte("x")); err != nil {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Size() != 800 {
+		t.Errorf("got size %d, expected 800", info.Size())
+	}
+}
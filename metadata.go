@@ -0,0 +1,115 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// MetaChange - describes a set of metadata updates for a single path. Nil
+// fields are left unchanged.
+type MetaChange struct {
+	Name    string
+	Mode    *os.FileMode
+	ModTime *time.Time
+	Owner   *Owner
+}
+
+// Owner - is a numeric user and group id pair as passed to `Chown`.
+type Owner struct {
+	Uid int
+	Gid int
+}
+
+// ApplyMetadata - applies `changes` in order. The current mode, times, and
+// owner of every path are captured with `Stat` before anything is modified,
+// and if any change fails the changes already applied are rolled back in
+// reverse order so the batch is all-or-nothing.
+//
+// The owner is read from an `Owner() Owner` method of the FileInfo, or from
+// its `Sys` value on platforms where the layout is known. If the owner of a
+// path with an `Owner` change can't be found, the batch could not be rolled
+// back, so nothing is changed and the error is an `*os.PathError` wrapping
+// `ErrNotImplemented`. The access time is found the same way as by
+// `ChtimesPartial`; where it can't be, a rolled back `ModTime` also resets
+// the access time to the prior modification time. The returned error joins
+// the failure with any errors encountered during rollback.
+func ApplyMetadata(fsys FileSystem, changes []MetaChange) error {
+	prior := make([]os.FileInfo, len(changes))
+	for i, c := range changes {
+		info, err := fsys.Stat(c.Name)
+		if err != nil {
+			return err
+		}
+		if _, ok := fileOwner(info); c.Owner != nil && !ok {
+			return &os.PathError{Op: "chown", Path: c.Name, Err: ErrNotImplemented}
+		}
+		prior[i] = info
+	}
+
+	for i, c := range changes {
+		err := applyMetaChange(fsys, c)
+		if err == nil {
+			continue
+		}
+		errs := []error{err}
+		for j := i; j >= 0; j-- {
+			if err := rollbackMetaChange(fsys, changes[j], prior[j]); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+func applyMetaChange(fsys FileSystem, c MetaChange) error {
+	if c.Mode != nil {
+		if err := fsys.Chmod(c.Name, *c.Mode); err != nil {
+			return err
+		}
+	}
+	if c.ModTime != nil {
+		if err := fsys.Chtimes(c.Name, *c.ModTime, *c.ModTime); err != nil {
+			return err
+		}
+	}
+	if c.Owner != nil {
+		if err := fsys.Chown(c.Name, c.Owner.Uid, c.Owner.Gid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func rollbackMetaChange(fsys FileSystem, c MetaChange, info os.FileInfo) error {
+	if c.Mode != nil {
+		if err := fsys.Chmod(c.Name, info.Mode()); err != nil {
+			return err
+		}
+	}
+	if c.ModTime != nil {
+		atime := info.ModTime()
+		if t, ok := accessTime(info); ok {
+			atime = t
+		}
+		if err := fsys.Chtimes(c.Name, atime, info.ModTime()); err != nil {
+			return err
+		}
+	}
+	if c.Owner != nil {
+		owner, _ := fileOwner(info)
+		if err := fsys.Chown(c.Name, owner.Uid, owner.Gid); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fileOwner - returns the owner recorded in `info`, if it can be found.
+func fileOwner(info os.FileInfo) (Owner, bool) {
+	if o, ok := info.(interface{ Owner() Owner }); ok {
+		return o.Owner(), true
+	}
+	return sysOwner(info.Sys())
+}
@@ -0,0 +1,8 @@
+//go:build !unix
+
+package absfs
+
+// sysOwner - reports that the owner is unknown on this platform.
+func sysOwner(sys interface{}) (Owner, bool) {
+	return Owner{}, false
+}
@@ -0,0 +1,169 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// chmodFailFS - fails every `Chmod` of the path `fail`.
+type chmodFailFS struct {
+	FileSystem
+	fail string
+}
+
+var errChmodFail = errors.New("chmod failed")
+
+func (fs *chmodFailFS) Chmod(name string, mode os.FileMode) error {
+	if name == fs.fail {
+		return errChmodFail
+	}
+	return fs.FileSystem.Chmod(name, mode)
+}
+
+func createFiles(t *testing.T, fsys FileSystem, names ...string) {
+	t.Helper()
+	for _, name := range names {
+		f, err := fsys.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Close()
+	}
+}
+
+func TestApplyMetadata(t *testing.T) {
+	fsys := newTestFS()
+	createFiles(t, fsys, "/a", "/b")
+
+	mode := os.FileMode(0600)
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	err := ApplyMetadata(fsys, []MetaChange{
+		{Name: "/a", Mode: &mode},
+		{Name: "/b", Mode: &mode, ModTime: &mtime},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"/a", "/b"} {
+		info, err := fsys.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode() != mode {
+			t.Errorf("%s: mode %s, expected %s", name, info.Mode(), mode)
+		}
+	}
+	info, _ := fsys.Stat("/b")
+	if !info.ModTime().Equal(mtime) {
+		t.Errorf("mtime %s, expected %s", info.ModTime(), mtime)
+	}
+}
+
+func TestApplyMetadataRollback(t *testing.T) {
+	fsys := &chmodFailFS{newTestFS(), "/c"}
+	createFiles(t, fsys, "/a", "/b", "/c")
+
+	before := make(map[string]os.FileInfo)
+	for _, name := range []string{"/a", "/b"} {
+		info, err := fsys.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		before[name] = info
+	}
+
+	mode := os.FileMode(0700)
+	mtime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	err := ApplyMetadata(fsys, []MetaChange{
+		{Name: "/a", Mode: &mode, ModTime: &mtime},
+		{Name: "/b", Mode: &mode},
+		{Name: "/c", Mode: &mode},
+	})
+	if !errors.Is(err, errChmodFail) {
+		t.Fatalf("got error %v, expected %v", err, errChmodFail)
+	}
+
+	for _, name := range []string{"/a", "/b"} {
+		info, err := fsys.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode() != before[name].Mode() {
+			t.Errorf("%s: mode %s, expected %s", name, info.Mode(), before[name].Mode())
+		}
+		if !info.ModTime().Equal(before[name].ModTime()) {
+			t.Errorf("%s: mtime %s, expected %s", name, info.ModTime(), before[name].ModTime())
+		}
+	}
+}
+
+func TestApplyMetadataRollbackOwner(t *testing.T) {
+	fsys := &chmodFailFS{newTestFS(), "/c"}
+	createFiles(t, fsys, "/a", "/c")
+	atime := time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)
+	mtime := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	if err := fsys.Chtimes("/a", atime, mtime); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Chown("/a", 1, 2); err != nil {
+		t.Fatal(err)
+	}
+
+	mode := os.FileMode(0700)
+	newTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	err := ApplyMetadata(fsys, []MetaChange{
+		{Name: "/a", ModTime: &newTime, Owner: &Owner{Uid: 5, Gid: 6}},
+		{Name: "/c", Mode: &mode},
+	})
+	if !errors.Is(err, errChmodFail) {
+		t.Fatalf("got error %v, expected %v", err, errChmodFail)
+	}
+
+	info, err := fsys.Stat("/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if owner, _ := fileOwner(info); owner != (Owner{Uid: 1, Gid: 2}) {
+		t.Errorf("owner %+v, expected %+v", owner, Owner{Uid: 1, Gid: 2})
+	}
+	if at, _ := accessTime(info); !at.Equal(atime) || !info.ModTime().Equal(mtime) {
+		t.Errorf("times %s, %s, expected %s, %s", at, info.ModTime(), atime, mtime)
+	}
+}
+
+// noOwnerFS - returns FileInfo values that carry no owner.
+type noOwnerFS struct {
+	FileSystem
+}
+
+type noOwnerInfo struct {
+	os.FileInfo
+}
+
+func (fs noOwnerFS) Stat(name string) (os.FileInfo, error) {
+	info, err := fs.FileSystem.Stat(name)
+	if err != nil {
+		return nil, err
+	}
+	return noOwnerInfo{info}, nil
+}
+
+func TestApplyMetadataUnknownOwner(t *testing.T) {
+	fsys := noOwnerFS{newTestFS()}
+	createFiles(t, fsys, "/a", "/b")
+
+	mode := os.FileMode(0700)
+	err := ApplyMetadata(fsys, []MetaChange{
+		{Name: "/a", Mode: &mode},
+		{Name: "/b", Owner: &Owner{Uid: 5, Gid: 6}},
+	})
+	if !errors.Is(err, ErrNotImplemented) {
+		t.Fatalf("got error %v, expected %v", err, ErrNotImplemented)
+	}
+	if info, _ := fsys.Stat("/a"); info.Mode() == mode {
+		t.Error("the batch was partly applied")
+	}
+}
@@ -0,0 +1,14 @@
+//go:build unix
+
+package absfs
+
+import "syscall"
+
+// sysOwner - returns the owner from a `*syscall.Stat_t`.
+func sysOwner(sys interface{}) (Owner, bool) {
+	st, ok := sys.(*syscall.Stat_t)
+	if !ok {
+		return Owner{}, false
+	}
+	return Owner{Uid: int(st.Uid), Gid: int(st.Gid)}, true
+}
@@ -0,0 +1,67 @@
+package absfs
+
+import (
+	"io"
+	"os"
+	"sync"
+	"syscall"
+)
+
+// Mmapper - is implemented by filesystems that can map a region of a file
+// into memory. The returned slice must not be modified or used after the
+// returned unmap function has been called.
+type Mmapper interface {
+	Mmap(name string, offset, length int64) ([]byte, func() error, error)
+}
+
+// Mmap - returns `length` bytes of the file `name` starting at `offset`,
+// together with a function that releases them. If `fsys` implements
+// `Mmapper` the call is delegated to it, giving zero-copy access on backends
+// that support it. Otherwise the region is read with `ReadAt` into a newly
+// allocated buffer and the release function does nothing.
+//
+// The release function may be called more than once; only the first call
+// has any effect and later calls return nil. Reading past the end of the
+// file is an error wrapping `io.ErrUnexpectedEOF`.
+func Mmap(fsys FileSystem, name string, offset, length int64) ([]byte, func() error, error) {
+	if offset < 0 || length < 0 {
+		return nil, nil, &os.PathError{Op: "mmap", Path: name, Err: syscall.EINVAL}
+	}
+	if m, ok := fsys.(Mmapper); ok {
+		data, unmap, err := m.Mmap(name, offset, length)
+		if err != nil {
+			return nil, nil, err
+		}
+		return data, onceFunc(unmap), nil
+	}
+	return readRegion(fsys, name, offset, length)
+}
+
+// readRegion - implements `Mmap` with `ReadAt`.
+func readRegion(fsys FileSystem, name string, offset, length int64) ([]byte, func() error, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	data := make([]byte, length)
+	n, err := f.ReadAt(data, offset)
+	if int64(n) < length {
+		if err == nil || err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, nil, &os.PathError{Op: "mmap", Path: name, Err: err}
+	}
+	return data, func() error { return nil }, nil
+}
+
+// onceFunc - makes `fn` safe to call more than once; only the first call runs
+// `fn` and later calls return nil.
+func onceFunc(fn func() error) func() error {
+	var once sync.Once
+	return func() (err error) {
+		once.Do(func() { err = fn() })
+		return err
+	}
+}
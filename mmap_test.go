@@ -0,0 +1,68 @@
+package absfs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"testing"
+)
+
+// mmapFiler - records calls to `Mmap` and `unmap`.
+type mmapFiler struct {
+	*mockFiler
+	mmaps, unmaps int
+}
+
+func (m *mmapFiler) Mmap(name string, offset, length int64) ([]byte, func() error, error) {
+	m.mmaps++
+	n, ok := m.nodes[name]
+	if !ok {
+		return nil, nil, &os.PathError{Op: "mmap", Path: name, Err: os.ErrNotExist}
+	}
+	return n.data[offset : offset+length], func() error { m.unmaps++; return nil }, nil
+}
+
+func TestMmap(t *testing.T) {
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/data", "0123456789")
+
+	data, unmap, err := Mmap(fsys, "/data", 2, 5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "23456" {
+		t.Errorf("got %q, expected %q", data, "23456")
+	}
+	if err := unmap(); err != nil {
+		t.Error(err)
+	}
+	if err := unmap(); err != nil {
+		t.Error(err)
+	}
+
+	if _, _, err := Mmap(fsys, "/data", 8, 5); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("got %v, expected %v", err, io.ErrUnexpectedEOF)
+	}
+	if _, _, err := Mmap(fsys, "/data", -1, 5); err == nil {
+		t.Error("expected an error for a negative offset")
+	}
+}
+
+func TestMmapDelegates(t *testing.T) {
+	m := &mmapFiler{mockFiler: newMockFiler()}
+	fsys := ExtendFiler(m)
+	writeTestFile(t, fsys, "/data", "0123456789")
+
+	data, unmap, err := Mmap(fsys, "data", 0, 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "0123" || m.mmaps != 1 {
+		t.Errorf("got %q after %d calls, expected %q from the Filer", data, m.mmaps, "0123")
+	}
+	unmap()
+	unmap()
+	if m.unmaps != 1 {
+		t.Errorf("unmap called %d times, expected 1", m.unmaps)
+	}
+}
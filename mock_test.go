@@ -0,0 +1,405 @@
+package absfs
+
+import (
+	"io"
+	"os"
+	"path"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// mockFiler - is a minimal in-memory `Filer` used to exercise the helpers and
+// wrappers in this package. It stores every node in a single map keyed by
+// cleaned absolute path and is not safe for concurrent use.
+type mockFiler struct {
+	nodes map[string]*mockNode
+
+	// mkdirs makes OpenFile create missing parent directories, like some
+	// lenient object store backends.
+	mkdirs bool
+}
+
+type mockNode struct {
+	mode   os.FileMode
+	data   []byte
+	target string
+	atime  time.Time
+	mtime  time.Time
+	uid    int
+	gid    int
+}
+
+func newMockFiler() *mockFiler {
+	now := time.Now()
+	return &mockFiler{nodes: map[string]*mockNode{
+		"/": {mode: os.ModeDir | 0755, atime: now, mtime: now},
+	}}
+}
+
+// newTestFS - returns a `FileSystem` backed by a fresh `mockFiler`.
+func newTestFS() FileSystem {
+	return ExtendFiler(newMockFiler())
+}
+
+// resolve - follows symbolic links in `name`. The final element is only
+// followed when `follow` is set.
+func (m *mockFiler) resolve(name string, follow bool) string {
+	return m.resolveDepth(path.Clean(name), follow, 0)
+}
+
+func (m *mockFiler) resolveDepth(name string, follow bool, depth int) string {
+	parts := strings.Split(name, "/")
+	resolved := "/"
+	for i, p := range parts {
+		if p == "" {
+			continue
+		}
+		next := resolved + "/" + p
+		if resolved == "/" {
+			next = "/" + p
+		}
+		n, ok := m.nodes[next]
+		last := i == len(parts)-1
+		if ok && n.mode&os.ModeSymlink != 0 && (follow || !last) && depth < 40 {
+			target := n.target
+			if !path.IsAbs(target) {
+				target = path.Join(resolved, target)
+			}
+			rest := strings.Join(parts[i+1:], "/")
+			return m.resolveDepth(path.Join(target, rest), follow, depth+1)
+		}
+		resolved = next
+	}
+	return resolved
+}
+
+func (m *mockFiler) lookup(op, name string) (*mockNode, error) {
+	n, ok := m.nodes[m.resolve(name, true)]
+	if !ok {
+		return nil, &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+	}
+	return n, nil
+}
+
+func (m *mockFiler) checkParent(op, name string) error {
+	parent, ok := m.nodes[path.Dir(name)]
+	if !ok {
+		return &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+	}
+	if !parent.mode.IsDir() {
+		return &os.PathError{Op: op, Path: name, Err: syscall.ENOTDIR}
+	}
+	return nil
+}
+
+func (m *mockFiler) children(dir string) []string {
+	prefix := dir + "/"
+	if dir == "/" {
+		prefix = dir
+	}
+	var names []string
+	for p := range m.nodes {
+		if p != dir && strings.HasPrefix(p, prefix) && !strings.Contains(p[len(prefix):], "/") {
+			names = append(names, p[len(prefix):])
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (m *mockFiler) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	name = m.resolve(name, true)
+	n, ok := m.nodes[name]
+	if ok && flag&os.O_CREATE != 0 && flag&os.O_EXCL != 0 {
+		return &InvalidFile{Path: name}, &os.PathError{Op: "open", Path: name, Err: os.ErrExist}
+	}
+	if !ok {
+		if flag&os.O_CREATE == 0 {
+			return &InvalidFile{Path: name}, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+		}
+		if m.mkdirs {
+			m.mkdirAll(path.Dir(name))
+		}
+		if err := m.checkParent("open", name); err != nil {
+			return &InvalidFile{Path: name}, err
+		}
+		now := time.Now()
+		n = &mockNode{mode: perm &^ os.ModeType, atime: now, mtime: now}
+		m.nodes[name] = n
+	}
+	if flag&os.O_TRUNC != 0 && !n.mode.IsDir() {
+		n.data = n.data[:0]
+	}
+	return &mockFileHandle{filer: m, name: name, node: n}, nil
+}
+
+func (m *mockFiler) Mkdir(name string, perm os.FileMode) error {
+	name = path.Clean(name)
+	if _, ok := m.nodes[name]; ok {
+		return &os.PathError{Op: "mkdir", Path: name, Err: os.ErrExist}
+	}
+	if err := m.checkParent("mkdir", name); err != nil {
+		return err
+	}
+	now := time.Now()
+	m.nodes[name] = &mockNode{mode: os.ModeDir | perm&os.ModePerm, atime: now, mtime: now}
+	return nil
+}
+
+func (m *mockFiler) mkdirAll(name string) {
+	if _, ok := m.nodes[name]; ok {
+		return
+	}
+	m.mkdirAll(path.Dir(name))
+	m.Mkdir(name, 0755)
+}
+
+func (m *mockFiler) Remove(name string) error {
+	name = path.Clean(name)
+	n, err := m.lookup("remove", name)
+	if err != nil {
+		return err
+	}
+	if n.mode.IsDir() && len(m.children(name)) > 0 {
+		return &os.PathError{Op: "remove", Path: name, Err: syscall.ENOTEMPTY}
+	}
+	delete(m.nodes, name)
+	return nil
+}
+
+func (m *mockFiler) Rename(oldpath, newpath string) error {
+	oldpath, newpath = path.Clean(oldpath), path.Clean(newpath)
+	if _, ok := m.nodes[oldpath]; !ok {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: os.ErrNotExist}
+	}
+	if err := m.checkParent("rename", newpath); err != nil {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: err.(*os.PathError).Err}
+	}
+	for p, n := range m.nodes {
+		if p == oldpath || strings.HasPrefix(p, oldpath+"/") {
+			delete(m.nodes, p)
+			m.nodes[newpath+strings.TrimPrefix(p, oldpath)] = n
+		}
+	}
+	return nil
+}
+
+func (m *mockFiler) Stat(name string) (os.FileInfo, error) {
+	n, err := m.lookup("stat", name)
+	if err != nil {
+		return nil, err
+	}
+	return &mockInfo{path.Base(path.Clean(name)), *n}, nil
+}
+
+func (m *mockFiler) Chmod(name string, mode os.FileMode) error {
+	n, err := m.lookup("chmod", name)
+	if err != nil {
+		return err
+	}
+	n.mode = n.mode&os.ModeType | mode&^os.ModeType
+	return nil
+}
+
+func (m *mockFiler) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	n, err := m.lookup("chtimes", name)
+	if err != nil {
+		return err
+	}
+	n.atime, n.mtime = atime, mtime
+	return nil
+}
+
+func (m *mockFiler) Chown(name string, uid, gid int) error {
+	n, err := m.lookup("chown", name)
+	if err != nil {
+		return err
+	}
+	n.uid, n.gid = uid, gid
+	return nil
+}
+
+func (m *mockFiler) Lstat(name string) (os.FileInfo, error) {
+	n, ok := m.nodes[m.resolve(name, false)]
+	if !ok {
+		return nil, &os.PathError{Op: "lstat", Path: name, Err: os.ErrNotExist}
+	}
+	return &mockInfo{path.Base(path.Clean(name)), *n}, nil
+}
+
+func (m *mockFiler) Lchown(name string, uid, gid int) error {
+	n, ok := m.nodes[m.resolve(name, false)]
+	if !ok {
+		return &os.PathError{Op: "lchown", Path: name, Err: os.ErrNotExist}
+	}
+	n.uid, n.gid = uid, gid
+	return nil
+}
+
+func (m *mockFiler) Readlink(name string) (string, error) {
+	n, ok := m.nodes[m.resolve(name, false)]
+	if !ok || n.mode&os.ModeSymlink == 0 {
+		return "", &os.PathError{Op: "readlink", Path: name, Err: syscall.EINVAL}
+	}
+	return n.target, nil
+}
+
+func (m *mockFiler) Symlink(oldname, newname string) error {
+	newname = m.resolve(newname, false)
+	if _, ok := m.nodes[newname]; ok {
+		return &os.LinkError{Op: "symlink", Old: oldname, New: newname, Err: os.ErrExist}
+	}
+	if err := m.checkParent("symlink", newname); err != nil {
+		return &os.LinkError{Op: "symlink", Old: oldname, New: newname, Err: os.ErrNotExist}
+	}
+	now := time.Now()
+	m.nodes[newname] = &mockNode{mode: os.ModeSymlink | 0777, target: oldname, atime: now, mtime: now}
+	return nil
+}
+
+// newTestSymlinkFS - returns a `SymlinkFileSystem` backed by a fresh
+// `mockFiler`.
+func newTestSymlinkFS() SymlinkFileSystem {
+	m := newMockFiler()
+	return ExtendSymlinkFiler(m, m)
+}
+
+// mockInfo - is the `os.FileInfo` returned by `mockFiler` and `mockFileHandle`.
+// It holds a copy of the node so it is not affected by later changes.
+type mockInfo struct {
+	name string
+	node mockNode
+}
+
+func (i *mockInfo) Name() string       { return i.name }
+func (i *mockInfo) Size() int64        { return int64(len(i.node.data)) }
+func (i *mockInfo) Mode() os.FileMode  { return i.node.mode }
+func (i *mockInfo) ModTime() time.Time { return i.node.mtime }
+func (i *mockInfo) IsDir() bool        { return i.node.mode.IsDir() }
+func (i *mockInfo) Sys() interface{}   { return nil }
+
+// AccessTime and Owner - let `ChtimesPartial` and `ApplyMetadata` read the
+// access time and owner.
+func (i *mockInfo) AccessTime() time.Time { return i.node.atime }
+func (i *mockInfo) Owner() Owner          { return Owner{i.node.uid, i.node.gid} }
+
+// mockFileHandle - is the `File` returned by `mockFiler.OpenFile`.
+type mockFileHandle struct {
+	filer   *mockFiler
+	name    string
+	node    *mockNode
+	offset  int64
+	listing []string
+	listed  bool
+}
+
+func (f *mockFileHandle) Name() string { return f.name }
+
+func (f *mockFileHandle) Read(b []byte) (int, error) {
+	n, err := f.ReadAt(b, f.offset)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *mockFileHandle) ReadAt(b []byte, off int64) (int, error) {
+	if f.node.mode.IsDir() {
+		return 0, &os.PathError{Op: "read", Path: f.name, Err: syscall.EISDIR}
+	}
+	if off >= int64(len(f.node.data)) {
+		return 0, io.EOF
+	}
+	n := copy(b, f.node.data[off:])
+	if n < len(b) {
+		return n, io.EOF
+	}
+	return n, nil
+}
+
+func (f *mockFileHandle) Write(b []byte) (int, error) {
+	n, err := f.WriteAt(b, f.offset)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *mockFileHandle) WriteAt(b []byte, off int64) (int, error) {
+	if f.node.mode.IsDir() {
+		return 0, &os.PathError{Op: "write", Path: f.name, Err: syscall.EISDIR}
+	}
+	end := off + int64(len(b))
+	if end > int64(len(f.node.data)) {
+		data := make([]byte, end)
+		copy(data, f.node.data)
+		f.node.data = data
+	}
+	copy(f.node.data[off:], b)
+	f.node.mtime = time.Now()
+	return len(b), nil
+}
+
+func (f *mockFileHandle) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+func (f *mockFileHandle) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += int64(len(f.node.data))
+	}
+	if offset < 0 {
+		return f.offset, &os.PathError{Op: "seek", Path: f.name, Err: syscall.EINVAL}
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *mockFileHandle) Truncate(size int64) error {
+	data := make([]byte, size)
+	copy(data, f.node.data)
+	f.node.data = data
+	return nil
+}
+
+func (f *mockFileHandle) Readdir(n int) ([]os.FileInfo, error) {
+	names, err := f.Readdirnames(n)
+	infos := make([]os.FileInfo, len(names))
+	for i, name := range names {
+		p := path.Join(f.name, name)
+		infos[i] = &mockInfo{name, *f.filer.nodes[p]}
+	}
+	return infos, err
+}
+
+func (f *mockFileHandle) Readdirnames(n int) ([]string, error) {
+	if !f.node.mode.IsDir() {
+		return nil, &os.PathError{Op: "readdirent", Path: f.name, Err: syscall.ENOTDIR}
+	}
+	if !f.listed {
+		f.listing = f.filer.children(f.name)
+		f.listed = true
+	}
+	if n <= 0 {
+		names := f.listing
+		f.listing = nil
+		return names, nil
+	}
+	if len(f.listing) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(f.listing) {
+		n = len(f.listing)
+	}
+	names := f.listing[:n]
+	f.listing = f.listing[n:]
+	return names, nil
+}
+
+func (f *mockFileHandle) Stat() (os.FileInfo, error) {
+	return &mockInfo{path.Base(f.name), *f.node}, nil
+}
+
+func (f *mockFileHandle) Sync() error  { return nil }
+func (f *mockFileHandle) Close() error { return nil }
@@ -0,0 +1,281 @@
+package absfs
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Mount - returns a FileSystem that routes every operation on a path at or
+// below one of the keys of `mounts` to the corresponding FileSystem, with the
+// mount point stripped from the path, and every other operation to `base`.
+// Mount points are absolute paths; when they are nested the longest matching
+// mount point wins. For example, an in-memory filesystem mounted at "/tmp"
+// over an OS backed `base` receives "/tmp/x" as "/x".
+//
+// Directory listings include the mount points directly below the directory,
+// which shadow any entries of the same name in the underlying filesystem.
+// The parent directory of a mount point should exist. Mount points cannot be
+// removed or renamed, and renaming between filesystems fails with an
+// `*os.LinkError` wrapping `syscall.EXDEV`, so `Move` falls back to copying.
+//
+// The returned FileSystem keeps its own working directory and is not safe for
+// concurrent use.
+func Mount(base FileSystem, mounts map[string]FileSystem) FileSystem {
+	m := &mountfs{cwd: "/", base: base}
+	for prefix, fsys := range mounts {
+		m.points = append(m.points, mountPoint{filepath.Clean("/" + prefix), fsys})
+	}
+	sort.Slice(m.points, func(i, j int) bool {
+		a, b := m.points[i].prefix, m.points[j].prefix
+		if len(a) != len(b) {
+			return len(a) > len(b)
+		}
+		return a < b
+	})
+	return m
+}
+
+type mountPoint struct {
+	prefix string
+	fsys   FileSystem
+}
+
+type mountfs struct {
+	cwd    string
+	base   FileSystem
+	points []mountPoint // longest prefix first
+}
+
+func (m *mountfs) abs(name string) string {
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(m.cwd, name)
+	}
+	return filepath.Clean(name)
+}
+
+// route - returns the filesystem responsible for `name`, the path within it,
+// and whether `name` is itself a mount point.
+func (m *mountfs) route(name string) (FileSystem, string, bool) {
+	name = m.abs(name)
+	sep := string(filepath.Separator)
+	for _, p := range m.points {
+		switch {
+		case name == p.prefix:
+			return p.fsys, sep, true
+		case p.prefix == sep:
+			return p.fsys, name, false
+		case strings.HasPrefix(name, p.prefix+sep):
+			return p.fsys, name[len(p.prefix):], false
+		}
+	}
+	return m.base, name, false
+}
+
+// pathErrorAt - reports an `*os.PathError` against `name` rather than the path
+// within the mounted filesystem.
+func pathErrorAt(err error, name string) error {
+	if perr, ok := err.(*os.PathError); ok {
+		return &os.PathError{Op: perr.Op, Path: name, Err: perr.Err}
+	}
+	return err
+}
+
+func (m *mountfs) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	fsys, p, _ := m.route(name)
+	f, err := fsys.OpenFile(p, flag, perm)
+	if err != nil {
+		return f, pathErrorAt(err, name)
+	}
+	dir := m.abs(name)
+	if info, err := f.Stat(); err == nil && info.IsDir() && len(m.children(dir)) > 0 {
+		return &mountDir{File: f, m: m, path: dir}, nil
+	}
+	return f, nil
+}
+
+// children - returns the mount points directly below `dir`, by name.
+func (m *mountfs) children(dir string) map[string]mountPoint {
+	children := make(map[string]mountPoint)
+	for _, p := range m.points {
+		if p.prefix != dir && filepath.Dir(p.prefix) == dir {
+			children[filepath.Base(p.prefix)] = p
+		}
+	}
+	return children
+}
+
+func (m *mountfs) Mkdir(name string, perm os.FileMode) error {
+	fsys, p, _ := m.route(name)
+	return pathErrorAt(fsys.Mkdir(p, perm), name)
+}
+
+func (m *mountfs) Remove(name string) error {
+	fsys, p, mountpoint := m.route(name)
+	if mountpoint {
+		return &os.PathError{Op: "remove", Path: name, Err: syscall.EBUSY}
+	}
+	return pathErrorAt(fsys.Remove(p), name)
+}
+
+func (m *mountfs) Rename(oldpath, newpath string) error {
+	oldfs, oldp, oldmount := m.route(oldpath)
+	newfs, newp, newmount := m.route(newpath)
+	switch {
+	case oldmount || newmount:
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EBUSY}
+	case !sameFS(oldfs, newfs):
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EXDEV}
+	}
+	return linkError("rename", oldpath, newpath, oldfs.Rename(oldp, newp))
+}
+
+func (m *mountfs) Stat(name string) (os.FileInfo, error) {
+	fsys, p, _ := m.route(name)
+	info, err := fsys.Stat(p)
+	if err != nil {
+		return nil, pathErrorAt(err, name)
+	}
+	return info, nil
+}
+
+func (m *mountfs) Chmod(name string, mode os.FileMode) error {
+	fsys, p, _ := m.route(name)
+	return pathErrorAt(fsys.Chmod(p, mode), name)
+}
+
+func (m *mountfs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	fsys, p, _ := m.route(name)
+	return pathErrorAt(fsys.Chtimes(p, atime, mtime), name)
+}
+
+func (m *mountfs) Chown(name string, uid, gid int) error {
+	fsys, p, _ := m.route(name)
+	return pathErrorAt(fsys.Chown(p, uid, gid), name)
+}
+
+func (m *mountfs) Separator() uint8 {
+	return m.base.Separator()
+}
+
+func (m *mountfs) ListSeparator() uint8 {
+	return m.base.ListSeparator()
+}
+
+func (m *mountfs) Chdir(dir string) error {
+	dir = m.abs(dir)
+	info, err := m.Stat(dir)
+	if err != nil {
+		return &os.PathError{Op: "chdir", Path: dir, Err: unwrapPathError(err)}
+	}
+	if !info.IsDir() {
+		return &os.PathError{Op: "chdir", Path: dir, Err: syscall.ENOTDIR}
+	}
+	m.cwd = dir
+	return nil
+}
+
+func (m *mountfs) Getwd() (dir string, err error) {
+	return m.cwd, nil
+}
+
+func (m *mountfs) TempDir() string {
+	return m.base.TempDir()
+}
+
+func (m *mountfs) Open(name string) (File, error) {
+	return m.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (m *mountfs) Create(name string) (File, error) {
+	return m.OpenFile(name, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0666)
+}
+
+func (m *mountfs) MkdirAll(name string, perm os.FileMode) error {
+	fsys, p, _ := m.route(name)
+	return pathErrorAt(fsys.MkdirAll(p, perm), name)
+}
+
+func (m *mountfs) RemoveAll(path string) (err error) {
+	fsys, p, mountpoint := m.route(path)
+	if mountpoint {
+		return &os.PathError{Op: "removeall", Path: path, Err: syscall.EBUSY}
+	}
+	return pathErrorAt(fsys.RemoveAll(p), path)
+}
+
+func (m *mountfs) Truncate(name string, size int64) error {
+	fsys, p, _ := m.route(name)
+	return pathErrorAt(fsys.Truncate(p, size), name)
+}
+
+// mountDir - is a directory handle whose listing includes the mount points
+// directly below it.
+type mountDir struct {
+	File
+	m     *mountfs
+	path  string
+	names []string
+	read  bool
+}
+
+func (d *mountDir) Readdirnames(n int) ([]string, error) {
+	if !d.read {
+		names, err := ReaddirnamesAll(d.File)
+		if err != nil {
+			return nil, err
+		}
+		children := d.m.children(d.path)
+		for _, name := range names {
+			if _, ok := children[name]; !ok {
+				d.names = append(d.names, name)
+			}
+		}
+		for name := range children {
+			d.names = append(d.names, name)
+		}
+		sort.Strings(d.names)
+		d.read = true
+	}
+	if n <= 0 {
+		names := d.names
+		d.names = nil
+		return names, nil
+	}
+	if len(d.names) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(d.names) {
+		n = len(d.names)
+	}
+	names := d.names[:n]
+	d.names = d.names[n:]
+	return names, nil
+}
+
+func (d *mountDir) Readdir(n int) ([]os.FileInfo, error) {
+	names, err := d.Readdirnames(n)
+	infos := make([]os.FileInfo, 0, len(names))
+	for _, name := range names {
+		info, serr := d.m.Stat(filepath.Join(d.path, name))
+		if serr != nil {
+			return infos, serr
+		}
+		infos = append(infos, namedInfo{info, name})
+	}
+	return infos, err
+}
+
+// namedInfo - reports `name` as the name of a mounted filesystem's root.
+type namedInfo struct {
+	os.FileInfo
+	name string
+}
+
+func (i namedInfo) Name() string {
+	return i.name
+}
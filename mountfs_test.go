@@ -0,0 +1,99 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"syscall"
+	"testing"
+)
+
+func TestMount(t *testing.T) {
+	base, tmp, cache := newTestFS(), newTestFS(), newTestFS()
+	for _, dir := range []string{"/tmp", "/home", "/var"} {
+		if err := base.Mkdir(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeTestFile(t, base, "/etc", "base")
+	writeTestFile(t, base, "/tmp/hidden", "shadowed by the mount")
+	fsys := Mount(base, map[string]FileSystem{
+		"/tmp":       tmp,
+		"/var/cache": cache,
+		"/tmp/inner": cache,
+	})
+
+	writeTestFile(t, fsys, "/tmp/scratch", "tmp")
+	if got := readTestFile(t, tmp, "/scratch"); got != "tmp" {
+		t.Errorf("mounted file: got %q", got)
+	}
+	writeTestFile(t, fsys, "/tmp/inner/x", "inner")
+	if got := readTestFile(t, cache, "/x"); got != "inner" {
+		t.Errorf("longest prefix: got %q", got)
+	}
+	if got := readTestFile(t, fsys, "/var/cache/x"); got != "inner" {
+		t.Errorf("second mount of the same filesystem: got %q", got)
+	}
+
+	if names := listTestDir(t, fsys, "/"); !reflect.DeepEqual(names, []string{"etc", "home", "tmp", "var"}) {
+		t.Errorf("/: got %v", names)
+	}
+	if names := listTestDir(t, fsys, "/var"); !reflect.DeepEqual(names, []string{"cache"}) {
+		t.Errorf("/var: got %v, expected the mount point", names)
+	}
+	if names := listTestDir(t, fsys, "/tmp"); !reflect.DeepEqual(names, []string{"inner", "scratch"}) {
+		t.Errorf("/tmp: got %v", names)
+	}
+
+	f, err := fsys.Open("/var")
+	if err != nil {
+		t.Fatal(err)
+	}
+	infos, err := f.Readdir(-1)
+	f.Close()
+	if err != nil || len(infos) != 1 || infos[0].Name() != "cache" || !infos[0].IsDir() {
+		t.Errorf("Readdir: got %v, %v", infos, err)
+	}
+
+	var lerr *os.LinkError
+	if err := fsys.Rename("/tmp/scratch", "/home/scratch"); !errors.As(err, &lerr) || !errors.Is(err, syscall.EXDEV) {
+		t.Errorf("got %v, expected an *os.LinkError wrapping %v", err, syscall.EXDEV)
+	}
+	if err := Move(fsys, "/home/scratch", fsys, "/tmp/scratch"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, base, "/home/scratch"); got != "tmp" {
+		t.Errorf("after Move got %q", got)
+	}
+	if err := fsys.Remove("/tmp"); !errors.Is(err, syscall.EBUSY) {
+		t.Errorf("got %v, expected %v", err, syscall.EBUSY)
+	}
+	if err := fsys.Chdir("/etc"); !errors.Is(err, syscall.ENOTDIR) {
+		t.Errorf("Chdir: got %v, expected %v", err, syscall.ENOTDIR)
+	}
+	if _, err := fsys.Stat("/tmp/missing"); err == nil || err.(*os.PathError).Path != "/tmp/missing" {
+		t.Errorf("got %v, expected the error to report the mounted path", err)
+	}
+}
+
+func TestMountRenameNotComparable(t *testing.T) {
+	fsys := Mount(sliceFS{FileSystem: newTestFS()}, map[string]FileSystem{
+		"/tmp": sliceFS{FileSystem: newTestFS()},
+	})
+	writeTestFile(t, fsys, "/tmp/file", "tmp")
+	if err := fsys.Rename("/tmp/file", "/file"); !errors.Is(err, syscall.EXDEV) {
+		t.Errorf("got %v, expected %v", err, syscall.EXDEV)
+	}
+}
+
+func TestMountListingPaged(t *testing.T) {
+	base := newTestFS()
+	createFiles(t, base, "/a", "/b", "/c")
+	if err := base.Mkdir("/m", 0755); err != nil {
+		t.Fatal(err)
+	}
+	fsys := Mount(oneEntryDirFS{base}, map[string]FileSystem{"/m": newTestFS()})
+	if names := listTestDir(t, fsys, "/"); !reflect.DeepEqual(names, []string{"a", "b", "c", "m"}) {
+		t.Errorf("got %v, expected the full listing", names)
+	}
+}
@@ -0,0 +1,58 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// NoImplicitMkdir - returns a FileSystem that requires the parent directory
+// of a file to exist before it can be created. Some backends create missing
+// parent directories on `Create` and others do not; `NoImplicitMkdir` makes
+// `Create`, and `OpenFile` with `O_CREATE`, return an `*os.PathError` wrapping
+// `syscall.ENOENT` when the parent is missing, regardless of the backend.
+func NoImplicitMkdir(fs FileSystem) FileSystem {
+	return &nomkdirfs{fs}
+}
+
+type nomkdirfs struct {
+	FileSystem
+}
+
+func (fs *nomkdirfs) checkParent(name string) error {
+	if !filepath.IsAbs(name) {
+		wd, err := fs.Getwd()
+		if err != nil {
+			return err
+		}
+		name = filepath.Join(wd, name)
+	}
+	info, err := fs.Stat(filepath.Dir(filepath.Clean(name)))
+	if errors.Is(err, os.ErrNotExist) {
+		return &os.PathError{Op: "open", Path: name, Err: syscall.ENOENT}
+	}
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return &os.PathError{Op: "open", Path: name, Err: syscall.ENOTDIR}
+	}
+	return nil
+}
+
+func (fs *nomkdirfs) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	if flag&O_CREATE != 0 {
+		if err := fs.checkParent(name); err != nil {
+			return &InvalidFile{Path: name}, err
+		}
+	}
+	return fs.FileSystem.OpenFile(name, flag, perm)
+}
+
+func (fs *nomkdirfs) Create(name string) (File, error) {
+	if err := fs.checkParent(name); err != nil {
+		return &InvalidFile{Path: name}, err
+	}
+	return fs.FileSystem.Create(name)
+}
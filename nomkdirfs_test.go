@@ -0,0 +1,50 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestNoImplicitMkdir(t *testing.T) {
+	for _, lenient := range []bool{false, true} {
+		filer := newMockFiler()
+		filer.mkdirs = lenient
+		fsys := NoImplicitMkdir(ExtendFiler(filer))
+		if err := fsys.Mkdir("/dir", 0755); err != nil {
+			t.Fatal(err)
+		}
+
+		f, err := fsys.Create("/dir/file")
+		if err != nil {
+			t.Errorf("lenient %t: create in existing dir: %v", lenient, err)
+		} else {
+			f.Close()
+		}
+
+		_, err = fsys.Create("/missing/file")
+		if !errors.Is(err, syscall.ENOENT) {
+			t.Errorf("lenient %t: create in missing dir: got %v, expected ENOENT", lenient, err)
+		}
+		_, err = fsys.OpenFile("/missing/file", os.O_CREATE|os.O_WRONLY, 0644)
+		if !errors.Is(err, syscall.ENOENT) {
+			t.Errorf("lenient %t: openfile in missing dir: got %v, expected ENOENT", lenient, err)
+		}
+		if _, err := fsys.Stat("/missing"); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("lenient %t: parent directory was created", lenient)
+		}
+	}
+}
+
+func TestNoImplicitMkdirStatError(t *testing.T) {
+	base := &forbiddenFS{newTestFS(), "/secret"}
+	if err := base.Mkdir("/secret", 0755); err != nil {
+		t.Fatal(err)
+	}
+	fsys := NoImplicitMkdir(base)
+	_, err := fsys.Create("/secret/file")
+	if !errors.Is(err, os.ErrPermission) || errors.Is(err, syscall.ENOENT) {
+		t.Errorf("got %v, expected %v", err, os.ErrPermission)
+	}
+}
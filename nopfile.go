@@ -0,0 +1,126 @@
+package absfs
+
+import (
+	"io"
+	"os"
+	"syscall"
+	"time"
+)
+
+// NopFile - returns a read-only File backed by `r`, for tests and for passing
+// in-memory data such as a `bytes.Reader` or `strings.Reader` to code that
+// expects a File. `Read` reads from `r`, and `ReadAt` is passed on if `r`
+// implements `io.ReaderAt`. `Seek` fails with `syscall.ESPIPE`, like a pipe,
+// and all writes fail with `syscall.EBADF`, like a file opened read only.
+// `Readdir` and `Readdirnames` return no entries, and `Close` and `Sync` do
+// nothing.
+//
+// The file has an empty name; use `WithName` to give it one. `Stat` returns a
+// synthetic regular file with mode 0444 and a size of 0, meaning unknown.
+func NopFile(r io.Reader) File {
+	return &nopFile{r: r}
+}
+
+type nopFile struct {
+	r    io.Reader
+	name string
+}
+
+func (f *nopFile) Name() string {
+	return f.name
+}
+
+func (f *nopFile) Read(b []byte) (int, error) {
+	return f.r.Read(b)
+}
+
+func (f *nopFile) ReadAt(b []byte, off int64) (int, error) {
+	if ra, ok := f.r.(io.ReaderAt); ok {
+		return ra.ReadAt(b, off)
+	}
+	return 0, &os.PathError{Op: "read", Path: f.name, Err: syscall.ESPIPE}
+}
+
+func (f *nopFile) Seek(offset int64, whence int) (int64, error) {
+	return 0, &os.PathError{Op: "seek", Path: f.name, Err: syscall.ESPIPE}
+}
+
+func (f *nopFile) Write(b []byte) (int, error) {
+	return 0, &os.PathError{Op: "write", Path: f.name, Err: syscall.EBADF}
+}
+
+func (f *nopFile) WriteAt(b []byte, off int64) (int, error) {
+	return 0, &os.PathError{Op: "write", Path: f.name, Err: syscall.EBADF}
+}
+
+func (f *nopFile) WriteString(s string) (int, error) {
+	return 0, &os.PathError{Op: "write", Path: f.name, Err: syscall.EBADF}
+}
+
+func (f *nopFile) Truncate(size int64) error {
+	return &os.PathError{Op: "truncate", Path: f.name, Err: syscall.EBADF}
+}
+
+func (f *nopFile) Readdir(n int) ([]os.FileInfo, error) {
+	if n > 0 {
+		return nil, io.EOF
+	}
+	return nil, nil
+}
+
+func (f *nopFile) Readdirnames(n int) ([]string, error) {
+	if n > 0 {
+		return nil, io.EOF
+	}
+	return nil, nil
+}
+
+func (f *nopFile) Stat() (os.FileInfo, error) {
+	return nopInfo{f.name}, nil
+}
+
+func (f *nopFile) Sync() error {
+	return nil
+}
+
+func (f *nopFile) Close() error {
+	return nil
+}
+
+// nopInfo - describes a `NopFile`.
+type nopInfo struct {
+	name string
+}
+
+func (i nopInfo) Name() string       { return i.name }
+func (i nopInfo) Size() int64        { return 0 }
+func (i nopInfo) Mode() os.FileMode  { return 0444 }
+func (i nopInfo) ModTime() time.Time { return time.Time{} }
+func (i nopInfo) IsDir() bool        { return false }
+func (i nopInfo) Sys() interface{}   { return nil }
+
+// WithName - returns a File that behaves like `f` but reports `name` from
+// `Name` and as the name of the `os.FileInfo` returned by `Stat`.
+func WithName(f File, name string) File {
+	if nf, ok := f.(*nopFile); ok {
+		return &nopFile{r: nf.r, name: name}
+	}
+	return &namedFile{f, name}
+}
+
+type namedFile struct {
+	File
+	name string
+}
+
+func (f *namedFile) Name() string {
+	return f.name
+}
+
+func (f *namedFile) Stat() (os.FileInfo, error) {
+	info, err := f.File.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return namedInfo{info, f.name}, nil
+}
@@ -0,0 +1,81 @@
+package absfs
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestNopFile(t *testing.T) {
+	f := NopFile(strings.NewReader("hello world"))
+
+	data, err := io.ReadAll(f)
+	if err != nil || string(data) != "hello world" {
+		t.Errorf("got %q, %v, expected %q", data, err, "hello world")
+	}
+	buf := make([]byte, 5)
+	if n, err := f.ReadAt(buf, 6); n != 5 || err != nil || string(buf) != "world" {
+		t.Errorf("ReadAt: got %q, %v", buf[:n], err)
+	}
+	if _, err := f.Seek(0, io.SeekStart); !errors.Is(err, syscall.ESPIPE) {
+		t.Errorf("Seek: got %v, expected %v", err, syscall.ESPIPE)
+	}
+	if _, err := f.Write([]byte("x")); !errors.Is(err, syscall.EBADF) {
+		t.Errorf("Write: got %v, expected %v", err, syscall.EBADF)
+	}
+	if _, err := f.WriteString("x"); !errors.Is(err, syscall.EBADF) {
+		t.Errorf("WriteString: got %v, expected %v", err, syscall.EBADF)
+	}
+	if err := f.Truncate(0); !errors.Is(err, syscall.EBADF) {
+		t.Errorf("Truncate: got %v, expected %v", err, syscall.EBADF)
+	}
+	if infos, err := f.Readdir(-1); len(infos) != 0 || err != nil {
+		t.Errorf("Readdir: got %v, %v", infos, err)
+	}
+	if names, err := f.Readdirnames(1); len(names) != 0 || err != io.EOF {
+		t.Errorf("Readdirnames: got %v, %v", names, err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name() != "" || info.Size() != 0 || !info.Mode().IsRegular() {
+		t.Errorf("Stat: got %q, %d, %s", info.Name(), info.Size(), info.Mode())
+	}
+
+	if _, err := NopFile(io.LimitReader(strings.NewReader("x"), 1)).ReadAt(buf, 0); !errors.Is(err, syscall.ESPIPE) {
+		t.Errorf("ReadAt: got %v, expected %v", err, syscall.ESPIPE)
+	}
+}
+
+func TestWithName(t *testing.T) {
+	f := WithName(NopFile(strings.NewReader("data")), "/data.txt")
+	if f.Name() != "/data.txt" {
+		t.Errorf("got %q, expected %q", f.Name(), "/data.txt")
+	}
+	if _, err := f.Write(nil); err == nil || !strings.Contains(err.Error(), "/data.txt") {
+		t.Errorf("got %v, expected an error naming /data.txt", err)
+	}
+
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/file", "content")
+	g, err := fsys.Open("/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.Close()
+	f = WithName(g, "renamed")
+	if f.Name() != "renamed" {
+		t.Errorf("got %q, expected %q", f.Name(), "renamed")
+	}
+	info, err := f.Stat()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Name() != "renamed" || info.Size() != 7 {
+		t.Errorf("Stat: got %q, %d", info.Name(), info.Size())
+	}
+}
@@ -0,0 +1,152 @@
+package absfs
+
+import (
+	"os"
+	"strings"
+	"time"
+)
+
+// NormalizeOption - configures the FileSystem returned by
+// `NormalizeSeparators`.
+type NormalizeOption func(*normfs)
+
+// SlashResults - makes the FileSystem returned by `NormalizeSeparators`
+// return slash separated paths from `Getwd`, `File.Name`, and
+// `File.Readdirnames`, so callers see the same form whatever the separator
+// of the underlying filesystem.
+func SlashResults() NormalizeOption {
+	return func(n *normfs) {
+		n.slash = true
+	}
+}
+
+// NormalizeSeparators - returns a FileSystem that accepts both "/" and "\" as
+// separators in the paths it is given, converting them to the `Separator` of
+// `fsys` before delegating, so cross-platform code can use "/" everywhere,
+// even against a Windows host filesystem. On filesystems whose separator is
+// "/", a backslash is always treated as a separator, so names containing a
+// literal backslash can't be reached through the returned FileSystem.
+//
+// Paths returned by `fsys` are passed through unchanged unless the
+// `SlashResults` option is given.
+func NormalizeSeparators(fsys FileSystem, opts ...NormalizeOption) FileSystem {
+	n := &normfs{FileSystem: fsys}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+type normfs struct {
+	FileSystem
+	slash bool
+}
+
+// native - converts every slash and backslash in `name` to the separator of
+// the underlying filesystem.
+func (n *normfs) native(name string) string {
+	sep := n.Separator()
+	other := "\\"
+	if sep == '\\' {
+		other = "/"
+	}
+	return strings.ReplaceAll(name, other, string(sep))
+}
+
+// result - converts a path returned by the underlying filesystem to slashes
+// when `SlashResults` is set.
+func (n *normfs) result(p string) string {
+	if !n.slash {
+		return p
+	}
+	return toSlash(n.Separator(), p)
+}
+
+func (n *normfs) file(f File, err error) (File, error) {
+	if err != nil || !n.slash {
+		return f, err
+	}
+	return &normFile{f, n}, nil
+}
+
+func (n *normfs) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	return n.file(n.FileSystem.OpenFile(n.native(name), flag, perm))
+}
+
+func (n *normfs) Open(name string) (File, error) {
+	return n.file(n.FileSystem.Open(n.native(name)))
+}
+
+func (n *normfs) Create(name string) (File, error) {
+	return n.file(n.FileSystem.Create(n.native(name)))
+}
+
+func (n *normfs) Mkdir(name string, perm os.FileMode) error {
+	return n.FileSystem.Mkdir(n.native(name), perm)
+}
+
+func (n *normfs) MkdirAll(name string, perm os.FileMode) error {
+	return n.FileSystem.MkdirAll(n.native(name), perm)
+}
+
+func (n *normfs) Remove(name string) error {
+	return n.FileSystem.Remove(n.native(name))
+}
+
+func (n *normfs) RemoveAll(name string) error {
+	return n.FileSystem.RemoveAll(n.native(name))
+}
+
+func (n *normfs) Rename(oldpath, newpath string) error {
+	return n.FileSystem.Rename(n.native(oldpath), n.native(newpath))
+}
+
+func (n *normfs) Stat(name string) (os.FileInfo, error) {
+	return n.FileSystem.Stat(n.native(name))
+}
+
+func (n *normfs) Chmod(name string, mode os.FileMode) error {
+	return n.FileSystem.Chmod(n.native(name), mode)
+}
+
+func (n *normfs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	return n.FileSystem.Chtimes(n.native(name), atime, mtime)
+}
+
+func (n *normfs) Chown(name string, uid, gid int) error {
+	return n.FileSystem.Chown(n.native(name), uid, gid)
+}
+
+func (n *normfs) Truncate(name string, size int64) error {
+	return n.FileSystem.Truncate(n.native(name), size)
+}
+
+func (n *normfs) Chdir(dir string) error {
+	return n.FileSystem.Chdir(n.native(dir))
+}
+
+func (n *normfs) Getwd() (string, error) {
+	dir, err := n.FileSystem.Getwd()
+	if err != nil {
+		return dir, err
+	}
+	return n.result(dir), nil
+}
+
+// normFile - returns slash separated names when `SlashResults` is set.
+type normFile struct {
+	File
+	n *normfs
+}
+
+func (f *normFile) Name() string {
+	return f.n.result(f.File.Name())
+}
+
+func (f *normFile) Readdirnames(count int) ([]string, error) {
+	names, err := f.File.Readdirnames(count)
+	for i, name := range names {
+		names[i] = f.n.result(name)
+	}
+	return names, err
+}
@@ -0,0 +1,64 @@
+package absfs
+
+import (
+	"os"
+	"reflect"
+	"testing"
+)
+
+// backslashFS - reports a backslash separator and records the names it is
+// given, mapping them back to slashes for the mock.
+type backslashFS struct {
+	FileSystem
+	names []string
+}
+
+func (fs *backslashFS) Separator() uint8 { return '\\' }
+
+func (fs *backslashFS) Mkdir(name string, perm os.FileMode) error {
+	fs.names = append(fs.names, name)
+	return fs.FileSystem.Mkdir(toSlash('\\', name), perm)
+}
+
+func (fs *backslashFS) Getwd() (string, error) {
+	dir, err := fs.FileSystem.Getwd()
+	return fromSlash('\\', dir), err
+}
+
+func (fs *backslashFS) Chdir(dir string) error {
+	fs.names = append(fs.names, dir)
+	return fs.FileSystem.Chdir(toSlash('\\', dir))
+}
+
+func TestNormalizeSeparators(t *testing.T) {
+	backend := &backslashFS{FileSystem: newTestFS()}
+	fsys := NormalizeSeparators(backend)
+	for _, name := range []string{"/a", `\a\b`, "/a/b/c"} {
+		if err := fsys.Mkdir(name, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := fsys.Chdir("/a/b"); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{`\a`, `\a\b`, `\a\b\c`, `\a\b`}
+	if !reflect.DeepEqual(backend.names, expected) {
+		t.Errorf("got %q, expected %q", backend.names, expected)
+	}
+	if wd, _ := fsys.Getwd(); wd != `\a\b` {
+		t.Errorf("Getwd: got %q, expected %q", wd, `\a\b`)
+	}
+	if wd, _ := NormalizeSeparators(backend, SlashResults()).Getwd(); wd != "/a/b" {
+		t.Errorf("Getwd: got %q, expected %q", wd, "/a/b")
+	}
+
+	// on a slash separated filesystem backslashes are converted too
+	fsys = NormalizeSeparators(&sepFS{newTestFS(), '/'})
+	if err := fsys.MkdirAll(`\x\y`, 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, fsys, `\x\y\file`, "data")
+	if got := readTestFile(t, fsys, "/x/y/file"); got != "data" {
+		t.Errorf("got %q, expected %q", got, "data")
+	}
+}
@@ -0,0 +1,43 @@
+//go:build windows
+
+package absfs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNormalizeSeparatorsWindows(t *testing.T) {
+	root := t.TempDir()
+	fsys := NormalizeSeparators(ExtendFiler(OSFiler(root)), SlashResults())
+	if fsys.Separator() != '\\' {
+		t.Fatalf("got separator %q, expected %q", fsys.Separator(), '\\')
+	}
+
+	if err := fsys.MkdirAll("/dir/sub", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, fsys, "/dir/sub/file", "data")
+	if _, err := os.Stat(filepath.Join(root, "dir", "sub", "file")); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, fsys, `\dir\sub\file`); got != "data" {
+		t.Errorf("got %q, expected %q", got, "data")
+	}
+
+	if err := fsys.Chdir("/dir/sub"); err != nil {
+		t.Fatal(err)
+	}
+	if wd, _ := fsys.Getwd(); wd != "/dir/sub" {
+		t.Errorf("Getwd: got %q, expected %q", wd, "/dir/sub")
+	}
+	f, err := fsys.Open("file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if f.Name() != "/dir/sub/file" {
+		t.Errorf("Name: got %q, expected %q", f.Name(), "/dir/sub/file")
+	}
+}
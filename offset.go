@@ -0,0 +1,72 @@
+package absfs
+
+import "io"
+
+// Tell - returns the current offset of `f` without moving it. If `f` was
+// returned by `OffsetFile` the cached offset is returned without calling the
+// backend, otherwise it is found with `Seek(0, io.SeekCurrent)`.
+func Tell(f File) (int64, error) {
+	if t, ok := f.(teller); ok {
+		return t.Tell()
+	}
+	return f.Seek(0, io.SeekCurrent)
+}
+
+type teller interface {
+	Tell() (int64, error)
+}
+
+// OffsetFile - returns a File that keeps track of the offset of `f`, updating
+// it on every `Read`, `Write`, `WriteString`, and `Seek`, so that `Tell` can
+// answer without a call to the backend. Tools that do their own readahead or
+// record positions for resumable transfers can query the offset as often as
+// they like.
+//
+// The cached offset assumes that writes happen at the current offset, so it
+// is wrong for handles opened with `O_APPEND`, and it must not be moved by
+// using `f` directly. If the offset of `f` can't be found when it is wrapped,
+// `Tell` calls `Seek` until the first successful `Seek` through the wrapper.
+func OffsetFile(f File) File {
+	offset, err := f.Seek(0, io.SeekCurrent)
+	return &offsetFile{File: f, offset: offset, known: err == nil}
+}
+
+type offsetFile struct {
+	File
+	offset int64
+	known  bool
+}
+
+func (f *offsetFile) Tell() (int64, error) {
+	if !f.known {
+		return f.File.Seek(0, io.SeekCurrent)
+	}
+	return f.offset, nil
+}
+
+func (f *offsetFile) Read(b []byte) (int, error) {
+	n, err := f.File.Read(b)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *offsetFile) Write(b []byte) (int, error) {
+	n, err := f.File.Write(b)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *offsetFile) WriteString(s string) (int, error) {
+	n, err := f.File.WriteString(s)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *offsetFile) Seek(offset int64, whence int) (int64, error) {
+	ret, err := f.File.Seek(offset, whence)
+	if err != nil {
+		return ret, err
+	}
+	f.offset, f.known = ret, true
+	return ret, nil
+}
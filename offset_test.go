@@ -0,0 +1,54 @@
+package absfs
+
+import (
+	"io"
+	"testing"
+)
+
+func TestTell(t *testing.T) {
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/file", "0123456789")
+	f, err := fsys.OpenFile("/file", O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.Seek(3, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if pos, err := Tell(f); pos != 3 || err != nil {
+		t.Errorf("got %d, %v, expected 3", pos, err)
+	}
+
+	of := OffsetFile(f)
+	buf := make([]byte, 4)
+	steps := []func() error{
+		func() error { _, err := of.Read(buf); return err },
+		func() error { _, err := of.Write([]byte("ab")); return err },
+		func() error { _, err := of.ReadAt(buf, 0); return err },
+		func() error { _, err := of.Seek(-3, io.SeekCurrent); return err },
+		func() error { _, err := of.WriteString("xyz"); return err },
+		func() error { _, err := of.WriteAt([]byte("!"), 0); return err },
+		func() error { _, err := of.Seek(-1, io.SeekEnd); return err },
+		func() error { _, err := of.Read(buf); return err },
+		func() error { _, err := of.Read(buf); return err },
+		func() error { _, err := of.Seek(20, io.SeekStart); return err },
+		func() error { _, err := of.Write([]byte("end")); return err },
+	}
+	for i, step := range steps {
+		if err := step(); err != nil && err != io.EOF {
+			t.Fatalf("step %d: %v", i, err)
+		}
+		cached, err := Tell(of)
+		if err != nil {
+			t.Fatal(err)
+		}
+		actual, err := f.Seek(0, io.SeekCurrent)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cached != actual {
+			t.Errorf("step %d: cached offset %d, backend offset %d", i, cached, actual)
+		}
+	}
+}
@@ -0,0 +1,211 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// OSFiler - returns a `Filer` backed by the host filesystem below the
+// directory `root`. Names are virtual slash separated paths: "/" is `root`,
+// relative names are resolved against the Filer's own working directory,
+// and ".." elements are resolved lexically, so no name can refer to a
+// location above `root`. Symbolic links on disk are resolved one element at
+// a time with `SecureJoinFS` semantics: absolute link targets are taken
+// relative to `root`, and a link that climbs above `root` is an error
+// wrapping `ErrPathEscapes`. The final element of a name is not resolved by
+// `Remove` and `Rename`, so they act on a link rather than its target.
+//
+// Besides `Filer` it implements `Open`, `Create`, `Chdir`, `Getwd`, and
+// `TempDir`, so `ExtendFiler(OSFiler(root))` is a complete host backed
+// `FileSystem`, suitable for examples and conformance tests. Files report
+// their virtual names, and errors returned by the Filer carry virtual paths
+// rather than host paths.
+func OSFiler(root string) Filer {
+	return &osFiler{root: filepath.Clean(root), cwd: "/"}
+}
+
+type osFiler struct {
+	root string
+	cwd  string
+}
+
+// virtual - returns the cleaned, virtual-absolute form of `name`.
+func (f *osFiler) virtual(name string) string {
+	name = filepath.ToSlash(name)
+	if !path.IsAbs(name) {
+		name = path.Join(f.cwd, name)
+	}
+	return path.Clean("/" + name)
+}
+
+// host - returns the host path of the virtual path `name`, with any symbolic
+// links resolved below `root`. If `follow` is false a link in the final
+// element of `name` is left unresolved.
+func (f *osFiler) host(name string, follow bool) (string, error) {
+	if follow {
+		return secureJoin(filepath.Separator, hostLinker{}, f.root, name)
+	}
+	dir, file := path.Split(name)
+	p, err := secureJoin(filepath.Separator, hostLinker{}, f.root, dir)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(p, file), nil
+}
+
+// hostLinker - is the `SymLinker` of the host filesystem, used to resolve
+// links below the root of an `OSFiler`.
+type hostLinker struct{}
+
+func (hostLinker) Lstat(name string) (os.FileInfo, error) { return os.Lstat(name) }
+func (hostLinker) Lchown(name string, uid, gid int) error { return os.Lchown(name, uid, gid) }
+func (hostLinker) Readlink(name string) (string, error)   { return os.Readlink(name) }
+func (hostLinker) Symlink(oldname, newname string) error  { return os.Symlink(oldname, newname) }
+
+// osError - replaces the host path in an `*os.PathError` with the virtual
+// name the caller used.
+func osError(err error, name string) error {
+	var perr *os.PathError
+	if errors.As(err, &perr) {
+		return &os.PathError{Op: perr.Op, Path: name, Err: perr.Err}
+	}
+	return err
+}
+
+func (f *osFiler) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	v := f.virtual(name)
+	p, err := f.host(v, true)
+	if err == nil {
+		var file *os.File
+		if file, err = os.OpenFile(p, flag, perm); err == nil {
+			return &osFile{file, v}, nil
+		}
+	}
+	err = osError(err, name)
+	return &InvalidFile{Path: name, Err: err}, err
+}
+
+func (f *osFiler) Mkdir(name string, perm os.FileMode) error {
+	p, err := f.host(f.virtual(name), true)
+	if err != nil {
+		return osError(err, name)
+	}
+	return osError(os.Mkdir(p, perm), name)
+}
+
+func (f *osFiler) Remove(name string) error {
+	v := f.virtual(name)
+	if v == "/" {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrPermission}
+	}
+	p, err := f.host(v, false)
+	if err != nil {
+		return osError(err, name)
+	}
+	return osError(os.Remove(p), name)
+}
+
+func (f *osFiler) Rename(oldpath, newpath string) error {
+	oldp, err := f.host(f.virtual(oldpath), false)
+	if err != nil {
+		return linkError("rename", oldpath, newpath, err)
+	}
+	newp, err := f.host(f.virtual(newpath), false)
+	if err != nil {
+		return linkError("rename", oldpath, newpath, err)
+	}
+	err = os.Rename(oldp, newp)
+	var lerr *os.LinkError
+	if errors.As(err, &lerr) {
+		return &os.LinkError{Op: lerr.Op, Old: oldpath, New: newpath, Err: lerr.Err}
+	}
+	return err
+}
+
+func (f *osFiler) Stat(name string) (os.FileInfo, error) {
+	p, err := f.host(f.virtual(name), true)
+	if err != nil {
+		return nil, osError(err, name)
+	}
+	info, err := os.Stat(p)
+	if err != nil {
+		return nil, osError(err, name)
+	}
+	return info, nil
+}
+
+func (f *osFiler) Chmod(name string, mode os.FileMode) error {
+	p, err := f.host(f.virtual(name), true)
+	if err != nil {
+		return osError(err, name)
+	}
+	return osError(os.Chmod(p, mode), name)
+}
+
+func (f *osFiler) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	p, err := f.host(f.virtual(name), true)
+	if err != nil {
+		return osError(err, name)
+	}
+	return osError(os.Chtimes(p, atime, mtime), name)
+}
+
+func (f *osFiler) Chown(name string, uid, gid int) error {
+	p, err := f.host(f.virtual(name), true)
+	if err != nil {
+		return osError(err, name)
+	}
+	return osError(os.Chown(p, uid, gid), name)
+}
+
+func (f *osFiler) Open(name string) (File, error) {
+	return f.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (f *osFiler) Create(name string) (File, error) {
+	return f.OpenFile(name, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0666)
+}
+
+func (f *osFiler) Chdir(dir string) error {
+	v := f.virtual(dir)
+	info, err := f.Stat(v)
+	if err != nil {
+		return &os.PathError{Op: "chdir", Path: dir, Err: errors.Unwrap(err)}
+	}
+	if !info.IsDir() {
+		return &os.PathError{Op: "chdir", Path: dir, Err: syscall.ENOTDIR}
+	}
+	f.cwd = v
+	return nil
+}
+
+func (f *osFiler) Getwd() (dir string, err error) {
+	return f.cwd, nil
+}
+
+// TempDir - returns "/tmp", which is not created automatically.
+func (f *osFiler) TempDir() string {
+	return "/tmp"
+}
+
+// osFile - reports the virtual name of an `*os.File`.
+type osFile struct {
+	*os.File
+	name string
+}
+
+func (f *osFile) Name() string {
+	return f.name
+}
+
+func (f *osFile) Chmod(mode os.FileMode) error {
+	return osError(f.File.Chmod(mode), f.name)
+}
+
+func (f *osFile) Chown(uid, gid int) error {
+	return osError(f.File.Chown(uid, gid), f.name)
+}
@@ -0,0 +1,128 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+)
+
+func TestOSFiler(t *testing.T) {
+	root := t.TempDir()
+	fsys := ExtendFiler(OSFiler(root))
+
+	if err := fsys.MkdirAll("/dir/sub", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, fsys, "/dir/sub/file", "data")
+	if data, err := os.ReadFile(filepath.Join(root, "dir", "sub", "file")); err != nil || string(data) != "data" {
+		t.Fatalf("host file: got %q, %v", data, err)
+	}
+
+	if err := fsys.Chdir("/dir"); err != nil {
+		t.Fatal(err)
+	}
+	if wd, _ := fsys.Getwd(); wd != "/dir" {
+		t.Errorf("Getwd: got %q, expected %q", wd, "/dir")
+	}
+	f, err := fsys.Open("sub/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Name() != "/dir/sub/file" {
+		t.Errorf("Name: got %q, expected the virtual name", f.Name())
+	}
+	f.Close()
+
+	if err := fsys.Rename("/dir/sub/file", "/moved"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, fsys, "/moved"); got != "data" {
+		t.Errorf("got %q after rename", got)
+	}
+	if err := fsys.RemoveAll("/dir"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "dir")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected the host directory to be removed", err)
+	}
+}
+
+func TestOSFilerConfined(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "root")
+	if err := os.Mkdir(root, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(parent, "secret"), []byte("secret"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	fsys := ExtendFiler(OSFiler(root))
+	for _, name := range []string{"/../secret", "../secret", "/a/../../secret"} {
+		_, err := fsys.Stat(name)
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("Stat(%q): got %v, expected %v", name, err, os.ErrNotExist)
+		}
+		var perr *os.PathError
+		if errors.As(err, &perr) && perr.Path != name {
+			t.Errorf("Stat(%q): error reports %q, expected the virtual name", name, perr.Path)
+		}
+	}
+}
+
+func TestOSFilerSymlinks(t *testing.T) {
+	parent := t.TempDir()
+	root := filepath.Join(parent, "root")
+	if err := os.MkdirAll(filepath.Join(root, "dir"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	for name, data := range map[string]string{
+		filepath.Join(parent, "secret"):    "secret",
+		filepath.Join(root, "dir", "file"): "data",
+	} {
+		if err := os.WriteFile(name, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	links := map[string]string{
+		"inside": "dir",
+		"up":     filepath.Join("..", "secret"),
+		"abs":    filepath.Join(parent, "secret"),
+	}
+	for link, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, link)); err != nil {
+			t.Skip(err)
+		}
+	}
+
+	fsys := ExtendFiler(OSFiler(root))
+	if got := readTestFile(t, fsys, "/inside/file"); got != "data" {
+		t.Errorf("link inside root: got %q, expected %q", got, "data")
+	}
+	if _, err := fsys.Stat("/up"); !errors.Is(err, ErrPathEscapes) {
+		t.Errorf("link above root: got %v, expected %v", err, ErrPathEscapes)
+	}
+	if _, err := fsys.Open("/abs"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("absolute link: got %v, expected it to be resolved below root", err)
+	}
+	if _, err := fsys.Create("/up"); !errors.Is(err, ErrPathEscapes) {
+		t.Errorf("create through a link above root: got %v, expected %v", err, ErrPathEscapes)
+	}
+	if data, _ := os.ReadFile(filepath.Join(parent, "secret")); string(data) != "secret" {
+		t.Errorf("file outside root changed to %q", data)
+	}
+
+	// Remove acts on the link, not its target
+	if err := fsys.Remove("/inside"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(filepath.Join(root, "dir", "file")); err != nil {
+		t.Errorf("link target removed: %v", err)
+	}
+
+	if err := fsys.Chdir("/dir/file"); !errors.Is(err, syscall.ENOTDIR) {
+		t.Errorf("Chdir: got %v, expected %v", err, syscall.ENOTDIR)
+	}
+}
@@ -0,0 +1,458 @@
+package absfs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"syscall"
+	"time"
+)
+
+// Overlay - returns a FileSystem implementing copy-on-write union semantics
+// over two layers. Reads resolve in `upper` first and then in `lower`. All
+// writes, including `Create`, go to `upper`; files and directories that only
+// exist in `lower` are copied up before they are modified. `Remove` records a
+// whiteout so the removed path in `lower` is masked from then on, and `lower`
+// is never modified.
+//
+// Directory listings merge the entries of both layers, deduplicated by name
+// and without whited-out names. A file in `upper` shadows a directory of the
+// same name in `lower` (and everything below it), and a directory in `upper`
+// shadows a file of the same name in `lower`.
+//
+// Whiteouts are kept in memory for the lifetime of the returned FileSystem.
+// The overlay is not safe for concurrent use; wrap it with `Synchronized` if
+// it must be shared between goroutines.
+func Overlay(upper, lower FileSystem) FileSystem {
+	return &overlayfs{
+		cwd:       "/",
+		upper:     upper,
+		lower:     lower,
+		whiteouts: make(map[string]bool),
+	}
+}
+
+type overlayfs struct {
+	cwd       string
+	upper     FileSystem
+	lower     FileSystem
+	whiteouts map[string]bool
+}
+
+func (o *overlayfs) abs(name string) string {
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(o.cwd, name)
+	}
+	return filepath.Clean(name)
+}
+
+// lowerVisible - reports whether `name` in the lower layer is not masked by a
+// whiteout or by a non-directory in the upper layer.
+func (o *overlayfs) lowerVisible(name string) bool {
+	for p := name; ; p = filepath.Dir(p) {
+		if o.whiteouts[p] {
+			return false
+		}
+		if p != name {
+			if info, err := o.upper.Stat(p); err == nil && !info.IsDir() {
+				return false
+			}
+		}
+		if filepath.Dir(p) == p {
+			return true
+		}
+	}
+}
+
+// stat - returns the union FileInfo of `name` and whether it came from the
+// upper layer.
+func (o *overlayfs) stat(name string) (os.FileInfo, bool, error) {
+	info, err := o.upper.Stat(name)
+	if err == nil {
+		return info, true, nil
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return nil, false, err
+	}
+	if o.lowerVisible(name) {
+		info, err = o.lower.Stat(name)
+		if err == nil {
+			return info, false, nil
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, false, err
+		}
+	}
+	return nil, false, &os.PathError{Op: "stat", Path: name, Err: os.ErrNotExist}
+}
+
+// names - returns the sorted, merged entry names of directory `name`.
+func (o *overlayfs) names(name string) ([]string, error) {
+	set := make(map[string]bool)
+	upperInfo, err := o.upper.Stat(name)
+	if err == nil && upperInfo.IsDir() {
+		names, err := readDirNames(o.upper, name)
+		if err != nil {
+			return nil, err
+		}
+		for _, n := range names {
+			set[n] = true
+		}
+	}
+	if err != nil || upperInfo.IsDir() {
+		if o.lowerVisible(name) {
+			if info, err := o.lower.Stat(name); err == nil && info.IsDir() {
+				names, err := readDirNames(o.lower, name)
+				if err != nil {
+					return nil, err
+				}
+				for _, n := range names {
+					if !set[n] && o.lowerVisible(filepath.Join(name, n)) {
+						set[n] = true
+					}
+				}
+			}
+		}
+	}
+
+	names := make([]string, 0, len(set))
+	for n := range set {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// copyUp - copies `name` from the lower layer into the upper layer, creating
+// any missing parent directories. Directories are copied without their
+// contents unless `recursive` is set.
+func (o *overlayfs) copyUp(name string, recursive bool) error {
+	info, inUpper, err := o.stat(name)
+	if err != nil {
+		return err
+	}
+	if inUpper && !(recursive && info.IsDir()) {
+		return nil
+	}
+	if !inUpper {
+		if dir := filepath.Dir(name); dir != name {
+			if err := o.copyUp(dir, false); err != nil {
+				return err
+			}
+		}
+		if info.IsDir() {
+			err = o.upper.Mkdir(name, info.Mode().Perm())
+		} else {
+			err = o.copyUpFile(name, info)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	if !recursive || !info.IsDir() {
+		return nil
+	}
+
+	names, err := o.names(name)
+	if err != nil {
+		return err
+	}
+	for _, n := range names {
+		if err := o.copyUp(filepath.Join(name, n), true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *overlayfs) copyUpFile(name string, info os.FileInfo) error {
+	src, err := o.lower.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := o.upper.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(dst, src)
+	if cerr := dst.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		o.upper.Remove(name)
+		return err
+	}
+	return o.upper.Chtimes(name, info.ModTime(), info.ModTime())
+}
+
+// prepareParent - ensures the parent directory of `name` exists in the upper
+// layer.
+func (o *overlayfs) prepareParent(op, name string) error {
+	dir := filepath.Dir(name)
+	info, _, err := o.stat(dir)
+	if err != nil {
+		return &os.PathError{Op: op, Path: name, Err: os.ErrNotExist}
+	}
+	if !info.IsDir() {
+		return &os.PathError{Op: op, Path: name, Err: syscall.ENOTDIR}
+	}
+	return o.copyUp(dir, false)
+}
+
+func (o *overlayfs) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	name = o.abs(name)
+	if flag&(O_ACCESS|O_APPEND|O_CREATE|O_TRUNC) == O_RDONLY {
+		return o.open(name)
+	}
+
+	_, inUpper, err := o.stat(name)
+	switch {
+	case err == nil && !inUpper:
+		if flag&O_TRUNC != 0 {
+			if err := o.prepareParent("open", name); err != nil {
+				return &InvalidFile{Path: name}, err
+			}
+		} else if err := o.copyUp(name, false); err != nil {
+			return &InvalidFile{Path: name}, err
+		}
+		flag |= O_CREATE
+	case errors.Is(err, os.ErrNotExist) && flag&O_CREATE != 0:
+		if err := o.prepareParent("open", name); err != nil {
+			return &InvalidFile{Path: name}, err
+		}
+	case err != nil && !errors.Is(err, os.ErrNotExist):
+		return &InvalidFile{Path: name}, err
+	}
+	return o.upper.OpenFile(name, flag, perm)
+}
+
+// open - opens `name` read-only, merging directory listings.
+func (o *overlayfs) open(name string) (File, error) {
+	info, inUpper, err := o.stat(name)
+	if err != nil {
+		return &InvalidFile{Path: name}, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	layer := o.lower
+	if inUpper {
+		layer = o.upper
+	}
+	f, err := layer.Open(name)
+	if err != nil || !info.IsDir() {
+		return f, err
+	}
+	return &overlayDir{File: f, o: o, path: name}, nil
+}
+
+func (o *overlayfs) Mkdir(name string, perm os.FileMode) error {
+	name = o.abs(name)
+	if _, _, err := o.stat(name); err == nil {
+		return &os.PathError{Op: "mkdir", Path: name, Err: os.ErrExist}
+	}
+	if err := o.prepareParent("mkdir", name); err != nil {
+		return err
+	}
+	return o.upper.Mkdir(name, perm)
+}
+
+func (o *overlayfs) Remove(name string) error {
+	name = o.abs(name)
+	info, inUpper, err := o.stat(name)
+	if err != nil {
+		return &os.PathError{Op: "remove", Path: name, Err: os.ErrNotExist}
+	}
+	if info.IsDir() {
+		names, err := o.names(name)
+		if err != nil {
+			return err
+		}
+		if len(names) > 0 {
+			return &os.PathError{Op: "remove", Path: name, Err: syscall.ENOTEMPTY}
+		}
+	}
+	if inUpper {
+		if err := o.upper.RemoveAll(name); err != nil {
+			return err
+		}
+	}
+	o.whiteouts[name] = true
+	return nil
+}
+
+func (o *overlayfs) Rename(oldpath, newpath string) error {
+	oldpath, newpath = o.abs(oldpath), o.abs(newpath)
+	if err := o.copyUp(oldpath, true); err != nil {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: unwrapPathError(err)}
+	}
+	if err := o.prepareParent("rename", newpath); err != nil {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: unwrapPathError(err)}
+	}
+	if err := o.upper.Rename(oldpath, newpath); err != nil {
+		return err
+	}
+	o.whiteouts[oldpath] = true
+	o.whiteouts[newpath] = true
+	return nil
+}
+
+func (o *overlayfs) Stat(name string) (os.FileInfo, error) {
+	info, _, err := o.stat(o.abs(name))
+	return info, err
+}
+
+func (o *overlayfs) Chmod(name string, mode os.FileMode) error {
+	name = o.abs(name)
+	if err := o.copyUp(name, false); err != nil {
+		return err
+	}
+	return o.upper.Chmod(name, mode)
+}
+
+func (o *overlayfs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	name = o.abs(name)
+	if err := o.copyUp(name, false); err != nil {
+		return err
+	}
+	return o.upper.Chtimes(name, atime, mtime)
+}
+
+func (o *overlayfs) Chown(name string, uid, gid int) error {
+	name = o.abs(name)
+	if err := o.copyUp(name, false); err != nil {
+		return err
+	}
+	return o.upper.Chown(name, uid, gid)
+}
+
+func (o *overlayfs) Separator() uint8 {
+	return o.upper.Separator()
+}
+
+func (o *overlayfs) ListSeparator() uint8 {
+	return o.upper.ListSeparator()
+}
+
+func (o *overlayfs) Chdir(dir string) error {
+	dir = o.abs(dir)
+	info, _, err := o.stat(dir)
+	if err != nil {
+		return &os.PathError{Op: "chdir", Path: dir, Err: os.ErrNotExist}
+	}
+	if !info.IsDir() {
+		return &os.PathError{Op: "chdir", Path: dir, Err: errors.New("not a directory")}
+	}
+	o.cwd = dir
+	return nil
+}
+
+func (o *overlayfs) Getwd() (dir string, err error) {
+	return o.cwd, nil
+}
+
+func (o *overlayfs) TempDir() string {
+	return o.upper.TempDir()
+}
+
+func (o *overlayfs) Open(name string) (File, error) {
+	return o.open(o.abs(name))
+}
+
+func (o *overlayfs) Create(name string) (File, error) {
+	return o.OpenFile(name, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0666)
+}
+
+func (o *overlayfs) MkdirAll(name string, perm os.FileMode) error {
+	name = o.abs(name)
+	info, _, err := o.stat(name)
+	if err == nil {
+		if !info.IsDir() {
+			return &os.PathError{Op: "mkdir", Path: name, Err: syscall.ENOTDIR}
+		}
+		return nil
+	}
+	if dir := filepath.Dir(name); dir != name {
+		if err := o.MkdirAll(dir, perm); err != nil {
+			return err
+		}
+	}
+	return o.Mkdir(name, perm)
+}
+
+func (o *overlayfs) RemoveAll(path string) (err error) {
+	path = o.abs(path)
+	if _, inUpper, err := o.stat(path); err != nil {
+		return nil
+	} else if inUpper {
+		if err := o.upper.RemoveAll(path); err != nil {
+			return err
+		}
+	}
+	o.whiteouts[path] = true
+	return nil
+}
+
+func (o *overlayfs) Truncate(name string, size int64) error {
+	name = o.abs(name)
+	if err := o.copyUp(name, false); err != nil {
+		return err
+	}
+	return o.upper.Truncate(name, size)
+}
+
+// overlayDir - is a directory handle from one layer whose listing is replaced
+// by the merged listing of both layers.
+type overlayDir struct {
+	File
+	o     *overlayfs
+	path  string
+	names []string
+	read  bool
+}
+
+func (d *overlayDir) Readdirnames(n int) ([]string, error) {
+	if !d.read {
+		names, err := d.o.names(d.path)
+		if err != nil {
+			return nil, err
+		}
+		d.names, d.read = names, true
+	}
+	if n <= 0 {
+		names := d.names
+		d.names = nil
+		return names, nil
+	}
+	if len(d.names) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(d.names) {
+		n = len(d.names)
+	}
+	names := d.names[:n]
+	d.names = d.names[n:]
+	return names, nil
+}
+
+func (d *overlayDir) Readdir(n int) ([]os.FileInfo, error) {
+	names, err := d.Readdirnames(n)
+	infos := make([]os.FileInfo, 0, len(names))
+	for _, name := range names {
+		info, _, serr := d.o.stat(filepath.Join(d.path, name))
+		if serr != nil {
+			return infos, serr
+		}
+		infos = append(infos, info)
+	}
+	return infos, err
+}
+
+// unwrapPathError - returns the underlying error of an `*os.PathError`.
+func unwrapPathError(err error) error {
+	if perr, ok := err.(*os.PathError); ok {
+		return perr.Err
+	}
+	return err
+}
@@ -0,0 +1,190 @@
+package absfs
+
+import (
+	"errors"
+	"io"
+	"os"
+	"reflect"
+	"testing"
+)
+
+func writeTestFile(t *testing.T, fsys FileSystem, name, content string) {
+	t.Helper()
+	f, err := fsys.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(content); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func readTestFile(t *testing.T, fsys FileSystem, name string) string {
+	t.Helper()
+	f, err := fsys.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func listTestDir(t *testing.T, fsys FileSystem, name string) []string {
+	t.Helper()
+	f, err := fsys.Open(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	names, err := f.Readdirnames(-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return names
+}
+
+func newOverlayTest(t *testing.T) (upper, lower, o FileSystem) {
+	upper, lower = newTestFS(), newTestFS()
+	if err := lower.MkdirAll("/dir/sub", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, lower, "/dir/a", "lower a")
+	writeTestFile(t, lower, "/dir/b", "lower b")
+	writeTestFile(t, lower, "/dir/sub/c", "lower c")
+	return upper, lower, Overlay(upper, lower)
+}
+
+func TestOverlayRead(t *testing.T) {
+	upper, _, o := newOverlayTest(t)
+	if err := upper.Mkdir("/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, upper, "/dir/b", "upper b")
+	writeTestFile(t, upper, "/dir/d", "upper d")
+
+	if got := readTestFile(t, o, "/dir/a"); got != "lower a" {
+		t.Errorf("got %q, expected %q", got, "lower a")
+	}
+	if got := readTestFile(t, o, "/dir/b"); got != "upper b" {
+		t.Errorf("got %q, expected %q", got, "upper b")
+	}
+
+	expected := []string{"a", "b", "d", "sub"}
+	if names := listTestDir(t, o, "/dir"); !reflect.DeepEqual(names, expected) {
+		t.Errorf("got %v, expected %v", names, expected)
+	}
+}
+
+func TestOverlayWrite(t *testing.T) {
+	upper, lower, o := newOverlayTest(t)
+
+	f, err := o.OpenFile("/dir/a", os.O_WRONLY|os.O_APPEND, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Seek(0, io.SeekEnd)
+	f.WriteString(" modified")
+	f.Close()
+
+	if got := readTestFile(t, o, "/dir/a"); got != "lower a modified" {
+		t.Errorf("got %q, expected %q", got, "lower a modified")
+	}
+	if got := readTestFile(t, lower, "/dir/a"); got != "lower a" {
+		t.Errorf("lower layer modified: %q", got)
+	}
+	if got := readTestFile(t, upper, "/dir/a"); got != "lower a modified" {
+		t.Errorf("got %q in upper, expected %q", got, "lower a modified")
+	}
+
+	writeTestFile(t, o, "/dir/sub/new", "new")
+	if _, err := lower.Stat("/dir/sub/new"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("file created in lower layer")
+	}
+}
+
+func TestOverlayRemove(t *testing.T) {
+	_, lower, o := newOverlayTest(t)
+
+	if err := o.Remove("/dir/a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := o.Stat("/dir/a"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("removed file still visible: %v", err)
+	}
+	if _, err := lower.Stat("/dir/a"); err != nil {
+		t.Errorf("lower layer modified: %v", err)
+	}
+	expected := []string{"b", "sub"}
+	if names := listTestDir(t, o, "/dir"); !reflect.DeepEqual(names, expected) {
+		t.Errorf("got %v, expected %v", names, expected)
+	}
+
+	if err := o.Remove("/dir/sub"); err == nil {
+		t.Error("removed non-empty directory")
+	}
+	if err := o.RemoveAll("/dir/sub"); err != nil {
+		t.Fatal(err)
+	}
+	if err := o.Mkdir("/dir/sub", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if names := listTestDir(t, o, "/dir/sub"); len(names) != 0 {
+		t.Errorf("recreated directory shows lower entries %v", names)
+	}
+}
+
+func TestOverlayShadowing(t *testing.T) {
+	upper, _, o := newOverlayTest(t)
+	if err := upper.Mkdir("/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	// an upper file shadows a lower directory and its contents
+	writeTestFile(t, upper, "/dir/sub", "upper file")
+	info, err := o.Stat("/dir/sub")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.IsDir() {
+		t.Error("upper file did not shadow lower directory")
+	}
+	if _, err := o.Stat("/dir/sub/c"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("lower entry visible below upper file: %v", err)
+	}
+
+	// an upper directory shadows a lower file
+	if err := upper.Mkdir("/dir/a", 0755); err != nil {
+		t.Fatal(err)
+	}
+	info, err = o.Stat("/dir/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.IsDir() {
+		t.Error("upper directory did not shadow lower file")
+	}
+	if names := listTestDir(t, o, "/dir/a"); len(names) != 0 {
+		t.Errorf("got %v, expected an empty directory", names)
+	}
+}
+
+func TestOverlayRename(t *testing.T) {
+	_, lower, o := newOverlayTest(t)
+	if err := o.Rename("/dir/sub", "/moved"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, o, "/moved/c"); got != "lower c" {
+		t.Errorf("got %q, expected %q", got, "lower c")
+	}
+	if _, err := o.Stat("/dir/sub"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("renamed directory still visible: %v", err)
+	}
+	if _, err := lower.Stat("/dir/sub/c"); err != nil {
+		t.Errorf("lower layer modified: %v", err)
+	}
+}
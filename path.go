@@ -0,0 +1,196 @@
+package absfs
+
+import (
+	"errors"
+	"path"
+	"strings"
+)
+
+// IsDescendant - reports whether `target` is `base` or lies strictly within
+// it, using `sep` as the path separator. Both paths are cleaned first and the
+// comparison is aligned on path components, so "/ab" is not a descendant of
+// "/a", and a target that escapes `base` with ".." is not a descendant.
+func IsDescendant(sep uint8, base, target string) bool {
+	base = cleanSep(sep, base)
+	target = cleanSep(sep, target)
+	if base == target {
+		return true
+	}
+	if base == "/" {
+		return strings.HasPrefix(target, "/")
+	}
+	if base == "." {
+		return target != ".." && !strings.HasPrefix(target, "../") && !strings.HasPrefix(target, "/")
+	}
+	return strings.HasPrefix(target, base+"/")
+}
+
+// cleanSep - converts `p` to a slash separated path and cleans it.
+func cleanSep(sep uint8, p string) string {
+	return path.Clean(toSlash(sep, p))
+}
+
+// toSlash - replaces each `sep` in `p` with a slash.
+func toSlash(sep uint8, p string) string {
+	if sep == '/' {
+		return p
+	}
+	return strings.ReplaceAll(p, string(sep), "/")
+}
+
+// fromSlash - replaces each slash in `p` with `sep`.
+func fromSlash(sep uint8, p string) string {
+	if sep == '/' {
+		return p
+	}
+	return strings.ReplaceAll(p, "/", string(sep))
+}
+
+// IsAbs - reports whether `p` is virtual-absolute, that is whether it begins
+// with a slash or a backslash. Unlike `filepath.IsAbs` the result does not
+// depend on the host operating system, so filesystem implementations can
+// share a single definition.
+func IsAbs(p string) bool {
+	return len(p) > 0 && (p[0] == '/' || p[0] == '\\')
+}
+
+// ValidName - reports whether `name` is acceptable as a path name in `fsys`:
+// it is not empty, it passes `ValidPath`, so it contains no NUL or other
+// control characters, and it is either virtual-absolute or clearly relative.
+// On filesystems whose separator is a backslash, names beginning with a
+// drive letter, such as "C:" or "C:foo", are neither, and are rejected.
+func ValidName(fsys FileSystem, name string) bool {
+	if name == "" || ValidPath(name) != nil {
+		return false
+	}
+	if fsys.Separator() == '\\' && len(name) >= 2 && name[1] == ':' {
+		c := name[0] | 0x20
+		return c < 'a' || c > 'z'
+	}
+	return true
+}
+
+// Clean - is like `filepath.Clean` but uses the separator of `fsys` rather
+// than the host separator.
+func Clean(fsys FileSystem, p string) string {
+	sep := fsys.Separator()
+	return fromSlash(sep, path.Clean(toSlash(sep, p)))
+}
+
+// Join - is like `filepath.Join` but uses the separator of `fsys` rather than
+// the host separator.
+func Join(fsys FileSystem, elem ...string) string {
+	sep := fsys.Separator()
+	elems := make([]string, len(elem))
+	for i, e := range elem {
+		elems[i] = toSlash(sep, e)
+	}
+	return fromSlash(sep, path.Join(elems...))
+}
+
+// Split - is like `filepath.Split` but uses the separator of `fsys` rather
+// than the host separator.
+func Split(fsys FileSystem, p string) (dir, file string) {
+	sep := fsys.Separator()
+	dir, file = path.Split(toSlash(sep, p))
+	return fromSlash(sep, dir), file
+}
+
+// Base - is like `filepath.Base` but uses the separator of `fsys` rather than
+// the host separator.
+func Base(fsys FileSystem, p string) string {
+	sep := fsys.Separator()
+	return fromSlash(sep, path.Base(toSlash(sep, p)))
+}
+
+// Dir - is like `filepath.Dir` but uses the separator of `fsys` rather than
+// the host separator.
+func Dir(fsys FileSystem, p string) string {
+	sep := fsys.Separator()
+	return fromSlash(sep, path.Dir(toSlash(sep, p)))
+}
+
+// Abs - is like `filepath.Abs` but resolves relative paths against the working
+// directory of `fsys` rather than the process, and returns the cleaned,
+// virtual-absolute form of `p` using the separator of `fsys`.
+func Abs(fsys FileSystem, p string) (string, error) {
+	if IsAbs(p) {
+		return Clean(fsys, p), nil
+	}
+	wd, err := fsys.Getwd()
+	if err != nil {
+		return "", err
+	}
+	return Join(fsys, wd, p), nil
+}
+
+// Rel - is like `filepath.Rel` but uses the separator of `fsys` and the
+// virtual-absolute rules of `IsAbs`. It returns a relative path that is
+// lexically equivalent to `targpath` when joined to `basepath`, or an error
+// if `targpath` can't be made relative to `basepath`, for example when only
+// one of them is absolute.
+func Rel(fsys FileSystem, basepath, targpath string) (string, error) {
+	sep := fsys.Separator()
+	base := cleanSep(sep, basepath)
+	targ := cleanSep(sep, targpath)
+	if targ == base {
+		return ".", nil
+	}
+	if base == "." {
+		base = ""
+	}
+	if IsAbs(base) != IsAbs(targ) {
+		return "", errors.New("Rel: can't make " + targpath + " relative to " + basepath)
+	}
+
+	// position past the last common path element
+	bl, tl := len(base), len(targ)
+	var b0, bi, t0, ti int
+	for {
+		for bi < bl && base[bi] != '/' {
+			bi++
+		}
+		for ti < tl && targ[ti] != '/' {
+			ti++
+		}
+		if targ[t0:ti] != base[b0:bi] {
+			break
+		}
+		if bi < bl {
+			bi++
+		}
+		if ti < tl {
+			ti++
+		}
+		b0, t0 = bi, ti
+	}
+	if base[b0:bi] == ".." {
+		return "", errors.New("Rel: can't make " + targpath + " relative to " + basepath)
+	}
+	if b0 == bl {
+		return fromSlash(sep, targ[t0:]), nil
+	}
+
+	rel := ".." + strings.Repeat("/..", strings.Count(base[b0:], "/"))
+	if t0 != tl {
+		rel += "/" + targ[t0:]
+	}
+	return fromSlash(sep, rel), nil
+}
+
+// RealPath - returns the canonical absolute form of `path`. Relative paths are
+// joined onto the working directory of `fs` and the result is cleaned. When
+// `fs` is a `SymlinkFileSystem` any symbolic links are then evaluated with
+// `EvalSymlinks`; for other filesystems the cleaned absolute path is
+// returned as is.
+func RealPath(fs FileSystem, path string) (string, error) {
+	path, err := Abs(fs, path)
+	if err != nil {
+		return "", err
+	}
+
+	if sfs, ok := fs.(SymlinkFileSystem); ok {
+		return EvalSymlinks(sfs, path)
+	}
+	return path, nil
+}
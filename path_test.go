@@ -0,0 +1,280 @@
+package absfs
+
+import (
+	"errors"
+	"strings"
+	"syscall"
+	"testing"
+)
+
+func TestIsDescendant(t *testing.T) {
+	tests := []struct {
+		Sep    uint8
+		Base   string
+		Target string
+		Exp    bool
+	}{
+		{'/', "/a", "/a", true},
+		{'/', "/a/", "/a", true},
+		{'/', "/a", "/a/b/c", true},
+		{'/', "/a", "/ab", false},
+		{'/', "/a", "/a/../b", false},
+		{'/', "/a", "/a/b/../../a/c", true},
+		{'/', "/", "/a", true},
+		{'/', "a", "a/b", true},
+		{'/', ".", "../a", false},
+		{'/', ".", "a", true},
+		{'\\', `\a`, `\a\b`, true},
+		{'\\', `\a`, `\ab`, false},
+		{'\\', `\a`, `\a\..\b`, false},
+	}
+
+	for _, test := range tests {
+		if got := IsDescendant(test.Sep, test.Base, test.Target); got != test.Exp {
+			t.Errorf("IsDescendant(%q, %q, %q) = %t, expected %t", test.Sep, test.Base, test.Target, got, test.Exp)
+		}
+	}
+}
+
+func TestRealPath(t *testing.T) {
+	fsys := newTestFS()
+	if err := fsys.MkdirAll("/a/b", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Chdir("/a"); err != nil {
+		t.Fatal(err)
+	}
+	p, err := RealPath(fsys, "b/../b/./c")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p != "/a/b/c" {
+		t.Errorf("got %q, expected %q", p, "/a/b/c")
+	}
+}
+
+func TestRealPathSymlink(t *testing.T) {
+	fsys := newTestSymlinkFS()
+	if err := fsys.MkdirAll("/data/real", 0755); err != nil {
+		t.Fatal(err)
+	}
+	createFiles(t, fsys, "/data/real/file")
+	if err := fsys.Symlink("real", "/data/link"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Symlink("/data/link/file", "/abs"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Chdir("/data"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]string{
+		"link/file": "/data/real/file",
+		"/abs":      "/data/real/file",
+	}
+	for in, exp := range tests {
+		p, err := RealPath(fsys, in)
+		if err != nil {
+			t.Errorf("%q: %v", in, err)
+			continue
+		}
+		if p != exp {
+			t.Errorf("%q: got %q, expected %q", in, p, exp)
+		}
+	}
+
+	if err := fsys.Symlink("/loop2", "/loop1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Symlink("/loop1", "/loop2"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := RealPath(fsys, "/loop1"); !errors.Is(err, syscall.ELOOP) {
+		t.Errorf("got %v, expected ELOOP", err)
+	}
+}
+
+// sepFS - overrides the separator reported by a `FileSystem`.
+type sepFS struct {
+	FileSystem
+	sep uint8
+}
+
+func (fs *sepFS) Separator() uint8 {
+	return fs.sep
+}
+
+func TestPathHelpers(t *testing.T) {
+	for _, sep := range []uint8{'/', '\\'} {
+		fsys := &sepFS{newTestFS(), sep}
+		s := func(p string) string {
+			return fromSlash(sep, p)
+		}
+
+		if got := Clean(fsys, s("/a/b/../c/./d/")); got != s("/a/c/d") {
+			t.Errorf("%q Clean: got %q", sep, got)
+		}
+		if got := Join(fsys, s("/a"), "b", s("c/d")); got != s("/a/b/c/d") {
+			t.Errorf("%q Join: got %q", sep, got)
+		}
+		if dir, file := Split(fsys, s("/a/b/c.txt")); dir != s("/a/b/") || file != "c.txt" {
+			t.Errorf("%q Split: got %q, %q", sep, dir, file)
+		}
+		if got := Base(fsys, s("/a/b/c.txt")); got != "c.txt" {
+			t.Errorf("%q Base: got %q", sep, got)
+		}
+		if got := Dir(fsys, s("/a/b/c.txt")); got != s("/a/b") {
+			t.Errorf("%q Dir: got %q", sep, got)
+		}
+		if !IsAbs(s("/a")) || IsAbs(s("a/b")) || IsAbs("") {
+			t.Errorf("%q IsAbs: unexpected result", sep)
+		}
+	}
+}
+
+// navFiler - is a `mockFiler` that tracks it's own working directory.
+type navFiler struct {
+	*mockFiler
+	cwd string
+}
+
+func (f *navFiler) Chdir(dir string) error {
+	f.cwd = dir
+	return nil
+}
+
+func (f *navFiler) Getwd() (string, error) {
+	return f.cwd, nil
+}
+
+func TestAbs(t *testing.T) {
+	fsys := newTestFS()
+	if err := fsys.MkdirAll("/a/b", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := fsys.Chdir("/a/b"); err != nil {
+		t.Fatal(err)
+	}
+	nav := ExtendFiler(&navFiler{newMockFiler(), "/x/y"})
+
+	tests := []struct {
+		FS  FileSystem
+		In  string
+		Exp string
+	}{
+		{fsys, ".", "/a/b"},
+		{fsys, "..", "/a"},
+		{fsys, "c/./d/../e", "/a/b/c/e"},
+		{fsys, "/c/../d", "/d"},
+		{fsys, "../../..", "/"},
+		{nav, ".", "/x/y"},
+		{nav, "../z", "/x/z"},
+	}
+	for _, test := range tests {
+		p, err := Abs(test.FS, test.In)
+		if err != nil {
+			t.Errorf("%q: %v", test.In, err)
+			continue
+		}
+		if p != test.Exp {
+			t.Errorf("%q: got %q, expected %q", test.In, p, test.Exp)
+		}
+	}
+}
+
+func TestRel(t *testing.T) {
+	tests := []struct {
+		Base string
+		Targ string
+		Exp  string
+		Err  bool
+	}{
+		{"/a", "/a", ".", false},
+		{"/a/b", "/a/b/c/d", "c/d", false},
+		{"/a/b/c", "/a/d", "../../d", false},
+		{"/a/b", "/a", "..", false},
+		{"/", "/a/b", "a/b", false},
+		{"/a/b/", "/a/b/../c", "../c", false},
+		{"a/b", "a/c", "../c", false},
+		{".", "a", "a", false},
+		{"/a", "b", "", true},
+		{"a", "/b", "", true},
+		{"../a", "b", "", true},
+	}
+
+	for _, sep := range []uint8{'/', '\\'} {
+		fsys := &sepFS{newTestFS(), sep}
+		for _, test := range tests {
+			base, targ := fromSlash(sep, test.Base), fromSlash(sep, test.Targ)
+			rel, err := Rel(fsys, base, targ)
+			if test.Err {
+				if err == nil {
+					t.Errorf("%q Rel(%q, %q): expected an error, got %q", sep, base, targ, rel)
+				}
+				continue
+			}
+			if err != nil {
+				t.Errorf("%q Rel(%q, %q): %v", sep, base, targ, err)
+				continue
+			}
+			if exp := fromSlash(sep, test.Exp); rel != exp {
+				t.Errorf("%q Rel(%q, %q): got %q, expected %q", sep, base, targ, rel, exp)
+			}
+		}
+	}
+}
+
+func TestValidName(t *testing.T) {
+	tests := []struct {
+		name      string
+		slash     bool
+		backslash bool
+	}{
+		{"", false, false},
+		{"/", true, true},
+		{"/a/b", true, true},
+		{`\a\b`, true, true},
+		{"a/b", true, true},
+		{"..", true, true},
+		{"a\x00b", false, false},
+		{"new\nline", false, false},
+		{"C:", true, false},
+		{`c:\windows`, true, false},
+		{"C:foo", true, false},
+		{"1:foo", true, true},
+		{"ab:c", true, true},
+	}
+	for _, tt := range tests {
+		if got := ValidName(&sepFS{newTestFS(), '/'}, tt.name); got != tt.slash {
+			t.Errorf("ValidName(%q) with '/' = %t, expected %t", tt.name, got, tt.slash)
+		}
+		if got := ValidName(&sepFS{newTestFS(), '\\'}, tt.name); got != tt.backslash {
+			t.Errorf("ValidName(%q) with '\\' = %t, expected %t", tt.name, got, tt.backslash)
+		}
+	}
+}
+
+func FuzzValidName(f *testing.F) {
+	for _, seed := range []string{"", "/", "a/b", "../x", "C:foo", "a\x00b", `\a\b`} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, name string) {
+		for _, sep := range []uint8{'/', '\\'} {
+			fsys := &sepFS{newTestFS(), sep}
+			if !ValidName(fsys, name) {
+				if name != "" && ValidPath(name) == nil && (sep != '\\' || len(name) < 2 || name[1] != ':') {
+					t.Fatalf("ValidName(%q) with %q rejected a valid name", name, sep)
+				}
+				continue
+			}
+			// resolving a valid name against the root keeps it valid
+			if abs := Join(fsys, string(sep), name); !ValidName(fsys, abs) || !IsAbs(abs) {
+				t.Fatalf("ValidName(%q) with %q, but not %q", name, sep, abs)
+			}
+			if clean := Clean(fsys, name); !ValidName(fsys, clean) && !strings.Contains(name, ":") {
+				t.Fatalf("ValidName(%q) with %q, but not %q", name, sep, clean)
+			}
+		}
+	})
+}
@@ -0,0 +1,154 @@
+package absfs
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// WithRateLimit - returns a FileSystem whose file handles throttle `Read`,
+// `Write`, `ReadAt`, and `WriteAt` so that the aggregate throughput of all
+// handles opened through it stays under `bytesPerSec`. A single token bucket,
+// holding at most one second's worth of bytes, is shared by every handle.
+// Writes wait before the data is written; reads are charged for the bytes
+// actually read. A non-positive `bytesPerSec` disables the limit and `fsys` is
+// returned unchanged.
+//
+// Closing a handle wakes any of its calls that are waiting for tokens; those
+// calls return an error wrapping `os.ErrClosed` and their reservation is
+// returned to the bucket.
+func WithRateLimit(fsys FileSystem, bytesPerSec int64) FileSystem {
+	if bytesPerSec <= 0 {
+		return fsys
+	}
+	rate := float64(bytesPerSec)
+	return &ratefs{fsys, &rateLimiter{rate: rate, tokens: rate, last: time.Now()}}
+}
+
+type ratefs struct {
+	FileSystem
+	l *rateLimiter
+}
+
+func (fs *ratefs) wrap(f File, err error) (File, error) {
+	if err != nil {
+		return f, err
+	}
+	return &ratefile{File: f, l: fs.l, closed: make(chan struct{})}, nil
+}
+
+func (fs *ratefs) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	return fs.wrap(fs.FileSystem.OpenFile(name, flag, perm))
+}
+
+func (fs *ratefs) Open(name string) (File, error) {
+	return fs.wrap(fs.FileSystem.Open(name))
+}
+
+func (fs *ratefs) Create(name string) (File, error) {
+	return fs.wrap(fs.FileSystem.Create(name))
+}
+
+// rateLimiter - a token bucket refilled at `rate` bytes per second with a
+// capacity of one second's worth of bytes. Reservations may drive the bucket
+// negative; the reserver then waits until the debt has been repaid.
+type rateLimiter struct {
+	mu     sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+// reserve - takes `n` tokens and returns how long the caller must wait
+// before using them.
+func (l *rateLimiter) reserve(n int) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.tokens += now.Sub(l.last).Seconds() * l.rate
+	if l.tokens > l.rate {
+		l.tokens = l.rate
+	}
+	l.last = now
+	l.tokens -= float64(n)
+	if l.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-l.tokens / l.rate * float64(time.Second))
+}
+
+// cancel - returns `n` reserved tokens to the bucket.
+func (l *rateLimiter) cancel(n int) {
+	l.mu.Lock()
+	l.tokens += float64(n)
+	l.mu.Unlock()
+}
+
+// wait - reserves `n` tokens and blocks until they are available or `closed`
+// is closed, in which case the reservation is cancelled and false is
+// returned.
+func (l *rateLimiter) wait(n int, closed <-chan struct{}) bool {
+	d := l.reserve(n)
+	if d == 0 {
+		return true
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return true
+	case <-closed:
+		l.cancel(n)
+		return false
+	}
+}
+
+type ratefile struct {
+	File
+	l      *rateLimiter
+	once   sync.Once
+	closed chan struct{}
+}
+
+func (f *ratefile) closedError(op string) error {
+	return &os.PathError{Op: op, Path: f.File.Name(), Err: os.ErrClosed}
+}
+
+func (f *ratefile) Read(b []byte) (int, error) {
+	n, err := f.File.Read(b)
+	if n > 0 && !f.l.wait(n, f.closed) {
+		return n, f.closedError("read")
+	}
+	return n, err
+}
+
+func (f *ratefile) ReadAt(b []byte, off int64) (int, error) {
+	n, err := f.File.ReadAt(b, off)
+	if n > 0 && !f.l.wait(n, f.closed) {
+		return n, f.closedError("read")
+	}
+	return n, err
+}
+
+func (f *ratefile) Write(b []byte) (int, error) {
+	if !f.l.wait(len(b), f.closed) {
+		return 0, f.closedError("write")
+	}
+	return f.File.Write(b)
+}
+
+func (f *ratefile) WriteAt(b []byte, off int64) (int, error) {
+	if !f.l.wait(len(b), f.closed) {
+		return 0, f.closedError("write")
+	}
+	return f.File.WriteAt(b, off)
+}
+
+func (f *ratefile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+func (f *ratefile) Close() error {
+	f.once.Do(func() { close(f.closed) })
+	return f.File.Close()
+}
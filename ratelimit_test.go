@@ -0,0 +1,82 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWithRateLimit(t *testing.T) {
+	fsys := WithRateLimit(newTestFS(), 1000)
+
+	a, err := fsys.Create("/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer a.Close()
+	b, err := fsys.Create("/b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer b.Close()
+
+	// The first second's worth of bytes is available immediately.
+	start := time.Now()
+	if _, err := a.Write(make([]byte, 1000)); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d > 100*time.Millisecond {
+		t.Errorf("initial write took %s, expected no delay", d)
+	}
+
+	// The bucket is shared, so a second handle must wait.
+	start = time.Now()
+	if _, err := b.Write(make([]byte, 200)); err != nil {
+		t.Fatal(err)
+	}
+	if d := time.Since(start); d < 150*time.Millisecond {
+		t.Errorf("throttled write took %s, expected about 200ms", d)
+	}
+
+	buf := make([]byte, 100)
+	start = time.Now()
+	if n, err := a.ReadAt(buf, 0); err != nil || n != len(buf) {
+		t.Fatalf("ReadAt: %d, %v", n, err)
+	}
+	if d := time.Since(start); d < 50*time.Millisecond {
+		t.Errorf("throttled read took %s, expected about 100ms", d)
+	}
+}
+
+func TestWithRateLimitClose(t *testing.T) {
+	fsys := WithRateLimit(newTestFS(), 100)
+	f, err := fsys.Create("/a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error)
+	go func() {
+		_, err := f.Write(make([]byte, 10000))
+		done <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	f.Close()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, os.ErrClosed) {
+			t.Errorf("got %v, expected %v", err, os.ErrClosed)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Close did not release the pending write")
+	}
+}
+
+func TestWithRateLimitDisabled(t *testing.T) {
+	fsys := newTestFS()
+	if WithRateLimit(fsys, 0) != fsys {
+		t.Error("a non-positive limit should return the FileSystem unchanged")
+	}
+}
@@ -0,0 +1,47 @@
+package absfs
+
+import "io"
+
+// ReadFull - reads exactly `len(buf)` bytes from `f` into `buf` and returns the
+// number of bytes copied. Like `io.ReadFull` it keeps reading through short
+// reads that return no error, returns `io.EOF` only if no bytes were read, and
+// returns `io.ErrUnexpectedEOF` if EOF is reached after a partial read.
+//
+// When the current offset of `f` can be found with `Seek`, the data is read
+// with `ReadAt`, which many backends can satisfy in a single call, and the
+// offset is then advanced past the bytes read. If `f` can't seek, or `ReadAt`
+// fails before returning any data, `ReadFull` falls back to looping `Read`.
+func ReadFull(f File, buf []byte) (n int, err error) {
+	pos, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return io.ReadFull(f, buf)
+	}
+	n, err = readFullAt(f, buf, pos)
+	if n == 0 && err != nil && err != io.EOF {
+		return io.ReadFull(f, buf)
+	}
+	if _, serr := f.Seek(pos+int64(n), io.SeekStart); serr != nil && err == nil {
+		err = serr
+	}
+	return n, err
+}
+
+// readFullAt - fills `buf` with `ReadAt` calls starting at `off`, with the
+// error semantics of `io.ReadFull`.
+func readFullAt(f File, buf []byte, off int64) (n int, err error) {
+	for n < len(buf) && err == nil {
+		var m int
+		m, err = f.ReadAt(buf[n:], off+int64(n))
+		if m == 0 && err == nil {
+			err = io.ErrNoProgress
+		}
+		n += m
+	}
+	if n == len(buf) {
+		return n, nil
+	}
+	if err == io.EOF && n > 0 {
+		err = io.ErrUnexpectedEOF
+	}
+	return n, err
+}
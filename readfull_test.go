@@ -0,0 +1,125 @@
+package absfs
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// chunkFile - returns at most `chunk` bytes from each `Read` without
+// signalling EOF early, like a backend that serves data in fixed size blocks.
+type chunkFile struct {
+	File
+	chunk int
+	reads int
+}
+
+func (f *chunkFile) Read(b []byte) (int, error) {
+	f.reads++
+	if len(b) > f.chunk {
+		b = b[:f.chunk]
+	}
+	return f.File.Read(b)
+}
+
+// noSeekFile - fails every `Seek`, forcing `ReadFull` to loop `Read`.
+type noSeekFile struct {
+	File
+}
+
+func (f noSeekFile) Seek(offset int64, whence int) (int64, error) {
+	return 0, errors.New("seek not supported")
+}
+
+func openChunkFile(t testing.TB, content string, chunk int) *chunkFile {
+	t.Helper()
+	fsys := newTestFS()
+	if err := WriteString(fsys, "/file", content, 0644); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fsys.Open("/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &chunkFile{File: f, chunk: chunk}
+}
+
+func TestReadFull(t *testing.T) {
+	content := strings.Repeat("0123456789", 10)
+
+	for _, test := range []struct {
+		name string
+		file func(*chunkFile) File
+	}{
+		{"ReadAt", func(f *chunkFile) File { return f }},
+		{"Read", func(f *chunkFile) File { return noSeekFile{f} }},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			f := openChunkFile(t, content, 7)
+			file := test.file(f)
+
+			buf := make([]byte, 60)
+			n, err := ReadFull(file, buf)
+			if n != 60 || err != nil {
+				t.Fatalf("got %d, %v, expected 60, nil", n, err)
+			}
+			if string(buf) != content[:60] {
+				t.Errorf("got %q, expected %q", buf, content[:60])
+			}
+
+			n, err = ReadFull(file, buf)
+			if n != 40 || err != io.ErrUnexpectedEOF {
+				t.Fatalf("got %d, %v, expected 40, %v", n, err, io.ErrUnexpectedEOF)
+			}
+			if string(buf[:n]) != content[60:] {
+				t.Errorf("got %q, expected %q", buf[:n], content[60:])
+			}
+
+			n, err = ReadFull(file, buf)
+			if n != 0 || err != io.EOF {
+				t.Errorf("got %d, %v, expected 0, %v", n, err, io.EOF)
+			}
+		})
+	}
+
+	f := openChunkFile(t, content, 7)
+	buf := make([]byte, 50)
+	if _, err := ReadFull(f, buf); err != nil {
+		t.Fatal(err)
+	}
+	if f.reads != 0 {
+		t.Errorf("expected ReadAt to be used, got %d reads", f.reads)
+	}
+	pos, err := f.Seek(0, io.SeekCurrent)
+	if err != nil || pos != 50 {
+		t.Errorf("got offset %d, %v, expected 50", pos, err)
+	}
+}
+
+func BenchmarkReadFull(b *testing.B) {
+	content := strings.Repeat("x", 1<<20)
+	buf := make([]byte, len(content))
+
+	b.Run("ReadFull", func(b *testing.B) {
+		f := openChunkFile(b, content, 4096)
+		b.SetBytes(int64(len(buf)))
+		for i := 0; i < b.N; i++ {
+			f.Seek(0, io.SeekStart)
+			if _, err := ReadFull(f, buf); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("Read", func(b *testing.B) {
+		f := openChunkFile(b, content, 4096)
+		b.SetBytes(int64(len(buf)))
+		for i := 0; i < b.N; i++ {
+			f.Seek(0, io.SeekStart)
+			if _, err := io.ReadFull(f, buf); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
@@ -0,0 +1,184 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"sync"
+)
+
+// RemoveAllParallel - removes `path` and any children it contains, like
+// `RemoveAll`, but a pool of `workers` goroutines issues operations on `fsys`
+// concurrently. A directory is still removed only after all of its children
+// have been removed. The first error stops any work that has not yet started
+// and is returned once the operations in flight have finished.
+//
+// Prefer `RemoveAllParallel` for wide trees on high-latency backends, such as
+// network or cloud stores, where the time is dominated by round trips.
+// `fsys` must be safe for concurrent use (see `Synchronized`); for backends
+// that are not, or that serialize mutations anyway, the sequential
+// `RemoveAll` is the better choice.
+func RemoveAllParallel(fsys FileSystem, path string, workers int) error {
+	if workers < 1 {
+		workers = 1
+	}
+	info, err := Lstat(fsys, path)
+	if err != nil {
+		return err
+	}
+	r := &parallelRemover{fsys: fsys}
+	r.cond = sync.NewCond(&r.mu)
+	r.push(removeTask{path: path, info: info})
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			r.work()
+		}()
+	}
+	wg.Wait()
+	return r.err
+}
+
+// removeTask - is an entry waiting to be removed by `RemoveAllParallel`.
+// `info` is nil until the entry has been looked up.
+type removeTask struct {
+	path   string
+	info   os.FileInfo
+	parent *removeDir
+}
+
+// removeDir - is a directory whose children are being removed. It is
+// removed itself once `pending` reaches zero.
+type removeDir struct {
+	path    string
+	parent  *removeDir
+	pending int
+}
+
+// parallelRemover - holds the queue and state shared by the workers of
+// `RemoveAllParallel`.
+type parallelRemover struct {
+	fsys FileSystem
+
+	mu     sync.Mutex
+	cond   *sync.Cond
+	queue  []removeTask
+	active int // tasks queued or running
+	err    error
+}
+
+// push - adds `t` to the queue.
+func (r *parallelRemover) push(t removeTask) {
+	r.mu.Lock()
+	r.queue = append(r.queue, t)
+	r.active++
+	r.mu.Unlock()
+	r.cond.Signal()
+}
+
+// work - runs tasks from the queue until there are none left, queued or
+// running. Once the removal has failed, queued tasks are dropped.
+func (r *parallelRemover) work() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for {
+		for len(r.queue) == 0 && r.active > 0 {
+			r.cond.Wait()
+		}
+		if r.active == 0 {
+			return
+		}
+		// taking the newest task first keeps the walk depth first, so the
+		// queue holds about one directory listing per level.
+		t := r.queue[len(r.queue)-1]
+		r.queue = r.queue[:len(r.queue)-1]
+		failed := r.err != nil
+		r.mu.Unlock()
+		if !failed {
+			r.run(t)
+		}
+		r.mu.Lock()
+		r.active--
+		if r.active == 0 {
+			r.cond.Broadcast()
+		}
+	}
+}
+
+// fail - records `err` if it is the first error. It reports whether the
+// removal should stop; an `os.ErrNotExist` means the entry is already gone.
+func (r *parallelRemover) fail(err error) bool {
+	if err == nil || errors.Is(err, os.ErrNotExist) {
+		return false
+	}
+	r.mu.Lock()
+	if r.err == nil {
+		r.err = err
+	}
+	r.mu.Unlock()
+	return true
+}
+
+// run - removes the entry of `t`, or queues the children of a directory.
+func (r *parallelRemover) run(t removeTask) {
+	info := t.info
+	if info == nil {
+		var err error
+		if info, err = Lstat(r.fsys, t.path); err != nil {
+			if !r.fail(err) {
+				r.done(t.parent)
+			}
+			return
+		}
+	}
+	if !info.IsDir() {
+		if !r.fail(r.fsys.Remove(t.path)) {
+			r.done(t.parent)
+		}
+		return
+	}
+
+	names, err := readDirNames(r.fsys, t.path)
+	if err != nil {
+		if !r.fail(err) {
+			r.done(t.parent)
+		}
+		return
+	}
+	d := &removeDir{path: t.path, parent: t.parent, pending: len(names)}
+	if len(names) == 0 {
+		r.removeDir(d)
+		return
+	}
+	for _, name := range names {
+		r.push(removeTask{path: Join(r.fsys, t.path, name), parent: d})
+	}
+}
+
+// done - records that a child of `d` has been removed, and removes `d` once
+// all of its children have been.
+func (r *parallelRemover) done(d *removeDir) {
+	if d == nil {
+		return
+	}
+	r.mu.Lock()
+	d.pending--
+	empty := d.pending == 0
+	r.mu.Unlock()
+	if empty {
+		r.removeDir(d)
+	}
+}
+
+// removeDir - removes the empty directory `d`, unless the removal has failed.
+func (r *parallelRemover) removeDir(d *removeDir) {
+	r.mu.Lock()
+	failed := r.err != nil
+	r.mu.Unlock()
+	if failed || r.fail(r.fsys.Remove(d.path)) {
+		return
+	}
+	r.done(d.parent)
+}
@@ -0,0 +1,116 @@
+package absfs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"sync"
+	"testing"
+)
+
+// removeFailFS - fails every `Remove` of `name`.
+type removeFailFS struct {
+	FileSystem
+	name string
+}
+
+var errRemove = errors.New("remove failed")
+
+func (fs *removeFailFS) Remove(name string) error {
+	if name == fs.name {
+		return &os.PathError{Op: "remove", Path: name, Err: errRemove}
+	}
+	return fs.FileSystem.Remove(name)
+}
+
+func newRemoveTree(t *testing.T) FileSystem {
+	t.Helper()
+	fsys := Synchronized(newTestFS())
+	for i := 0; i < 10; i++ {
+		dir := fmt.Sprintf("/tree/d%d/sub", i)
+		if err := fsys.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for j := 0; j < 10; j++ {
+			createFiles(t, fsys, fmt.Sprintf("%s/f%d", dir, j))
+		}
+	}
+	createFiles(t, fsys, "/keep")
+	return fsys
+}
+
+func TestRemoveAllParallel(t *testing.T) {
+	fsys := newRemoveTree(t)
+	if err := RemoveAllParallel(fsys, "/tree", 4); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fsys.Stat("/tree"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrNotExist)
+	}
+	if _, err := fsys.Stat("/keep"); err != nil {
+		t.Error(err)
+	}
+
+	if err := RemoveAllParallel(fsys, "/keep", 4); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fsys.Stat("/keep"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrNotExist)
+	}
+}
+
+func TestRemoveAllParallelError(t *testing.T) {
+	fsys := &removeFailFS{FileSystem: newRemoveTree(t), name: "/tree/d3/sub/f3"}
+	err := RemoveAllParallel(fsys, "/tree", 4)
+	if !errors.Is(err, errRemove) {
+		t.Fatalf("got %v, expected %v", err, errRemove)
+	}
+	// the directories holding the failed file must survive.
+	for _, dir := range []string{"/tree", "/tree/d3", "/tree/d3/sub"} {
+		if _, err := fsys.Stat(dir); err != nil {
+			t.Errorf("%s: %v", dir, err)
+		}
+	}
+}
+
+// goroutineCountFS - records the largest number of goroutines running
+// during a `Remove`.
+type goroutineCountFS struct {
+	FileSystem
+	mu  sync.Mutex
+	max int
+}
+
+func (fs *goroutineCountFS) Remove(name string) error {
+	n := runtime.NumGoroutine()
+	fs.mu.Lock()
+	if n > fs.max {
+		fs.max = n
+	}
+	fs.mu.Unlock()
+	return fs.FileSystem.Remove(name)
+}
+
+func TestRemoveAllParallelWorkers(t *testing.T) {
+	base := Synchronized(newTestFS())
+	if err := base.Mkdir("/wide", 0755); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 1000; i++ {
+		createFiles(t, base, fmt.Sprintf("/wide/f%04d", i))
+	}
+	fsys := &goroutineCountFS{FileSystem: base}
+
+	const workers = 4
+	before := runtime.NumGoroutine()
+	if err := RemoveAllParallel(fsys, "/wide", workers); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := base.Stat("/wide"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrNotExist)
+	}
+	if fsys.max > before+workers {
+		t.Errorf("got %d goroutines, expected at most %d", fsys.max, before+workers)
+	}
+}
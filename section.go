@@ -0,0 +1,140 @@
+package absfs
+
+import (
+	"io"
+	"os"
+	"syscall"
+)
+
+// LimitFile - returns a read-only File presenting at most `n` bytes of `f`,
+// starting at its current offset, like `io.LimitReader` but keeping the File
+// interface so the result can be passed to APIs that expect one, for example
+// to serve a byte range or read a fixed size record. Reads return `io.EOF`
+// once the limit is reached. Offsets used by `Seek` and `ReadAt` are relative
+// to the start of the window, and `Stat` reports the size of the window.
+//
+// It is `NewSectionFile` starting at the current offset of `f`.
+func LimitFile(f File, n int64) File {
+	base, err := f.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return &InvalidFile{Path: f.Name(), Err: err}
+	}
+	return NewSectionFile(f, base, n)
+}
+
+// NewSectionFile - returns a read-only File presenting the `n` bytes of `f`
+// starting at offset `off`, like `io.SectionReader` but keeping the File
+// interface, so a bounded part of a larger container, such as a member of an
+// uncompressed archive, can be handed to code that expects a whole file.
+//
+// Reads and seeks are confined to the section: offsets used by `Seek` and
+// `ReadAt` are relative to `off`, `io.SeekEnd` is relative to the end of the
+// section, and reads return `io.EOF` at the end of the section. The section is
+// read-only, so bytes outside it can never be changed through it: writes and
+// `Truncate` fail with `syscall.EBADF`. `Stat` reports the size of the
+// section. The offset of `f` is not moved, and `Close` closes `f`.
+func NewSectionFile(f File, off, n int64) File {
+	if off < 0 {
+		off = 0
+	}
+	if n < 0 {
+		n = 0
+	}
+	return &sectionFile{File: f, base: off, limit: n}
+}
+
+// sectionFile - a read-only view of the bytes of `File` in
+// [base, base+limit).
+type sectionFile struct {
+	File
+	base   int64
+	limit  int64
+	offset int64
+}
+
+func (f *sectionFile) pathError(op string, err error) error {
+	return &os.PathError{Op: op, Path: f.File.Name(), Err: err}
+}
+
+func (f *sectionFile) Read(b []byte) (int, error) {
+	n, err := f.ReadAt(b, f.offset)
+	f.offset += int64(n)
+	return n, err
+}
+
+func (f *sectionFile) ReadAt(b []byte, off int64) (int, error) {
+	if off < 0 {
+		return 0, f.pathError("read", syscall.EINVAL)
+	}
+	if off >= f.limit {
+		return 0, io.EOF
+	}
+	if remaining := f.limit - off; int64(len(b)) > remaining {
+		n, err := f.File.ReadAt(b[:remaining], f.base+off)
+		if err == nil {
+			err = io.EOF
+		}
+		return n, err
+	}
+	return f.File.ReadAt(b, f.base+off)
+}
+
+func (f *sectionFile) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += f.offset
+	case io.SeekEnd:
+		offset += f.limit
+	default:
+		return f.offset, f.pathError("seek", syscall.EINVAL)
+	}
+	if offset < 0 {
+		return f.offset, f.pathError("seek", syscall.EINVAL)
+	}
+	f.offset = offset
+	return offset, nil
+}
+
+func (f *sectionFile) Write(b []byte) (int, error) {
+	return 0, f.pathError("write", syscall.EBADF)
+}
+
+func (f *sectionFile) WriteAt(b []byte, off int64) (int, error) {
+	return 0, f.pathError("write", syscall.EBADF)
+}
+
+func (f *sectionFile) WriteString(s string) (int, error) {
+	return 0, f.pathError("write", syscall.EBADF)
+}
+
+func (f *sectionFile) Truncate(size int64) error {
+	return f.pathError("truncate", syscall.EBADF)
+}
+
+// Stat - describes the underlying file with the size of the window, or less
+// if the file ends within it.
+func (f *sectionFile) Stat() (os.FileInfo, error) {
+	info, err := f.File.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := info.Size() - f.base
+	if size > f.limit {
+		size = f.limit
+	}
+	if size < 0 {
+		size = 0
+	}
+	return sizedInfo{info, size}, nil
+}
+
+// sizedInfo - reports `size` as the size of a file.
+type sizedInfo struct {
+	os.FileInfo
+	size int64
+}
+
+func (i sizedInfo) Size() int64 {
+	return i.size
+}
@@ -0,0 +1,112 @@
+package absfs
+
+import (
+	"errors"
+	"io"
+	"syscall"
+	"testing"
+)
+
+func openTestFile(t *testing.T, content string) File {
+	t.Helper()
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/file", content)
+	f, err := fsys.Open("/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { f.Close() })
+	return f
+}
+
+func TestLimitFile(t *testing.T) {
+	f := openTestFile(t, "0123456789")
+	if _, err := f.Seek(2, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	lf := LimitFile(f, 5)
+
+	data, err := io.ReadAll(lf)
+	if err != nil || string(data) != "23456" {
+		t.Errorf("got %q, %v, expected %q", data, err, "23456")
+	}
+	if pos, err := Tell(f); pos != 2 || err != nil {
+		t.Errorf("underlying offset moved to %d, %v", pos, err)
+	}
+
+	buf := make([]byte, 4)
+	if n, err := lf.ReadAt(buf, 3); n != 2 || err != io.EOF || string(buf[:n]) != "56" {
+		t.Errorf("ReadAt at limit: got %q, %v", buf[:n], err)
+	}
+	if n, err := lf.ReadAt(buf, 5); n != 0 || err != io.EOF {
+		t.Errorf("ReadAt past limit: got %d, %v", n, err)
+	}
+	if n, err := lf.ReadAt(buf, 0); n != 4 || err != nil || string(buf) != "2345" {
+		t.Errorf("ReadAt: got %q, %v", buf[:n], err)
+	}
+
+	info, err := lf.Stat()
+	if err != nil || info.Size() != 5 {
+		t.Errorf("Stat: got %v, %v, expected size 5", info, err)
+	}
+	if _, err := lf.Write([]byte("x")); !errors.Is(err, syscall.EBADF) {
+		t.Errorf("Write: got %v, expected %v", err, syscall.EBADF)
+	}
+
+	// a limit beyond the end of the file stops at the end
+	lf = LimitFile(f, 100)
+	if data, err := io.ReadAll(lf); err != nil || string(data) != "23456789" {
+		t.Errorf("got %q, %v", data, err)
+	}
+	if info, err := lf.Stat(); err != nil || info.Size() != 8 {
+		t.Errorf("Stat: got %v, %v, expected size 8", info, err)
+	}
+}
+
+func TestSectionFile(t *testing.T) {
+	f := openTestFile(t, "header|section body|trailer")
+	sf := NewSectionFile(f, 7, 12)
+
+	data, err := io.ReadAll(sf)
+	if err != nil || string(data) != "section body" {
+		t.Fatalf("got %q, %v", data, err)
+	}
+
+	buf := make([]byte, 4)
+	for _, test := range []struct {
+		offset int64
+		whence int
+		pos    int64
+		read   string
+	}{
+		{0, io.SeekStart, 0, "sect"},
+		{4, io.SeekStart, 4, "ion "},
+		{-4, io.SeekEnd, 8, "body"},
+		{-6, io.SeekCurrent, 6, "n bo"},
+		{-2, io.SeekEnd, 10, "dy"},
+		{5, io.SeekEnd, 17, ""},
+	} {
+		pos, err := sf.Seek(test.offset, test.whence)
+		if err != nil || pos != test.pos {
+			t.Errorf("Seek(%d, %d): got %d, %v, expected %d", test.offset, test.whence, pos, err, test.pos)
+			continue
+		}
+		n, err := sf.Read(buf)
+		if string(buf[:n]) != test.read {
+			t.Errorf("after Seek(%d, %d): got %q, %v, expected %q", test.offset, test.whence, buf[:n], err, test.read)
+		}
+	}
+	if _, err := sf.Seek(-1, io.SeekStart); !errors.Is(err, syscall.EINVAL) {
+		t.Errorf("got %v, expected %v", err, syscall.EINVAL)
+	}
+
+	if n, err := sf.ReadAt(buf, 8); n != 4 || err != nil || string(buf) != "body" {
+		t.Errorf("ReadAt: got %q, %v", buf[:n], err)
+	}
+	if _, err := sf.WriteAt([]byte("x"), 0); !errors.Is(err, syscall.EBADF) {
+		t.Errorf("WriteAt: got %v, expected %v", err, syscall.EBADF)
+	}
+	if info, err := sf.Stat(); err != nil || info.Size() != 12 {
+		t.Errorf("Stat: got %v, %v, expected size 12", info, err)
+	}
+}
@@ -0,0 +1,305 @@
+package absfs
+
+import (
+	"io"
+	"os"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// Snapshot - returns a read-only view of `fsys` that keeps presenting the
+// state it observed, so a long running operation such as a backup sees a
+// stable tree even if `fsys` is modified meanwhile.
+//
+// The snapshot is taken lazily: the metadata of a path is captured the first
+// time it is looked up, the contents of a file the first time it is opened,
+// and the listing of a directory, together with the metadata of its entries,
+// the first time it is opened. Later changes to `fsys` are not visible
+// through the snapshot, but changes made before a path is first accessed are.
+// Every file read through the snapshot is held in memory in full until
+// `Release` is called, so memory use grows with the amount of data read.
+//
+// All mutations fail with `syscall.EROFS`. The snapshot has its own working
+// directory, initially that of `fsys`.
+func Snapshot(fsys FileSystem) *SnapshotFS {
+	cwd, err := fsys.Getwd()
+	if err != nil {
+		cwd = string(fsys.Separator())
+	}
+	return &SnapshotFS{
+		fsys:  fsys,
+		cwd:   cwd,
+		infos: make(map[string]snapInfo),
+		data:  make(map[string][]byte),
+		dirs:  make(map[string][]os.FileInfo),
+	}
+}
+
+// SnapshotFS - is the read-only FileSystem returned by `Snapshot`.
+type SnapshotFS struct {
+	fsys FileSystem
+
+	mu    sync.Mutex
+	cwd   string
+	infos map[string]snapInfo
+	data  map[string][]byte
+	dirs  map[string][]os.FileInfo
+}
+
+// snapInfo - the captured result of a `Stat`.
+type snapInfo struct {
+	info os.FileInfo
+	err  error
+}
+
+// Release - frees all captured data. Paths accessed after `Release` are
+// captured again from the current state of the underlying FileSystem.
+func (s *SnapshotFS) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.infos = make(map[string]snapInfo)
+	s.data = make(map[string][]byte)
+	s.dirs = make(map[string][]os.FileInfo)
+}
+
+// abs - returns the cleaned absolute form of `name`. The caller must hold
+// `s.mu`.
+func (s *SnapshotFS) abs(name string) string {
+	if IsAbs(name) {
+		return Clean(s.fsys, name)
+	}
+	return Join(s.fsys, s.cwd, name)
+}
+
+// stat - returns the captured metadata of the absolute path `p`, capturing
+// it if needed. The caller must hold `s.mu`.
+func (s *SnapshotFS) stat(p string) (os.FileInfo, error) {
+	si, ok := s.infos[p]
+	if !ok {
+		si.info, si.err = s.fsys.Stat(p)
+		s.infos[p] = si
+	}
+	return si.info, si.err
+}
+
+func (s *SnapshotFS) readOnly(op, name string) error {
+	return &os.PathError{Op: op, Path: name, Err: syscall.EROFS}
+}
+
+func (s *SnapshotFS) Separator() uint8     { return s.fsys.Separator() }
+func (s *SnapshotFS) ListSeparator() uint8 { return s.fsys.ListSeparator() }
+func (s *SnapshotFS) TempDir() string      { return s.fsys.TempDir() }
+
+func (s *SnapshotFS) Getwd() (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cwd, nil
+}
+
+func (s *SnapshotFS) Chdir(dir string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.abs(dir)
+	info, err := s.stat(p)
+	if err != nil {
+		return &os.PathError{Op: "chdir", Path: dir, Err: unwrapPathError(err)}
+	}
+	if !info.IsDir() {
+		return &os.PathError{Op: "chdir", Path: dir, Err: syscall.ENOTDIR}
+	}
+	s.cwd = p
+	return nil
+}
+
+func (s *SnapshotFS) Stat(name string) (os.FileInfo, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.stat(s.abs(name))
+}
+
+func (s *SnapshotFS) Open(name string) (File, error) {
+	return s.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (s *SnapshotFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) != 0 {
+		err := s.readOnly("open", name)
+		return &InvalidFile{Path: name, Err: err}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	p := s.abs(name)
+	info, err := s.stat(p)
+	if err != nil {
+		return &InvalidFile{Path: name, Err: err}, err
+	}
+	if info.IsDir() {
+		entries, err := s.listing(p)
+		if err != nil {
+			return &InvalidFile{Path: name, Err: err}, err
+		}
+		return &snapDir{name: name, info: info, entries: entries}, nil
+	}
+	data, ok := s.data[p]
+	if !ok {
+		data, err = ReadFile(s.fsys, p)
+		if err != nil {
+			return &InvalidFile{Path: name, Err: err}, err
+		}
+		s.data[p] = data
+	}
+	f := NewSectionFile(NewMemFile(name, data, info.Mode()), 0, int64(len(data)))
+	return &snapFile{f, info}, nil
+}
+
+// listing - returns the captured entries of the directory `p`, capturing
+// them, and their metadata, if needed. The caller must hold `s.mu`.
+func (s *SnapshotFS) listing(p string) ([]os.FileInfo, error) {
+	if entries, ok := s.dirs[p]; ok {
+		return entries, nil
+	}
+	f, err := s.fsys.Open(p)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	entries, err := ReaddirAll(f)
+	if err != nil {
+		return nil, err
+	}
+	for _, info := range entries {
+		child := Join(s.fsys, p, info.Name())
+		if _, ok := s.infos[child]; !ok {
+			s.infos[child] = snapInfo{info: info}
+		}
+	}
+	s.dirs[p] = entries
+	return entries, nil
+}
+
+func (s *SnapshotFS) Create(name string) (File, error) {
+	err := s.readOnly("open", name)
+	return &InvalidFile{Path: name, Err: err}, err
+}
+
+func (s *SnapshotFS) Mkdir(name string, perm os.FileMode) error {
+	return s.readOnly("mkdir", name)
+}
+
+func (s *SnapshotFS) MkdirAll(name string, perm os.FileMode) error {
+	return s.readOnly("mkdir", name)
+}
+
+func (s *SnapshotFS) Remove(name string) error {
+	return s.readOnly("remove", name)
+}
+
+func (s *SnapshotFS) RemoveAll(name string) error {
+	return s.readOnly("removeall", name)
+}
+
+func (s *SnapshotFS) Rename(oldpath, newpath string) error {
+	return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: syscall.EROFS}
+}
+
+func (s *SnapshotFS) Chmod(name string, mode os.FileMode) error {
+	return s.readOnly("chmod", name)
+}
+
+func (s *SnapshotFS) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	return s.readOnly("chtimes", name)
+}
+
+func (s *SnapshotFS) Chown(name string, uid, gid int) error {
+	return s.readOnly("chown", name)
+}
+
+func (s *SnapshotFS) Truncate(name string, size int64) error {
+	return s.readOnly("truncate", name)
+}
+
+// snapFile - a read-only copy of a file captured by a snapshot.
+type snapFile struct {
+	File
+	info os.FileInfo
+}
+
+func (f *snapFile) Stat() (os.FileInfo, error) {
+	return f.info, nil
+}
+
+// snapDir - a directory listing captured by a snapshot.
+type snapDir struct {
+	name    string
+	info    os.FileInfo
+	entries []os.FileInfo
+	offset  int
+}
+
+func (d *snapDir) pathError(op string, err error) error {
+	return &os.PathError{Op: op, Path: d.name, Err: err}
+}
+
+func (d *snapDir) Name() string               { return d.name }
+func (d *snapDir) Stat() (os.FileInfo, error) { return d.info, nil }
+func (d *snapDir) Sync() error                { return nil }
+func (d *snapDir) Close() error               { return nil }
+
+func (d *snapDir) Read(b []byte) (int, error) {
+	return 0, d.pathError("read", syscall.EISDIR)
+}
+
+func (d *snapDir) ReadAt(b []byte, off int64) (int, error) {
+	return 0, d.pathError("read", syscall.EISDIR)
+}
+
+func (d *snapDir) Write(b []byte) (int, error) {
+	return 0, d.pathError("write", syscall.EROFS)
+}
+
+func (d *snapDir) WriteAt(b []byte, off int64) (int, error) {
+	return 0, d.pathError("write", syscall.EROFS)
+}
+
+func (d *snapDir) WriteString(s string) (int, error) {
+	return 0, d.pathError("write", syscall.EROFS)
+}
+
+func (d *snapDir) Truncate(size int64) error {
+	return d.pathError("truncate", syscall.EROFS)
+}
+
+// Seek - only supports rewinding the listing to the start.
+func (d *snapDir) Seek(offset int64, whence int) (int64, error) {
+	if offset != 0 || whence != io.SeekStart {
+		return 0, d.pathError("seek", syscall.EINVAL)
+	}
+	d.offset = 0
+	return 0, nil
+}
+
+func (d *snapDir) Readdir(n int) ([]os.FileInfo, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return append([]os.FileInfo(nil), rest...), nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	if n > len(rest) {
+		n = len(rest)
+	}
+	d.offset += n
+	return append([]os.FileInfo(nil), rest[:n]...), nil
+}
+
+func (d *snapDir) Readdirnames(n int) ([]string, error) {
+	infos, err := d.Readdir(n)
+	names := make([]string, len(infos))
+	for i, info := range infos {
+		names[i] = info.Name()
+	}
+	return names, err
+}
@@ -0,0 +1,74 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestSnapshot(t *testing.T) {
+	fsys := newTestFS()
+	if err := fsys.Mkdir("/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, fsys, "/dir/a", "version 1")
+	writeTestFile(t, fsys, "/dir/b", "bbb")
+
+	snap := Snapshot(fsys)
+	if names := listTestDir(t, snap, "/dir"); len(names) != 2 {
+		t.Fatalf("got %v, expected 2 entries", names)
+	}
+	if got := readTestFile(t, snap, "/dir/a"); got != "version 1" {
+		t.Fatalf("got %q", got)
+	}
+
+	// external changes are not visible
+	writeTestFile(t, fsys, "/dir/a", "version 2, longer")
+	writeTestFile(t, fsys, "/dir/c", "new")
+	if err := fsys.Remove("/dir/b"); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := readTestFile(t, snap, "/dir/a"); got != "version 1" {
+		t.Errorf("got %q, expected %q", got, "version 1")
+	}
+	if info, err := snap.Stat("/dir/a"); err != nil || info.Size() != 9 {
+		t.Errorf("got %v, %v, expected size 9", info, err)
+	}
+	if info, err := snap.Stat("/dir/b"); err != nil || info.Size() != 3 {
+		t.Errorf("removed entry: got %v, %v", info, err)
+	}
+	if names := listTestDir(t, snap, "/dir"); len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Errorf("got %v, expected [a b]", names)
+	}
+
+	// relative paths use the snapshot's own working directory
+	if err := snap.Chdir("/dir"); err != nil {
+		t.Fatal(err)
+	}
+	if got := readTestFile(t, snap, "a"); got != "version 1" {
+		t.Errorf("got %q, expected %q", got, "version 1")
+	}
+	if wd, _ := fsys.Getwd(); wd != "/" {
+		t.Errorf("underlying working directory changed to %q", wd)
+	}
+
+	for _, err := range []error{
+		snap.Mkdir("/x", 0755),
+		snap.Remove("/dir/a"),
+		snap.Rename("/dir/a", "/x"),
+		snap.Chmod("/dir/a", 0600),
+		func() error { _, err := snap.Create("/x"); return err }(),
+		func() error { _, err := snap.OpenFile("/dir/a", os.O_RDWR, 0); return err }(),
+	} {
+		if !errors.Is(err, syscall.EROFS) {
+			t.Errorf("got %v, expected %v", err, syscall.EROFS)
+		}
+	}
+
+	snap.Release()
+	if got := readTestFile(t, snap, "/dir/a"); got != "version 2, longer" {
+		t.Errorf("after Release: got %q", got)
+	}
+}
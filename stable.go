@@ -0,0 +1,74 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrFileChanged - is returned when a file reopened by name is no longer the
+// file that was originally opened, for example because it was renamed or
+// replaced in the meantime.
+var ErrFileChanged = errors.New("file changed")
+
+// StableFile - returns a File that records the identity of `f`, taken from
+// `f.Stat()` when it is wrapped, so that code which falls back to reopening
+// the file by name, such as `Dup`, can check that it reached the same file.
+// If it did not, the fallback fails with an `*os.PathError` wrapping
+// `ErrFileChanged` instead of silently using whatever file now has the name.
+//
+// Files are compared with `SameFile`. On backends that don't report a stable
+// identity the comparison falls back to the size, mode, and modification
+// time, so a file that was modified after it was wrapped is also reported as
+// changed, and an identical file put in its place is not detected. If `f`
+// can't be stat'ed, no identity is recorded and nothing is checked.
+func StableFile(f File) File {
+	info, err := f.Stat()
+	if err != nil {
+		return f
+	}
+	return &stableFile{File: f, info: info}
+}
+
+type stableFile struct {
+	File
+	info os.FileInfo
+}
+
+// verifier - is implemented by files that can check that another handle
+// refers to the same file.
+type verifier interface {
+	verify(op string, g File) error
+}
+
+func (f *stableFile) verify(op string, g File) error {
+	info, err := g.Stat()
+	if err != nil {
+		return err
+	}
+	if !SameFile(f.info, info) {
+		return &os.PathError{Op: op, Path: f.File.Name(), Err: ErrFileChanged}
+	}
+	return nil
+}
+
+// SameFile - reports whether `fi1` and `fi2` describe the same file. When
+// both carry a device and inode number in `Sys`, or are both from the `os`
+// package, those identify the file. Otherwise, since there is nothing to
+// identify the file by, it reports whether they have the same size, mode, and
+// modification time.
+func SameFile(fi1, fi2 os.FileInfo) bool {
+	if id1, ok := fileIdentity(fi1.Sys()); ok {
+		if id2, ok := fileIdentity(fi2.Sys()); ok {
+			return id1 == id2
+		}
+	}
+	if os.SameFile(fi1, fi2) {
+		return true
+	}
+	return fi1.Size() == fi2.Size() && fi1.Mode() == fi2.Mode() && fi1.ModTime().Equal(fi2.ModTime())
+}
+
+// identity - is a device and inode number.
+type identity struct {
+	dev, ino uint64
+}
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package absfs
+
+// fileIdentity - reports that `Sys` carries no file identity on this
+// platform.
+func fileIdentity(sys interface{}) (identity, bool) {
+	return identity{}, false
+}
@@ -0,0 +1,51 @@
+package absfs
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestStableFile(t *testing.T) {
+	for _, tt := range []struct {
+		name string
+		fsys FileSystem
+	}{
+		{"mock", newTestFS()},
+		{"os", ExtendFiler(OSFiler(t.TempDir()))},
+	} {
+		fsys := tt.fsys
+		writeTestFile(t, fsys, "/file", "original")
+		f, err := fsys.Open("/file")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer f.Close()
+		f = StableFile(f)
+
+		dup, err := Dup(fsys, f)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		dup.Close()
+
+		// replace the file behind the handle's back
+		if err := fsys.Rename("/file", "/moved"); err != nil {
+			t.Fatal(err)
+		}
+		writeTestFile(t, fsys, "/file", "replacement")
+		if _, err := Dup(fsys, f); !errors.Is(err, ErrFileChanged) {
+			t.Errorf("%s: got %v, expected %v", tt.name, err, ErrFileChanged)
+		}
+
+		// moving the original back makes the name refer to it again
+		if err := fsys.Rename("/moved", "/file"); err != nil {
+			t.Fatal(err)
+		}
+		dup, err = Dup(fsys, f)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+		} else {
+			dup.Close()
+		}
+	}
+}
@@ -0,0 +1,15 @@
+//go:build unix
+
+package absfs
+
+import "syscall"
+
+// fileIdentity - returns the device and inode number from a
+// `*syscall.Stat_t`.
+func fileIdentity(sys interface{}) (identity, bool) {
+	st, ok := sys.(*syscall.Stat_t)
+	if !ok {
+		return identity{}, false
+	}
+	return identity{uint64(st.Dev), uint64(st.Ino)}, true
+}
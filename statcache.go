@@ -0,0 +1,191 @@
+package absfs
+
+import (
+	"os"
+	"time"
+)
+
+// WithStatCache - returns a FileSystem that reuses the results of `Stat`, and
+// of `Lstat` called as a method on it, for `ttl`, so walking code that stats
+// the same paths repeatedly makes fewer calls to a slow `fsys`. Only
+// successful results are cached, so checking that a file does not exist
+// always asks `fsys`. If `ttl` is zero or less `fsys` is returned unchanged.
+//
+// Every mutation through the returned FileSystem, including `Chmod`,
+// `Chtimes`, `Truncate`, `Remove`, and `Rename`, invalidates the affected
+// paths and everything below them. Files opened for writing invalidate their
+// path when opened and again on every write, `Truncate`, and `Close`.
+//
+// Results can be stale for up to `ttl` after a change made other than
+// through the returned FileSystem, such as by another process or through
+// `fsys` directly, and the size and modification time of a directory are not
+// refreshed when entries are added to or removed from it. Use
+// `InvalidateStat` to drop a path that is known to have changed. The returned
+// FileSystem is safe for concurrent use if `fsys` is.
+func WithStatCache(fsys FileSystem, ttl time.Duration) FileSystem {
+	if ttl <= 0 {
+		return fsys
+	}
+	return &statcachefs{
+		FileSystem: fsys,
+		stats:      newStatCache(ttl),
+		lstats:     newStatCache(ttl),
+	}
+}
+
+// InvalidateStat - drops the cached `Stat` and `Lstat` results for `name` and
+// everything below it, if `fsys` was returned by `WithStatCache`; otherwise
+// it does nothing.
+func InvalidateStat(fsys FileSystem, name string) error {
+	c, ok := fsys.(*statcachefs)
+	if !ok {
+		return nil
+	}
+	key, err := Abs(c.FileSystem, name)
+	if err != nil {
+		return err
+	}
+	c.invalidate(key)
+	return nil
+}
+
+type statcachefs struct {
+	FileSystem
+	stats  *statCache
+	lstats *statCache
+}
+
+// invalidate - drops the cached results for `key` and everything below it.
+func (c *statcachefs) invalidate(key string) {
+	sep := c.Separator()
+	c.stats.invalidate(sep, key)
+	c.lstats.invalidate(sep, key)
+}
+
+// forget - drops the cached results for `key` only.
+func (c *statcachefs) forget(key string) {
+	c.stats.forget(key)
+	c.lstats.forget(key)
+}
+
+// cached - returns the result cached in `s` for `name`, calling `stat` and
+// caching its result on a miss.
+func (c *statcachefs) cached(s *statCache, name string, stat func(string) (os.FileInfo, error)) (os.FileInfo, error) {
+	key, err := Abs(c.FileSystem, name)
+	if err != nil {
+		return nil, err
+	}
+	return s.get(key, stat)
+}
+
+func (c *statcachefs) Stat(name string) (os.FileInfo, error) {
+	return c.cached(c.stats, name, c.FileSystem.Stat)
+}
+
+// Lstat - is `Lstat` on the underlying filesystem, which is `Stat` when it
+// does not support symbolic links.
+func (c *statcachefs) Lstat(name string) (os.FileInfo, error) {
+	return c.cached(c.lstats, name, func(key string) (os.FileInfo, error) {
+		return Lstat(c.FileSystem, key)
+	})
+}
+
+func (c *statcachefs) Open(name string) (File, error) {
+	return c.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (c *statcachefs) Create(name string) (File, error) {
+	return c.OpenFile(name, os.O_CREATE|os.O_RDWR|os.O_TRUNC, 0666)
+}
+
+func (c *statcachefs) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	if flag&(os.O_WRONLY|os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND) == 0 {
+		return c.FileSystem.OpenFile(name, flag, perm)
+	}
+	key, err := Abs(c.FileSystem, name)
+	if err != nil {
+		return nil, err
+	}
+	c.forget(key)
+	f, err := c.FileSystem.OpenFile(name, flag, perm)
+	if err != nil {
+		return f, err
+	}
+	return &statCacheFile{f, c, key}, nil
+}
+
+// statCacheFile - invalidates the cached results for a file written through
+// it.
+type statCacheFile struct {
+	File
+	c   *statcachefs
+	key string
+}
+
+func (f *statCacheFile) Write(b []byte) (int, error) {
+	defer f.c.forget(f.key)
+	return f.File.Write(b)
+}
+
+func (f *statCacheFile) WriteAt(b []byte, off int64) (int, error) {
+	defer f.c.forget(f.key)
+	return f.File.WriteAt(b, off)
+}
+
+func (f *statCacheFile) WriteString(s string) (int, error) {
+	defer f.c.forget(f.key)
+	return f.File.WriteString(s)
+}
+
+func (f *statCacheFile) Truncate(size int64) error {
+	defer f.c.forget(f.key)
+	return f.File.Truncate(size)
+}
+
+func (f *statCacheFile) Close() error {
+	defer f.c.forget(f.key)
+	return f.File.Close()
+}
+
+// mutate - invalidates `name`, calls `fn`, and invalidates `name` again.
+func (c *statcachefs) mutate(name string, fn func() error) error {
+	return mutatePath(c.FileSystem, name, c.invalidate, fn)
+}
+
+func (c *statcachefs) Mkdir(name string, perm os.FileMode) error {
+	return c.mutate(name, func() error { return c.FileSystem.Mkdir(name, perm) })
+}
+
+func (c *statcachefs) MkdirAll(name string, perm os.FileMode) error {
+	return c.mutate(name, func() error { return c.FileSystem.MkdirAll(name, perm) })
+}
+
+func (c *statcachefs) Remove(name string) error {
+	return c.mutate(name, func() error { return c.FileSystem.Remove(name) })
+}
+
+func (c *statcachefs) RemoveAll(name string) error {
+	return c.mutate(name, func() error { return c.FileSystem.RemoveAll(name) })
+}
+
+func (c *statcachefs) Truncate(name string, size int64) error {
+	return c.mutate(name, func() error { return c.FileSystem.Truncate(name, size) })
+}
+
+func (c *statcachefs) Chmod(name string, mode os.FileMode) error {
+	return c.mutate(name, func() error { return c.FileSystem.Chmod(name, mode) })
+}
+
+func (c *statcachefs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	return c.mutate(name, func() error { return c.FileSystem.Chtimes(name, atime, mtime) })
+}
+
+func (c *statcachefs) Chown(name string, uid, gid int) error {
+	return c.mutate(name, func() error { return c.FileSystem.Chown(name, uid, gid) })
+}
+
+func (c *statcachefs) Rename(oldpath, newpath string) error {
+	return c.mutate(oldpath, func() error {
+		return c.mutate(newpath, func() error { return c.FileSystem.Rename(oldpath, newpath) })
+	})
+}
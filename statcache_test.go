@@ -0,0 +1,124 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestWithStatCache(t *testing.T) {
+	backing := &statCountFS{FileSystem: newTestFS()}
+	if err := backing.MkdirAll("/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, backing, "/dir/file", "data")
+	fsys := WithStatCache(backing, time.Hour)
+
+	stat := func(name string, size int64) {
+		t.Helper()
+		info, err := fsys.Stat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Size() != size {
+			t.Errorf("%s: got size %d, expected %d", name, info.Size(), size)
+		}
+	}
+
+	backing.stats = 0
+	for i := 0; i < 3; i++ {
+		stat("/dir/file", 4)
+	}
+	if err := fsys.Chdir("/dir"); err != nil {
+		t.Fatal(err)
+	}
+	stat("file", 4)
+	if backing.stats != 1 {
+		t.Errorf("backing stat %d times, expected 1", backing.stats)
+	}
+
+	// writes through a handle invalidate the path
+	f, err := fsys.OpenFile("/dir/file", os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stat("/dir/file", 4)
+	if _, err := f.WriteAt([]byte(" more"), 4); err != nil {
+		t.Fatal(err)
+	}
+	stat("/dir/file", 9)
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fsys.Truncate("/dir/file", 2); err != nil {
+		t.Fatal(err)
+	}
+	stat("/dir/file", 2)
+
+	if err := fsys.Chmod("/dir/file", 0600); err != nil {
+		t.Fatal(err)
+	}
+	if info, err := fsys.Stat("/dir/file"); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("got %v, %v after Chmod", info, err)
+	}
+
+	// renaming a directory invalidates everything below it
+	if err := fsys.Rename("/dir", "/moved"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fsys.Stat("/dir/file"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrNotExist)
+	}
+	stat("/moved/file", 2)
+
+	// changes made behind the cache's back need InvalidateStat
+	if err := backing.Truncate("/moved/file", 0); err != nil {
+		t.Fatal(err)
+	}
+	stat("/moved/file", 2)
+	if err := InvalidateStat(fsys, "/moved"); err != nil {
+		t.Fatal(err)
+	}
+	stat("/moved/file", 0)
+}
+
+func TestWithStatCacheExpires(t *testing.T) {
+	backing := &statCountFS{FileSystem: newTestFS()}
+	writeTestFile(t, backing, "/file", "data")
+	fsys := WithStatCache(backing, time.Millisecond)
+
+	backing.stats = 0
+	if _, err := fsys.Stat("/file"); err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(5 * time.Millisecond)
+	if _, err := fsys.Stat("/file"); err != nil {
+		t.Fatal(err)
+	}
+	if backing.stats != 2 {
+		t.Errorf("backing stat %d times, expected 2", backing.stats)
+	}
+
+	if WithStatCache(backing, 0) != FileSystem(backing) {
+		t.Error("a zero ttl should return the filesystem unchanged")
+	}
+}
+
+func TestWithStatCacheInvalidateRoot(t *testing.T) {
+	backing := newTestFS()
+	writeTestFile(t, backing, "/f", "data")
+	fsys := WithStatCache(backing, time.Hour)
+	if _, err := fsys.Stat("/f"); err != nil {
+		t.Fatal(err)
+	}
+
+	fsys.RemoveAll("/")
+	if _, err := backing.Stat("/f"); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("backing: got %v, expected the file to be removed", err)
+	}
+	if _, err := fsys.Stat("/f"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected a stale result to be dropped", err)
+	}
+}
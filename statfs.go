@@ -0,0 +1,39 @@
+package absfs
+
+import "os"
+
+// FSStat - describes the capacity of a filesystem. The fields mirror those of
+// `statfs(2)` but carry no backend specific data, so in-memory and remote
+// filesystems can report synthetic values.
+type FSStat struct {
+	BlockSize  int64  // size of a block in bytes.
+	Blocks     uint64 // total number of blocks.
+	BlocksFree uint64 // number of blocks available for writing.
+	Files      uint64 // total number of file nodes.
+	FilesFree  uint64 // number of file nodes available.
+}
+
+// Total - returns the total size of the filesystem in bytes.
+func (s FSStat) Total() uint64 {
+	return s.Blocks * uint64(s.BlockSize)
+}
+
+// Free - returns the number of bytes available for writing.
+func (s FSStat) Free() uint64 {
+	return s.BlocksFree * uint64(s.BlockSize)
+}
+
+// StatFSer - is implemented by filesystems that can report their capacity.
+type StatFSer interface {
+	StatFS(name string) (FSStat, error)
+}
+
+// Usage - returns the capacity of the filesystem containing `path`. If
+// `fsys` does not implement `StatFSer` the error is an `*os.PathError`
+// wrapping `ErrNotImplemented`.
+func Usage(fsys FileSystem, path string) (FSStat, error) {
+	if s, ok := fsys.(StatFSer); ok {
+		return s.StatFS(path)
+	}
+	return FSStat{}, &os.PathError{Op: "statfs", Path: path, Err: ErrNotImplemented}
+}
@@ -0,0 +1,36 @@
+package absfs
+
+import (
+	"errors"
+	"testing"
+)
+
+// statfsFiler - reports a fixed capacity.
+type statfsFiler struct {
+	*mockFiler
+	stat FSStat
+	name string
+}
+
+func (m *statfsFiler) StatFS(name string) (FSStat, error) {
+	m.name = name
+	return m.stat, nil
+}
+
+func TestUsage(t *testing.T) {
+	if _, err := Usage(newTestFS(), "/"); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("got %v, expected %v", err, ErrNotImplemented)
+	}
+
+	m := &statfsFiler{mockFiler: newMockFiler(), stat: FSStat{BlockSize: 4096, Blocks: 100, BlocksFree: 25}}
+	stat, err := Usage(ExtendFiler(m), "dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stat != m.stat || m.name != "/dir" {
+		t.Errorf("got %+v for %q, expected %+v for %q", stat, m.name, m.stat, "/dir")
+	}
+	if stat.Total() != 409600 || stat.Free() != 102400 {
+		t.Errorf("got total %d and free %d", stat.Total(), stat.Free())
+	}
+}
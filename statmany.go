@@ -0,0 +1,58 @@
+package absfs
+
+import (
+	"os"
+	"sync"
+)
+
+// BulkStater - is implemented by filesystems that can stat many files in one
+// request. `StatMany` returns one `os.FileInfo` and one error per name, in
+// the same order as `names`; for each name exactly one of the two is nil.
+type BulkStater interface {
+	StatMany(names []string) ([]os.FileInfo, []error)
+}
+
+// statManyWorkers - is the number of concurrent `Stat` calls made by
+// `StatMany` when the filesystem does not implement `BulkStater`.
+const statManyWorkers = 8
+
+// StatMany - returns the `os.FileInfo` of each of `names`, and the error
+// from stating it, in the same order as `names`. If `fsys` implements
+// `BulkStater` the call is delegated to it, so backends that can batch
+// lookups, such as remote stores, answer with one round trip. Otherwise
+// `Stat` is called for each name by a pool of up to 8 goroutines, so `fsys`
+// must be safe for concurrent use (see `Synchronized`).
+func StatMany(fsys FileSystem, names []string) ([]os.FileInfo, []error) {
+	if b, ok := fsys.(BulkStater); ok {
+		return b.StatMany(names)
+	}
+	return statMany(fsys, names)
+}
+
+// statMany - implements `StatMany` with concurrent calls to `Stat`.
+func statMany(fsys FileSystem, names []string) ([]os.FileInfo, []error) {
+	infos := make([]os.FileInfo, len(names))
+	errs := make([]error, len(names))
+	workers := statManyWorkers
+	if len(names) < workers {
+		workers = len(names)
+	}
+
+	next := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				infos[i], errs[i] = fsys.Stat(names[i])
+			}
+		}()
+	}
+	for i := range names {
+		next <- i
+	}
+	close(next)
+	wg.Wait()
+	return infos, errs
+}
@@ -0,0 +1,80 @@
+package absfs
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+)
+
+// bulkStatFiler - answers `StatMany` in one call and counts round trips.
+type bulkStatFiler struct {
+	*mockFiler
+	mu    sync.Mutex
+	trips int
+}
+
+func (m *bulkStatFiler) Stat(name string) (os.FileInfo, error) {
+	m.mu.Lock()
+	m.trips++
+	m.mu.Unlock()
+	return m.mockFiler.Stat(name)
+}
+
+func (m *bulkStatFiler) StatMany(names []string) ([]os.FileInfo, []error) {
+	m.trips++
+	infos := make([]os.FileInfo, len(names))
+	errs := make([]error, len(names))
+	for i, name := range names {
+		infos[i], errs[i] = m.mockFiler.Stat(name)
+	}
+	return infos, errs
+}
+
+// noBulkStatFiler - hides the `StatMany` method of a `bulkStatFiler`.
+type noBulkStatFiler struct {
+	*bulkStatFiler
+	StatMany struct{}
+}
+
+func TestStatMany(t *testing.T) {
+	m := &bulkStatFiler{mockFiler: newMockFiler()}
+	var names []string
+	for i := 0; i < 50; i++ {
+		name := fmt.Sprintf("/file%02d", i)
+		writeTestFile(t, ExtendFiler(m), name, name)
+		names = append(names, name)
+	}
+	names = append(names, "/missing", "file07")
+
+	check := func(infos []os.FileInfo, errs []error) {
+		t.Helper()
+		if len(infos) != len(names) || len(errs) != len(names) {
+			t.Fatalf("got %d infos and %d errors for %d names", len(infos), len(errs), len(names))
+		}
+		for i := 0; i < 50; i++ {
+			if errs[i] != nil || infos[i].Size() != 7 || infos[i].Name() != names[i][1:] {
+				t.Errorf("%s: got %v, %v", names[i], infos[i], errs[i])
+			}
+		}
+		if infos[50] != nil || !errors.Is(errs[50], os.ErrNotExist) {
+			t.Errorf("/missing: got %v, %v", infos[50], errs[50])
+		}
+		if errs[51] != nil || infos[51].Name() != "file07" {
+			t.Errorf("relative name: got %v, %v", infos[51], errs[51])
+		}
+	}
+
+	m.trips = 0
+	check(StatMany(ExtendFiler(m), names))
+	if m.trips != 1 {
+		t.Errorf("got %d round trips, expected 1", m.trips)
+	}
+
+	m.trips = 0
+	check(StatMany(ExtendFiler(&noBulkStatFiler{bulkStatFiler: m}), names))
+	if m.trips != len(names) {
+		t.Errorf("got %d round trips, expected %d", m.trips, len(names))
+	}
+}
@@ -0,0 +1,202 @@
+package absfs
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// ExtendReadOnlyFiler - returns a FileSystem that serves `Open` (and
+// `OpenFile` with `O_RDONLY`) from `r`. The returned files are read-only
+// streams: `Read` and `Close` are passed through, while writes, seeks, and
+// directory operations return errors. All other FileSystem methods that
+// inspect or modify the filesystem return `ErrNotImplemented`.
+//
+// This lets minimal streaming sources, such as HTTP bodies or object store
+// GETs, be used wherever a FileSystem is expected.
+func ExtendReadOnlyFiler(r ReadOnlyFiler) FileSystem {
+	return &streamfs{cwd: "/", r: r}
+}
+
+// ExtendWriteOnlyFiler - returns a FileSystem that serves `Create` (and
+// `OpenFile` with `O_WRONLY`) from `w`. The returned files are write-only
+// streams: `Write` and `Close` are passed through, while reads, seeks, and
+// directory operations return errors. All other FileSystem methods that
+// inspect or modify the filesystem return `ErrNotImplemented`.
+func ExtendWriteOnlyFiler(w WriteOnlyFiler) FileSystem {
+	return &streamfs{cwd: "/", w: w}
+}
+
+type streamfs struct {
+	cwd string
+	r   ReadOnlyFiler
+	w   WriteOnlyFiler
+}
+
+func (fs *streamfs) abs(name string) string {
+	if !filepath.IsAbs(name) {
+		name = filepath.Join(fs.cwd, name)
+	}
+	return filepath.Clean(name)
+}
+
+func (fs *streamfs) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	name = fs.abs(name)
+	switch {
+	case flag&O_ACCESS == O_RDONLY && fs.r != nil:
+		rc, err := fs.r.Open(name)
+		if err != nil {
+			return &InvalidFile{Path: name}, err
+		}
+		return &streamFile{name: name, r: rc}, nil
+	case flag&O_ACCESS == O_WRONLY && fs.w != nil:
+		wc, err := fs.w.Open(name)
+		if err != nil {
+			return &InvalidFile{Path: name}, err
+		}
+		return &streamFile{name: name, w: wc}, nil
+	}
+	return &InvalidFile{Path: name}, &os.PathError{Op: "open", Path: name, Err: ErrNotImplemented}
+}
+
+func (fs *streamfs) Mkdir(name string, perm os.FileMode) error {
+	return &os.PathError{Op: "mkdir", Path: name, Err: ErrNotImplemented}
+}
+
+func (fs *streamfs) Remove(name string) error {
+	return &os.PathError{Op: "remove", Path: name, Err: ErrNotImplemented}
+}
+
+func (fs *streamfs) Rename(oldpath, newpath string) error {
+	return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: ErrNotImplemented}
+}
+
+func (fs *streamfs) Stat(name string) (os.FileInfo, error) {
+	return nil, &os.PathError{Op: "stat", Path: name, Err: ErrNotImplemented}
+}
+
+func (fs *streamfs) Chmod(name string, mode os.FileMode) error {
+	return &os.PathError{Op: "chmod", Path: name, Err: ErrNotImplemented}
+}
+
+func (fs *streamfs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	return &os.PathError{Op: "chtimes", Path: name, Err: ErrNotImplemented}
+}
+
+func (fs *streamfs) Chown(name string, uid, gid int) error {
+	return &os.PathError{Op: "chown", Path: name, Err: ErrNotImplemented}
+}
+
+func (fs *streamfs) Separator() uint8 {
+	return filepath.Separator
+}
+
+func (fs *streamfs) ListSeparator() uint8 {
+	return filepath.ListSeparator
+}
+
+// Chdir - changes the directory used to resolve relative paths. A streaming
+// source cannot be inspected, so `dir` is not checked for existence.
+func (fs *streamfs) Chdir(dir string) error {
+	fs.cwd = fs.abs(dir)
+	return nil
+}
+
+func (fs *streamfs) Getwd() (dir string, err error) {
+	return fs.cwd, nil
+}
+
+func (fs *streamfs) TempDir() string {
+	return os.TempDir()
+}
+
+func (fs *streamfs) Open(name string) (File, error) {
+	return fs.OpenFile(name, os.O_RDONLY, 0)
+}
+
+func (fs *streamfs) Create(name string) (File, error) {
+	return fs.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0666)
+}
+
+func (fs *streamfs) MkdirAll(name string, perm os.FileMode) error {
+	return &os.PathError{Op: "mkdir", Path: name, Err: ErrNotImplemented}
+}
+
+func (fs *streamfs) RemoveAll(path string) (err error) {
+	return &os.PathError{Op: "removeall", Path: path, Err: ErrNotImplemented}
+}
+
+func (fs *streamfs) Truncate(name string, size int64) error {
+	return &os.PathError{Op: "truncate", Path: name, Err: ErrNotImplemented}
+}
+
+// streamFile - is a non-seekable `File` over either an `io.ReadCloser` or an
+// `io.WriteCloser`.
+type streamFile struct {
+	name string
+	r    io.ReadCloser
+	w    io.WriteCloser
+}
+
+func (f *streamFile) Name() string {
+	return f.name
+}
+
+func (f *streamFile) Read(p []byte) (int, error) {
+	if f.r == nil {
+		return 0, &os.PathError{Op: "read", Path: f.name, Err: syscall.EBADF}
+	}
+	return f.r.Read(p)
+}
+
+func (f *streamFile) Write(p []byte) (int, error) {
+	if f.w == nil {
+		return 0, &os.PathError{Op: "write", Path: f.name, Err: syscall.EBADF}
+	}
+	return f.w.Write(p)
+}
+
+func (f *streamFile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
+
+func (f *streamFile) Close() error {
+	if f.r != nil {
+		return f.r.Close()
+	}
+	return f.w.Close()
+}
+
+func (f *streamFile) Sync() error {
+	return nil
+}
+
+func (f *streamFile) Stat() (os.FileInfo, error) {
+	return nil, &os.PathError{Op: "stat", Path: f.name, Err: ErrNotImplemented}
+}
+
+func (f *streamFile) Readdir(int) ([]os.FileInfo, error) {
+	return nil, &os.PathError{Op: "readdir", Path: f.name, Err: ErrNotImplemented}
+}
+
+func (f *streamFile) Readdirnames(n int) ([]string, error) {
+	return nil, &os.PathError{Op: "readdirnames", Path: f.name, Err: ErrNotImplemented}
+}
+
+func (f *streamFile) Seek(offset int64, whence int) (int64, error) {
+	return 0, &os.PathError{Op: "seek", Path: f.name, Err: ErrNotImplemented}
+}
+
+func (f *streamFile) ReadAt(b []byte, off int64) (int, error) {
+	return 0, &os.PathError{Op: "read", Path: f.name, Err: ErrNotImplemented}
+}
+
+func (f *streamFile) WriteAt(b []byte, off int64) (int, error) {
+	return 0, &os.PathError{Op: "write", Path: f.name, Err: ErrNotImplemented}
+}
+
+func (f *streamFile) Truncate(size int64) error {
+	return &os.PathError{Op: "truncate", Path: f.name, Err: ErrNotImplemented}
+}
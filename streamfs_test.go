@@ -0,0 +1,130 @@
+package absfs
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+// mapReader - serves the contents of a map as read-only streams.
+type mapReader map[string]string
+
+func (m mapReader) Open(name string) (io.ReadCloser, error) {
+	data, ok := m[name]
+	if !ok {
+		return nil, &os.PathError{Op: "open", Path: name, Err: os.ErrNotExist}
+	}
+	return io.NopCloser(strings.NewReader(data)), nil
+}
+
+// mapWriter - stores the streams written to it in a map when they are
+// closed.
+type mapWriter map[string]string
+
+func (m mapWriter) Open(name string) (io.WriteCloser, error) {
+	return &mapWriteCloser{m: m, name: name}, nil
+}
+
+type mapWriteCloser struct {
+	bytes.Buffer
+	m    mapWriter
+	name string
+}
+
+func (w *mapWriteCloser) Close() error {
+	w.m[w.name] = w.String()
+	return nil
+}
+
+func TestExtendReadOnlyFiler(t *testing.T) {
+	fsys := ExtendReadOnlyFiler(mapReader{"/dir/file": "data"})
+
+	if err := fsys.Chdir("/dir"); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fsys.Open("file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "data" {
+		t.Errorf("got %q, expected %q", data, "data")
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err == nil {
+		t.Error("Seek: expected an error")
+	}
+	if _, err := f.Write([]byte("x")); err == nil {
+		t.Error("Write: expected an error")
+	}
+	if _, err := fsys.Open("/missing"); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %v, expected %v", err, os.ErrNotExist)
+	}
+	if _, err := fsys.Create("/new"); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("Create: got %v, expected %v", err, ErrNotImplemented)
+	}
+	testStreamMutations(t, fsys)
+}
+
+func TestExtendWriteOnlyFiler(t *testing.T) {
+	w := mapWriter{}
+	fsys := ExtendWriteOnlyFiler(w)
+
+	f, err := fsys.Create("/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("data"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Read(make([]byte, 4)); err == nil {
+		t.Error("Read: expected an error")
+	}
+	if _, err := f.Seek(0, io.SeekStart); err == nil {
+		t.Error("Seek: expected an error")
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if w["/file"] != "data" {
+		t.Errorf("got %q, expected %q", w["/file"], "data")
+	}
+
+	if _, err := fsys.Open("/file"); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("Open: got %v, expected %v", err, ErrNotImplemented)
+	}
+	testStreamMutations(t, fsys)
+}
+
+// testStreamMutations - checks that every method that inspects or modifies
+// a streaming FileSystem returns `ErrNotImplemented`.
+func testStreamMutations(t *testing.T, fsys FileSystem) {
+	t.Helper()
+	now := time.Now()
+	for op, err := range map[string]error{
+		"Mkdir":     fsys.Mkdir("/dir", 0755),
+		"MkdirAll":  fsys.MkdirAll("/dir/sub", 0755),
+		"Remove":    fsys.Remove("/file"),
+		"RemoveAll": fsys.RemoveAll("/dir"),
+		"Rename":    fsys.Rename("/file", "/moved"),
+		"Chmod":     fsys.Chmod("/file", 0600),
+		"Chtimes":   fsys.Chtimes("/file", now, now),
+		"Chown":     fsys.Chown("/file", 0, 0),
+		"Truncate":  fsys.Truncate("/file", 0),
+	} {
+		if !errors.Is(err, ErrNotImplemented) {
+			t.Errorf("%s: got %v, expected %v", op, err, ErrNotImplemented)
+		}
+	}
+	if _, err := fsys.Stat("/file"); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("Stat: got %v, expected %v", err, ErrNotImplemented)
+	}
+}
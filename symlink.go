@@ -0,0 +1,177 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"syscall"
+)
+
+// maxSymlinks - is the number of symbolic links followed while resolving a
+// single path before giving up with `syscall.ELOOP`.
+const maxSymlinks = 255
+
+// Lstat - returns the `os.FileInfo` of `name` without following a final
+// symbolic link. If `fsys` implements `SymLinker` its `Lstat` is called;
+// otherwise `fsys` has no symbolic links, so `Lstat` and `Stat` are
+// equivalent and `Stat` is called. This lets tools that need to detect links
+// run unchanged against simple backends.
+func Lstat(fsys FileSystem, name string) (os.FileInfo, error) {
+	if sl, ok := fsys.(SymLinker); ok {
+		return sl.Lstat(name)
+	}
+	return fsys.Stat(name)
+}
+
+// EvalSymlinks - returns the absolute path name after the evaluation of any
+// symbolic links in `name`, resolving one path element at a time with `Lstat`
+// and `Readlink`. Relative paths are resolved against the filesystem's
+// working directory. If a link cycle is detected, or more than 255 links are
+// followed, the error is an `*os.PathError` wrapping `syscall.ELOOP`.
+func EvalSymlinks(fsys SymlinkFileSystem, name string) (string, error) {
+	if !IsAbs(name) {
+		wd, err := fsys.Getwd()
+		if err != nil {
+			return "", err
+		}
+		name = Join(fsys, wd, name)
+	}
+
+	sep := fsys.Separator()
+	root := string(sep)
+	resolved := root
+	rest := strings.Split(toSlash(sep, name), "/")
+	links := 0
+	for len(rest) > 0 {
+		elem := rest[0]
+		rest = rest[1:]
+		switch elem {
+		case "", ".":
+			continue
+		case "..":
+			resolved = Dir(fsys, resolved)
+			continue
+		}
+
+		next := Join(fsys, resolved, elem)
+		info, err := fsys.Lstat(next)
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			resolved = next
+			continue
+		}
+
+		links++
+		if links > maxSymlinks {
+			return "", &os.PathError{Op: "evalsymlinks", Path: name, Err: syscall.ELOOP}
+		}
+		target, err := fsys.Readlink(next)
+		if err != nil {
+			return "", err
+		}
+		if IsAbs(target) {
+			resolved = root
+		}
+		rest = append(strings.Split(toSlash(sep, target), "/"), rest...)
+	}
+	return resolved, nil
+}
+
+// ErrPathEscapes - is returned by `SecureJoin` when a path would resolve to a
+// location outside of the root it was joined to.
+var ErrPathEscapes = errors.New("path escapes root")
+
+// SecureJoin - joins the untrusted `unsafePath` to `root` and returns the
+// cleaned result, guaranteeing that it lies within `root`. Unlike
+// `filepath.Join` followed by a prefix check, ".." elements are resolved one
+// at a time, and if any of them would climb above `root` the error is an
+// `*os.PathError` wrapping `ErrPathEscapes`. Absolute `unsafePath`s are
+// treated as relative to `root`. `unsafePath` is split on slashes as well as
+// the host separator, and the result uses the host separator.
+//
+// `SecureJoin` does not consult any filesystem, so a symbolic link below
+// `root` can still lead outside of it; use `SecureJoinFS` to resolve links.
+func SecureJoin(root, unsafePath string) (string, error) {
+	return secureJoin(filepath.Separator, nil, root, unsafePath)
+}
+
+// SecureJoinFS - is like `SecureJoin`, but if `fsys` implements `SymLinker`
+// symbolic links are resolved one path element at a time with `Lstat` and
+// `Readlink`, in the manner of the securejoin algorithm. Absolute link targets
+// are interpreted relative to `root`, as if `root` were the root of a chroot,
+// and a link whose target climbs above `root` is an error wrapping
+// `ErrPathEscapes`. Elements that do not exist are joined lexically. If more
+// than 255 links are followed the error wraps `syscall.ELOOP`. Paths are
+// split on slashes as well as the separator of `fsys`, and the result uses
+// the separator of `fsys`.
+//
+// The result is only safe as long as the tree below `root` is not modified by
+// an untrusted party between the call and the use of the result.
+func SecureJoinFS(fsys FileSystem, root, unsafePath string) (string, error) {
+	sl, _ := fsys.(SymLinker)
+	return secureJoin(fsys.Separator(), sl, root, unsafePath)
+}
+
+func secureJoin(sep uint8, sl SymLinker, root, unsafePath string) (string, error) {
+	root = cleanSep(sep, root)
+	join := func(elems []string) string {
+		return fromSlash(sep, path.Join(root, path.Join(elems...)))
+	}
+	var resolved []string
+	rest := strings.Split(toSlash(sep, unsafePath), "/")
+	links := 0
+	for len(rest) > 0 {
+		elem := rest[0]
+		rest = rest[1:]
+		switch elem {
+		case "", ".":
+			continue
+		case "..":
+			if len(resolved) == 0 {
+				return "", &os.PathError{Op: "securejoin", Path: unsafePath, Err: ErrPathEscapes}
+			}
+			resolved = resolved[:len(resolved)-1]
+			continue
+		}
+
+		resolved = append(resolved, elem)
+		if sl == nil {
+			continue
+		}
+		next := join(resolved)
+		info, err := sl.Lstat(next)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if info.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+
+		links++
+		if links > maxSymlinks {
+			return "", &os.PathError{Op: "securejoin", Path: unsafePath, Err: syscall.ELOOP}
+		}
+		target, err := sl.Readlink(next)
+		if err != nil {
+			return "", err
+		}
+		resolved = resolved[:len(resolved)-1]
+		if IsAbs(target) {
+			resolved = resolved[:0]
+		}
+		rest = append(strings.Split(toSlash(sep, target), "/"), rest...)
+	}
+
+	joined := join(resolved)
+	if !IsDescendant(sep, root, joined) {
+		return "", &os.PathError{Op: "securejoin", Path: unsafePath, Err: ErrPathEscapes}
+	}
+	return joined, nil
+}
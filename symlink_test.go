@@ -0,0 +1,189 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestSecureJoin(t *testing.T) {
+	tests := []struct {
+		unsafe   string
+		expected string
+		err      error
+	}{
+		{"a/b", "/srv/a/b", nil},
+		{"/a/b", "/srv/a/b", nil},
+		{"a/../b", "/srv/b", nil},
+		{"", "/srv", nil},
+		{"a/../..", "", ErrPathEscapes},
+		{"../srv/a", "", ErrPathEscapes},
+	}
+	for _, test := range tests {
+		got, err := SecureJoin("/srv", test.unsafe)
+		if !errors.Is(err, test.err) || got != test.expected {
+			t.Errorf("SecureJoin(%q) = %q, %v; expected %q, %v", test.unsafe, got, err, test.expected, test.err)
+		}
+	}
+}
+
+func TestSecureJoinFS(t *testing.T) {
+	fsys := newTestSymlinkFS()
+	if err := fsys.MkdirAll("/srv/www/data", 0755); err != nil {
+		t.Fatal(err)
+	}
+	links := map[string]string{
+		"/srv/www/abs":    "/data",
+		"/srv/www/rel":    "data",
+		"/srv/www/up":     "../../etc",
+		"/srv/www/loop1":  "loop2",
+		"/srv/www/loop2":  "loop1",
+		"/srv/www/parent": "..",
+	}
+	for link, target := range links {
+		if err := fsys.Symlink(target, link); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		unsafe   string
+		expected string
+		err      error
+	}{
+		{"data/file", "/srv/www/data/file", nil},
+		{"abs/file", "/srv/www/data/file", nil},
+		{"rel/file", "/srv/www/data/file", nil},
+		{"data/../rel", "/srv/www/data", nil},
+		{"missing/../rel", "/srv/www/data", nil},
+		{"up/passwd", "", ErrPathEscapes},
+		{"parent/www", "", ErrPathEscapes},
+		{"loop1", "", syscall.ELOOP},
+	}
+	for _, test := range tests {
+		got, err := SecureJoinFS(fsys, "/srv/www", test.unsafe)
+		if !errors.Is(err, test.err) || got != test.expected {
+			t.Errorf("SecureJoinFS(%q) = %q, %v; expected %q, %v", test.unsafe, got, err, test.expected, test.err)
+		}
+	}
+}
+
+func TestSecureJoinFSBackslash(t *testing.T) {
+	sfs := newTestSymlinkFS()
+	if err := sfs.MkdirAll("/srv/www", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := sfs.Symlink(`..\..\etc`, "/srv/www/up"); err != nil {
+		t.Fatal(err)
+	}
+	fsys := &backslashSymlinkFS{sfs}
+
+	tests := []struct {
+		unsafe   string
+		expected string
+		err      error
+	}{
+		{`a\b`, `\srv\www\a\b`, nil},
+		{"a/b", `\srv\www\a\b`, nil},
+		{`..\..\etc`, "", ErrPathEscapes},
+		{"../../etc", "", ErrPathEscapes},
+		{`a/..\..`, "", ErrPathEscapes},
+		{`up\passwd`, "", ErrPathEscapes},
+	}
+	for _, test := range tests {
+		got, err := SecureJoinFS(fsys, `\srv\www`, test.unsafe)
+		if !errors.Is(err, test.err) || got != test.expected {
+			t.Errorf("SecureJoinFS(%q) = %q, %v; expected %q, %v", test.unsafe, got, err, test.expected, test.err)
+		}
+	}
+}
+
+func TestLstat(t *testing.T) {
+	sfs := newTestSymlinkFS()
+	createFiles(t, sfs, "/target")
+	if err := sfs.Symlink("/target", "/link"); err != nil {
+		t.Fatal(err)
+	}
+	info, err := Lstat(sfs, "/link")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("got mode %s, expected a symbolic link", info.Mode())
+	}
+
+	fsys := newTestFS()
+	createFiles(t, fsys, "/file")
+	info, err = Lstat(fsys, "/file")
+	if err != nil || !info.Mode().IsRegular() {
+		t.Errorf("got %v, %v, expected the Stat result", info, err)
+	}
+}
+
+// backslashSymlinkFS - reports a backslash separator, mapping names back to
+// slashes for the mock.
+type backslashSymlinkFS struct {
+	SymlinkFileSystem
+}
+
+func (fs *backslashSymlinkFS) Separator() uint8 { return '\\' }
+
+func (fs *backslashSymlinkFS) Getwd() (string, error) {
+	dir, err := fs.SymlinkFileSystem.Getwd()
+	return fromSlash('\\', dir), err
+}
+
+func (fs *backslashSymlinkFS) Lstat(name string) (os.FileInfo, error) {
+	return fs.SymlinkFileSystem.Lstat(toSlash('\\', name))
+}
+
+func (fs *backslashSymlinkFS) Readlink(name string) (string, error) {
+	link, err := fs.SymlinkFileSystem.Readlink(toSlash('\\', name))
+	return fromSlash('\\', link), err
+}
+
+func TestEvalSymlinks(t *testing.T) {
+	sfs := newTestSymlinkFS()
+	if err := sfs.MkdirAll("/a/b", 0755); err != nil {
+		t.Fatal(err)
+	}
+	createFiles(t, sfs, "/a/b/file")
+	if err := sfs.Symlink("b", "/a/rel"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sfs.Symlink("/a/b", "/abs"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sfs.Symlink("/loop", "/loop"); err != nil {
+		t.Fatal(err)
+	}
+	if err := sfs.Chdir("/a"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, fsys := range []SymlinkFileSystem{sfs, &backslashSymlinkFS{sfs}} {
+		sep := fsys.Separator()
+		s := func(p string) string {
+			return fromSlash(sep, p)
+		}
+		tests := []struct {
+			name     string
+			expected string
+		}{
+			{s("/a/rel/file"), s("/a/b/file")},
+			{s("/abs/file"), s("/a/b/file")},
+			{s("/abs/../rel"), s("/a/b")},
+			{s("rel/file"), s("/a/b/file")},
+		}
+		for _, test := range tests {
+			got, err := EvalSymlinks(fsys, test.name)
+			if err != nil || got != test.expected {
+				t.Errorf("%q EvalSymlinks(%q) = %q, %v; expected %q", sep, test.name, got, err, test.expected)
+			}
+		}
+		if _, err := EvalSymlinks(fsys, s("/loop")); !errors.Is(err, syscall.ELOOP) {
+			t.Errorf("%q got %v, expected %v", sep, err, syscall.ELOOP)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package absfs
+
+import "os"
+
+// ExtendSymlinkFiler - combines a `Filer` and a `SymLinker` into a
+// `SymlinkFileSystem`. The `Filer` is extended as by `ExtendFiler`, and the
+// same relative path resolution and name validation is applied to the names
+// passed to `Lstat`, `Lchown`, `Readlink`, and to the new name passed to
+// `Symlink`. The target of a symbolic link is stored as given, so relative
+// targets remain relative to the directory containing the link. Errors from
+// `Symlink` are normalized to `*os.LinkError`.
+func ExtendSymlinkFiler(filer Filer, sym SymLinker) SymlinkFileSystem {
+	fs := ExtendFiler(filer).(*fs)
+	fs.sym = sym
+	return &symlinkfs{fs, sym}
+}
+
+type symlinkfs struct {
+	*fs
+	sym SymLinker
+}
+
+func (fs *symlinkfs) Lstat(name string) (os.FileInfo, error) {
+	if err := fs.validPath("lstat", name); err != nil {
+		return nil, err
+	}
+	return fs.sym.Lstat(fs.path(name))
+}
+
+func (fs *symlinkfs) Lchown(name string, uid, gid int) error {
+	if err := fs.validPath("lchown", name); err != nil {
+		return err
+	}
+	return fs.sym.Lchown(fs.path(name), uid, gid)
+}
+
+func (fs *symlinkfs) Readlink(name string) (string, error) {
+	if err := fs.validPath("readlink", name); err != nil {
+		return "", err
+	}
+	return fs.sym.Readlink(fs.path(name))
+}
+
+func (fs *symlinkfs) Symlink(oldname, newname string) error {
+	if err := fs.validPath("symlink", newname); err != nil {
+		return linkError("symlink", oldname, newname, err)
+	}
+	return linkError("symlink", oldname, newname, fs.sym.Symlink(oldname, fs.path(newname)))
+}
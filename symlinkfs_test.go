@@ -0,0 +1,50 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestExtendSymlinkFiler(t *testing.T) {
+	fsys := newTestSymlinkFS()
+	if err := fsys.Mkdir("/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+	writeTestFile(t, fsys, "/dir/target", "data")
+	if err := fsys.Chdir("/dir"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := fsys.Symlink("target", "link"); err != nil {
+		t.Fatal(err)
+	}
+	if target, err := fsys.Readlink("/dir/link"); err != nil || target != "target" {
+		t.Errorf("Readlink: got %q, %v, expected the relative target to be kept", target, err)
+	}
+	info, err := fsys.Lstat("link")
+	if err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("Lstat: got %v, %v, expected a symbolic link", info, err)
+	}
+	if got := readTestFile(t, fsys, "link"); got != "data" {
+		t.Errorf("got %q through the link", got)
+	}
+	if err := fsys.Lchown("link", 1, 2); err != nil {
+		t.Error(err)
+	}
+
+	var lerr *os.LinkError
+	if err := fsys.Symlink("target", "link"); !errors.As(err, &lerr) || !errors.Is(err, os.ErrExist) {
+		t.Errorf("got %v, expected an *os.LinkError wrapping %v", err, os.ErrExist)
+	}
+	if err := fsys.Symlink("target", "bad\x00"); !errors.As(err, &lerr) || !errors.Is(err, ErrInvalidName) {
+		t.Errorf("got %v, expected an *os.LinkError wrapping %v", err, ErrInvalidName)
+	}
+
+	if err := fsys.RemoveAll("/dir/link"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := fsys.Stat("/dir/target"); err != nil {
+		t.Errorf("RemoveAll followed the link: %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package absfs
+
+// Syncer - is implemented by filesystems that buffer or batch writes
+// internally and can flush them to stable storage on request.
+type Syncer interface {
+	Sync() error
+}
+
+// SyncFS - flushes all pending writes in `fsys` by calling its `Sync` method
+// if it implements `Syncer`; otherwise it does nothing and returns nil.
+//
+// `File.Sync` only flushes the data written through one handle. `SyncFS` is a
+// single durability point for a whole batch of operations, including
+// metadata changes such as `Rename` and `Remove`, without having to track
+// every open handle.
+func SyncFS(fsys FileSystem) error {
+	if s, ok := fsys.(Syncer); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// CloseSync - calls `f.Sync` and then `f.Close`, and returns the first error.
+// `Close` is always attempted, even if `Sync` fails, so the handle is never
+// leaked. Use it in place of `f.Close` for handles that have been written to,
+// since some backends only persist data on `Sync` and a failed flush reported
+// only through `Sync` would otherwise be lost.
+func CloseSync(f File) error {
+	err := f.Sync()
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
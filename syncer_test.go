@@ -0,0 +1,79 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+)
+
+// syncFiler - counts calls to `Sync` and returns `err`.
+type syncFiler struct {
+	*mockFiler
+	syncs int
+	err   error
+}
+
+func (m *syncFiler) Sync() error {
+	m.syncs++
+	return m.err
+}
+
+func TestSyncFS(t *testing.T) {
+	if err := SyncFS(newTestFS()); err != nil {
+		t.Errorf("got %v, expected a no-op", err)
+	}
+
+	m := &syncFiler{mockFiler: newMockFiler()}
+	fsys := ExtendFiler(m)
+	if err := SyncFS(fsys); err != nil || m.syncs != 1 {
+		t.Errorf("got %v after %d calls, expected the Filer to be synced once", err, m.syncs)
+	}
+
+	m.err = errors.New("flush failed")
+	if err := SyncFS(fsys); err != m.err {
+		t.Errorf("got %v, expected %v", err, m.err)
+	}
+}
+
+// closeSyncFile - records the order of `Sync` and `Close` calls.
+type closeSyncFile struct {
+	File
+	calls   []string
+	syncErr error
+}
+
+func (f *closeSyncFile) Sync() error {
+	f.calls = append(f.calls, "sync")
+	return f.syncErr
+}
+
+func (f *closeSyncFile) Close() error {
+	f.calls = append(f.calls, "close")
+	return f.File.Close()
+}
+
+func TestCloseSync(t *testing.T) {
+	f := &closeSyncFile{File: NewMemFile("file", nil, 0644)}
+	if err := CloseSync(f); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(f.calls, ",") != "sync,close" {
+		t.Errorf("got calls %v, expected sync then close", f.calls)
+	}
+
+	// Close is still attempted when Sync fails, and the Sync error wins
+	f = &closeSyncFile{File: NewMemFile("file", nil, 0644), syncErr: errors.New("flush failed")}
+	if err := CloseSync(f); err != f.syncErr {
+		t.Errorf("got %v, expected %v", err, f.syncErr)
+	}
+	if len(f.calls) != 2 {
+		t.Errorf("got calls %v, expected sync then close", f.calls)
+	}
+
+	// the Close error is returned when Sync succeeds
+	f.syncErr = nil
+	if err := CloseSync(f); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("got %v, expected %v", err, os.ErrClosed)
+	}
+}
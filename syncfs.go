@@ -0,0 +1,252 @@
+package absfs
+
+import (
+	"os"
+	"sync"
+	"time"
+)
+
+// Synchronized - returns a FileSystem that guards every operation on `fsys`
+// with a `sync.RWMutex`, making it safe to share a non-thread-safe `Filer`
+// between goroutines. Lookups (`Stat`, `Open`, `Getwd`) take a read lock and
+// all mutations take the write lock.
+//
+// File handles returned by `Synchronized` are wrapped with their own mutex so
+// that concurrent calls on a single handle (e.g. `Read` and `Seek`) are
+// serialized. Handle calls also take the filesystem's lock, a read lock for
+// reads, `Stat` and directory listings and the write lock for writes,
+// `Truncate` and `Close`, so they never run at the same time as a mutation
+// of the shared `Filer`.
+func Synchronized(fsys FileSystem) FileSystem {
+	return &syncfs{fs: fsys}
+}
+
+type syncfs struct {
+	mu sync.RWMutex
+	fs FileSystem
+}
+
+func (s *syncfs) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := s.fs.OpenFile(name, flag, perm)
+	if err != nil {
+		return f, err
+	}
+	return &syncfile{fs: s, f: f}, nil
+}
+
+func (s *syncfs) Mkdir(name string, perm os.FileMode) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fs.Mkdir(name, perm)
+}
+
+func (s *syncfs) Remove(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fs.Remove(name)
+}
+
+func (s *syncfs) Rename(oldpath, newpath string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fs.Rename(oldpath, newpath)
+}
+
+func (s *syncfs) Stat(name string) (os.FileInfo, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.fs.Stat(name)
+}
+
+func (s *syncfs) Chmod(name string, mode os.FileMode) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fs.Chmod(name, mode)
+}
+
+func (s *syncfs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fs.Chtimes(name, atime, mtime)
+}
+
+func (s *syncfs) Chown(name string, uid, gid int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fs.Chown(name, uid, gid)
+}
+
+func (s *syncfs) Separator() uint8 {
+	return s.fs.Separator()
+}
+
+func (s *syncfs) ListSeparator() uint8 {
+	return s.fs.ListSeparator()
+}
+
+func (s *syncfs) Chdir(dir string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fs.Chdir(dir)
+}
+
+func (s *syncfs) Getwd() (dir string, err error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.fs.Getwd()
+}
+
+func (s *syncfs) TempDir() string {
+	return s.fs.TempDir()
+}
+
+func (s *syncfs) Open(name string) (File, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	f, err := s.fs.Open(name)
+	if err != nil {
+		return f, err
+	}
+	return &syncfile{fs: s, f: f}, nil
+}
+
+func (s *syncfs) Create(name string) (File, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	f, err := s.fs.Create(name)
+	if err != nil {
+		return f, err
+	}
+	return &syncfile{fs: s, f: f}, nil
+}
+
+func (s *syncfs) MkdirAll(name string, perm os.FileMode) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fs.MkdirAll(name, perm)
+}
+
+func (s *syncfs) RemoveAll(path string) (err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fs.RemoveAll(path)
+}
+
+func (s *syncfs) Truncate(name string, size int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.fs.Truncate(name, size)
+}
+
+// syncfile - serializes all calls on a single `File` handle. Calls that reach
+// the shared `Filer` also take the lock of the `syncfs` that opened the
+// handle: a read lock for reads, and the write lock for writes and `Close`.
+type syncfile struct {
+	mu sync.Mutex
+	fs *syncfs
+	f  File
+}
+
+func (f *syncfile) Name() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.f.Name()
+}
+
+func (f *syncfile) Read(b []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fs.mu.RLock()
+	defer f.fs.mu.RUnlock()
+	return f.f.Read(b)
+}
+
+func (f *syncfile) Write(b []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	return f.f.Write(b)
+}
+
+func (f *syncfile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	return f.f.Close()
+}
+
+func (f *syncfile) Sync() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	return f.f.Sync()
+}
+
+func (f *syncfile) Stat() (os.FileInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fs.mu.RLock()
+	defer f.fs.mu.RUnlock()
+	return f.f.Stat()
+}
+
+func (f *syncfile) Readdir(n int) ([]os.FileInfo, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fs.mu.RLock()
+	defer f.fs.mu.RUnlock()
+	return f.f.Readdir(n)
+}
+
+func (f *syncfile) Seek(offset int64, whence int) (int64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fs.mu.RLock()
+	defer f.fs.mu.RUnlock()
+	return f.f.Seek(offset, whence)
+}
+
+func (f *syncfile) ReadAt(b []byte, off int64) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fs.mu.RLock()
+	defer f.fs.mu.RUnlock()
+	return f.f.ReadAt(b, off)
+}
+
+func (f *syncfile) WriteAt(b []byte, off int64) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	return f.f.WriteAt(b, off)
+}
+
+func (f *syncfile) WriteString(s string) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	return f.f.WriteString(s)
+}
+
+func (f *syncfile) Truncate(size int64) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fs.mu.Lock()
+	defer f.fs.mu.Unlock()
+	return f.f.Truncate(size)
+}
+
+func (f *syncfile) Readdirnames(n int) ([]string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.fs.mu.RLock()
+	defer f.fs.mu.RUnlock()
+	return f.f.Readdirnames(n)
+}
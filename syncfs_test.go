@@ -0,0 +1,118 @@
+package absfs
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+)
+
+func TestSynchronized(t *testing.T) {
+	fsys := Synchronized(newTestFS())
+	if err := fsys.Mkdir("/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("/dir/file%02d", i)
+			f, err := fsys.Create(name)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := f.WriteString(name); err != nil {
+				t.Error(err)
+			}
+			f.Close()
+			if _, err := fsys.Stat(name); err != nil {
+				t.Error(err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	f, err := fsys.Open("/dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	names, err := f.Readdirnames(-1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names) != 16 {
+		t.Errorf("got %d entries, expected 16", len(names))
+	}
+}
+
+func TestSynchronizedHandle(t *testing.T) {
+	fsys := Synchronized(newTestFS())
+	f, err := fsys.Create("/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString("0123456789"); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			buf := make([]byte, 1)
+			for j := 0; j < 100; j++ {
+				if _, err := f.Seek(0, io.SeekStart); err != nil {
+					t.Error(err)
+					return
+				}
+				if _, err := f.Read(buf); err != nil && err != io.EOF {
+					t.Error(err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestSynchronizedHandleAndFilesystem(t *testing.T) {
+	fsys := Synchronized(newTestFS())
+	if err := fsys.Mkdir("/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			f, err := fsys.Create(fmt.Sprintf("/dir/file%03d", i))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			f.Close()
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			f, err := fsys.Open("/dir")
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := f.Readdirnames(-1); err != nil {
+				t.Error(err)
+			}
+			f.Close()
+		}
+	}()
+	wg.Wait()
+}
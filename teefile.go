@@ -0,0 +1,47 @@
+package absfs
+
+import "io"
+
+// TeeFile - returns a File that reads from `primary` and writes to both
+// `primary` and `mirror`, like `io.TeeReader` for writes. Every successful
+// `Write`, `WriteString`, and `WriteAt` is repeated on `mirror` with the bytes
+// the primary accepted, in call order; offsets are not passed on, since the
+// mirror is a plain `io.Writer`. Typical mirrors are a `hash.Hash` computing a
+// checksum while writing, or a shadow log.
+//
+// Writes return the primary's result. A mirror error is returned only after
+// the primary has succeeded, and reports the number of bytes written to the
+// primary. All other methods, including `Read`, `ReadAt`, and `Seek`, go to
+// `primary` unchanged.
+func TeeFile(primary File, mirror io.Writer) File {
+	return &teeFile{primary, mirror}
+}
+
+type teeFile struct {
+	File
+	mirror io.Writer
+}
+
+func (f *teeFile) mirrorWrite(b []byte, n int, err error) (int, error) {
+	if err != nil {
+		return n, err
+	}
+	if _, err := f.mirror.Write(b[:n]); err != nil {
+		return n, err
+	}
+	return n, nil
+}
+
+func (f *teeFile) Write(b []byte) (int, error) {
+	n, err := f.File.Write(b)
+	return f.mirrorWrite(b, n, err)
+}
+
+func (f *teeFile) WriteAt(b []byte, off int64) (int, error) {
+	n, err := f.File.WriteAt(b, off)
+	return f.mirrorWrite(b, n, err)
+}
+
+func (f *teeFile) WriteString(s string) (int, error) {
+	return f.Write([]byte(s))
+}
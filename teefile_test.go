@@ -0,0 +1,59 @@
+package absfs
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+)
+
+// failWriter - fails every write.
+type failWriter struct {
+	err error
+}
+
+func (w failWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
+
+func TestTeeFile(t *testing.T) {
+	fsys := newTestFS()
+	primary, err := fsys.Create("/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer primary.Close()
+
+	var mirror bytes.Buffer
+	f := TeeFile(primary, &mirror)
+	if _, err := f.Write([]byte("hello ")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("world"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteAt([]byte("H"), 0); err != nil {
+		t.Fatal(err)
+	}
+	if mirror.String() != "hello worldH" {
+		t.Errorf("mirror got %q", mirror.String())
+	}
+
+	buf := make([]byte, 11)
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadFull(f, buf); err != nil || string(buf) != "Hello world" {
+		t.Errorf("read %q, %v", buf, err)
+	}
+
+	errMirror := errors.New("mirror failed")
+	f = TeeFile(primary, failWriter{errMirror})
+	n, err := f.Write([]byte("!"))
+	if n != 1 || err != errMirror {
+		t.Errorf("got %d, %v, expected 1, %v", n, err, errMirror)
+	}
+	if got := readTestFile(t, fsys, "/file"); got != "Hello world!" {
+		t.Errorf("primary got %q", got)
+	}
+}
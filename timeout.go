@@ -0,0 +1,206 @@
+package absfs
+
+import (
+	"os"
+	"time"
+)
+
+// WithTimeout - returns a FileSystem that runs each operation on `fsys` in a
+// new goroutine and stops waiting for it after `d`, protecting callers from
+// backends that hang, such as an unresponsive network filesystem. An
+// operation that times out returns an `*os.PathError` (an `*os.LinkError` for
+// `Rename`) wrapping `os.ErrDeadlineExceeded`. A non-positive `d` disables the
+// timeout and `fsys` is returned unchanged.
+//
+// Go can't interrupt a blocked call, so an operation that times out keeps
+// running in the background and may still take effect later; the wrapper only
+// stops the caller from waiting for it. A file opened after its `OpenFile`
+// timed out is closed when the open completes.
+//
+// File handles returned by the wrapper apply the same timeout to `Read`,
+// `ReadAt`, `Write`, `WriteAt`, `WriteString`, and `Sync`. After a read times
+// out the background read may still write into the caller's buffer, so the
+// buffer should not be reused.
+func WithTimeout(fsys FileSystem, d time.Duration) FileSystem {
+	if d <= 0 {
+		return fsys
+	}
+	return &timeoutfs{fsys, d}
+}
+
+type timeoutfs struct {
+	FileSystem
+	d time.Duration
+}
+
+// withTimeout - runs `fn` in a new goroutine and returns its error, or
+// `os.ErrDeadlineExceeded` if it does not return within `d`. Values set by
+// `fn` must only be read when the returned error is not
+// `os.ErrDeadlineExceeded`.
+func withTimeout(d time.Duration, fn func() error) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- fn()
+	}()
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case err := <-done:
+		return err
+	case <-timer.C:
+		return os.ErrDeadlineExceeded
+	}
+}
+
+func (fs *timeoutfs) do(op, name string, fn func() error) error {
+	err := withTimeout(fs.d, fn)
+	if err == os.ErrDeadlineExceeded {
+		return &os.PathError{Op: op, Path: name, Err: err}
+	}
+	return err
+}
+
+// open - runs `fn` with a timeout, closing the file it opens if the timeout
+// expires first.
+func (fs *timeoutfs) open(name string, fn func() (File, error)) (File, error) {
+	type result struct {
+		f   File
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		f, err := fn()
+		done <- result{f, err}
+	}()
+	timer := time.NewTimer(fs.d)
+	defer timer.Stop()
+	select {
+	case r := <-done:
+		if r.err != nil {
+			return r.f, r.err
+		}
+		return &timeoutfile{r.f, fs.d}, nil
+	case <-timer.C:
+		go func() {
+			if r := <-done; r.err == nil {
+				r.f.Close()
+			}
+		}()
+		err := &os.PathError{Op: "open", Path: name, Err: os.ErrDeadlineExceeded}
+		return &InvalidFile{Path: name, Err: err}, err
+	}
+}
+
+func (fs *timeoutfs) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	return fs.open(name, func() (File, error) { return fs.FileSystem.OpenFile(name, flag, perm) })
+}
+
+func (fs *timeoutfs) Open(name string) (File, error) {
+	return fs.open(name, func() (File, error) { return fs.FileSystem.Open(name) })
+}
+
+func (fs *timeoutfs) Create(name string) (File, error) {
+	return fs.open(name, func() (File, error) { return fs.FileSystem.Create(name) })
+}
+
+func (fs *timeoutfs) Mkdir(name string, perm os.FileMode) error {
+	return fs.do("mkdir", name, func() error { return fs.FileSystem.Mkdir(name, perm) })
+}
+
+func (fs *timeoutfs) MkdirAll(name string, perm os.FileMode) error {
+	return fs.do("mkdir", name, func() error { return fs.FileSystem.MkdirAll(name, perm) })
+}
+
+func (fs *timeoutfs) Remove(name string) error {
+	return fs.do("remove", name, func() error { return fs.FileSystem.Remove(name) })
+}
+
+func (fs *timeoutfs) RemoveAll(name string) error {
+	return fs.do("removeall", name, func() error { return fs.FileSystem.RemoveAll(name) })
+}
+
+func (fs *timeoutfs) Rename(oldpath, newpath string) error {
+	err := withTimeout(fs.d, func() error { return fs.FileSystem.Rename(oldpath, newpath) })
+	if err == os.ErrDeadlineExceeded {
+		return &os.LinkError{Op: "rename", Old: oldpath, New: newpath, Err: err}
+	}
+	return err
+}
+
+func (fs *timeoutfs) Stat(name string) (os.FileInfo, error) {
+	var info os.FileInfo
+	err := fs.do("stat", name, func() (err error) {
+		info, err = fs.FileSystem.Stat(name)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+func (fs *timeoutfs) Chmod(name string, mode os.FileMode) error {
+	return fs.do("chmod", name, func() error { return fs.FileSystem.Chmod(name, mode) })
+}
+
+func (fs *timeoutfs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	return fs.do("chtimes", name, func() error { return fs.FileSystem.Chtimes(name, atime, mtime) })
+}
+
+func (fs *timeoutfs) Chown(name string, uid, gid int) error {
+	return fs.do("chown", name, func() error { return fs.FileSystem.Chown(name, uid, gid) })
+}
+
+func (fs *timeoutfs) Truncate(name string, size int64) error {
+	return fs.do("truncate", name, func() error { return fs.FileSystem.Truncate(name, size) })
+}
+
+func (fs *timeoutfs) Chdir(dir string) error {
+	return fs.do("chdir", dir, func() error { return fs.FileSystem.Chdir(dir) })
+}
+
+type timeoutfile struct {
+	File
+	d time.Duration
+}
+
+// rw - runs a read or write with a timeout.
+func (f *timeoutfile) rw(op string, fn func() (int, error)) (int, error) {
+	var n int
+	err := withTimeout(f.d, func() (err error) {
+		n, err = fn()
+		return err
+	})
+	if err == os.ErrDeadlineExceeded {
+		return 0, &os.PathError{Op: op, Path: f.File.Name(), Err: err}
+	}
+	return n, err
+}
+
+func (f *timeoutfile) Read(b []byte) (int, error) {
+	return f.rw("read", func() (int, error) { return f.File.Read(b) })
+}
+
+func (f *timeoutfile) ReadAt(b []byte, off int64) (int, error) {
+	return f.rw("read", func() (int, error) { return f.File.ReadAt(b, off) })
+}
+
+func (f *timeoutfile) Write(b []byte) (int, error) {
+	return f.rw("write", func() (int, error) { return f.File.Write(b) })
+}
+
+func (f *timeoutfile) WriteAt(b []byte, off int64) (int, error) {
+	return f.rw("write", func() (int, error) { return f.File.WriteAt(b, off) })
+}
+
+func (f *timeoutfile) WriteString(s string) (int, error) {
+	return f.rw("write", func() (int, error) { return f.File.WriteString(s) })
+}
+
+func (f *timeoutfile) Sync() error {
+	err := withTimeout(f.d, f.File.Sync)
+	if err == os.ErrDeadlineExceeded {
+		return &os.PathError{Op: "sync", Path: f.File.Name(), Err: err}
+	}
+	return err
+}
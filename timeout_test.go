@@ -0,0 +1,100 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// slowFiler - blocks `Stat` of "/dir" and `Rename` until `release` is
+// closed, and then fails them without touching the mock.
+type slowFiler struct {
+	*mockFiler
+	release chan struct{}
+}
+
+func (m *slowFiler) Stat(name string) (os.FileInfo, error) {
+	if name != "/dir" {
+		return m.mockFiler.Stat(name)
+	}
+	<-m.release
+	return nil, errSlow
+}
+
+func (m *slowFiler) Rename(oldpath, newpath string) error {
+	<-m.release
+	return errSlow
+}
+
+var errSlow = errors.New("released")
+
+// slowFile - blocks `Read` until `release` is closed, and then fails it.
+type slowFile struct {
+	File
+	release chan struct{}
+}
+
+func (f *slowFile) Read(b []byte) (int, error) {
+	<-f.release
+	return 0, errSlow
+}
+
+func TestWithTimeout(t *testing.T) {
+	m := &slowFiler{mockFiler: newMockFiler(), release: make(chan struct{})}
+	defer close(m.release)
+	fsys := WithTimeout(ExtendFiler(m), 20*time.Millisecond)
+
+	// operations that return promptly are unaffected
+	if err := fsys.Mkdir("/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := fsys.Create("/dir/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.Write([]byte("data")); err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+
+	start := time.Now()
+	_, err = fsys.Stat("/dir")
+	var perr *os.PathError
+	if !errors.As(err, &perr) || perr.Op != "stat" || !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("Stat: got %v, expected a stat error wrapping %v", err, os.ErrDeadlineExceeded)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("Stat took %s", d)
+	}
+
+	err = fsys.Rename("/dir/file", "/dir/other")
+	var lerr *os.LinkError
+	if !errors.As(err, &lerr) || !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("Rename: got %v, expected an *os.LinkError wrapping %v", err, os.ErrDeadlineExceeded)
+	}
+}
+
+func TestWithTimeoutFile(t *testing.T) {
+	fsys := newTestFS()
+	writeTestFile(t, fsys, "/file", "data")
+	g, err := fsys.Open("/file")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release := make(chan struct{})
+	defer close(release)
+	f := &timeoutfile{&slowFile{g, release}, 20 * time.Millisecond}
+
+	buf := make([]byte, 4)
+	if n, err := f.Read(buf); n != 0 || !errors.Is(err, os.ErrDeadlineExceeded) {
+		t.Errorf("Read: got %d, %v, expected %v", n, err, os.ErrDeadlineExceeded)
+	}
+	if n, err := f.ReadAt(buf, 0); n != 4 || err != nil {
+		t.Errorf("ReadAt: got %d, %v", n, err)
+	}
+
+	if WithTimeout(fsys, 0) != fsys {
+		t.Error("expected a non-positive timeout to return fsys unchanged")
+	}
+}
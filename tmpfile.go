@@ -0,0 +1,77 @@
+package absfs
+
+import "os"
+
+// TmpFiler - is implemented by filesystems that can create anonymous
+// temporary files, in the manner of `O_TMPFILE`. The file returned by
+// `OpenTmp` has no name until it is passed to `LinkTmp`, and its storage is
+// released when it is closed without having been linked. Files returned by
+// `OpenTmp` must implement `Link(name string) error`.
+type TmpFiler interface {
+	OpenTmp(dir string, perm os.FileMode) (File, error)
+}
+
+// tmpLinker - is implemented by the files returned by `OpenTmp`.
+type tmpLinker interface {
+	Link(name string) error
+}
+
+// OpenTmp - creates an anonymous temporary file in the directory `dir`. If
+// `fsys` implements `TmpFiler` the call is delegated to it. Otherwise a file
+// with a random name is created in `dir` and removed when the handle is
+// closed, unless it has been linked with `LinkTmp` first; unlike a native
+// anonymous file it is briefly visible in `dir`.
+func OpenTmp(fsys FileSystem, dir string, perm os.FileMode) (File, error) {
+	if t, ok := fsys.(TmpFiler); ok {
+		return t.OpenTmp(dir, perm)
+	}
+	return openTmp(fsys, dir, perm)
+}
+
+// LinkTmp - gives the temporary file `f`, returned by `OpenTmp`, the name
+// `name`, so that it persists after it is closed. `name` should be in the
+// directory the file was created in. If `f` was not returned by `OpenTmp` the
+// error is an `*os.PathError` wrapping `ErrNotImplemented`.
+func LinkTmp(f File, name string) error {
+	if l, ok := f.(tmpLinker); ok {
+		return l.Link(name)
+	}
+	return &os.PathError{Op: "link", Path: name, Err: ErrNotImplemented}
+}
+
+func openTmp(fsys FileSystem, dir string, perm os.FileMode) (File, error) {
+	f, name, err := createTemp(fsys, dir, ".tmp", perm)
+	if err != nil {
+		return nil, err
+	}
+	return &tmpFile{File: f, fsys: fsys, name: name}, nil
+}
+
+// tmpFile - emulates an anonymous temporary file with a named one.
+type tmpFile struct {
+	File
+	fsys   FileSystem
+	name   string
+	linked bool
+}
+
+func (f *tmpFile) Link(name string) error {
+	if f.linked {
+		return &os.LinkError{Op: "link", Old: f.name, New: name, Err: os.ErrExist}
+	}
+	if err := f.fsys.Rename(f.name, name); err != nil {
+		return err
+	}
+	f.name, f.linked = name, true
+	return nil
+}
+
+func (f *tmpFile) Close() error {
+	err := f.File.Close()
+	if !f.linked {
+		if rerr := f.fsys.Remove(f.name); err == nil {
+			err = rerr
+		}
+	}
+	return err
+}
@@ -0,0 +1,57 @@
+package absfs
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestOpenTmp(t *testing.T) {
+	fsys := newTestFS()
+	if err := fsys.Mkdir("/scratch", 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := OpenTmp(fsys, "/scratch", 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("scratch data"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if names := listTestDir(t, fsys, "/scratch"); len(names) != 0 {
+		t.Errorf("got %v, expected an unlinked file to be removed", names)
+	}
+
+	f, err = OpenTmp(fsys, "/scratch", 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := f.WriteString("kept"); err != nil {
+		t.Fatal(err)
+	}
+	if err := LinkTmp(f, "/scratch/kept"); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if names := listTestDir(t, fsys, "/scratch"); !reflect.DeepEqual(names, []string{"kept"}) {
+		t.Errorf("got %v, expected only the linked file", names)
+	}
+	if got := readTestFile(t, fsys, "/scratch/kept"); got != "kept" {
+		t.Errorf("got %q", got)
+	}
+
+	plain, err := fsys.Create("/plain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer plain.Close()
+	if err := LinkTmp(plain, "/other"); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("got %v, expected %v", err, ErrNotImplemented)
+	}
+}
@@ -0,0 +1,79 @@
+package absfs
+
+import (
+	"bytes"
+	"errors"
+	"hash"
+	"io"
+	"math"
+	"os"
+)
+
+// ErrChecksumMismatch - is returned when the digest of the data read from a
+// file does not match the expected digest.
+var ErrChecksumMismatch = errors.New("checksum mismatch")
+
+// VerifyFile - returns a File that feeds the bytes returned by `Read` through
+// `h` and, when a `Read` reaches EOF, compares the digest to `want`. If they
+// differ that `Read` returns an `*os.PathError` wrapping
+// `ErrChecksumMismatch` in place of `io.EOF`, so download and restore tools
+// detect corruption without a second pass over the data. `h` should be newly
+// created or reset.
+//
+// Only a sequential read of the whole file is verified. `ReadAt` is random
+// access and is passed through without hashing. A `Seek` back to the start
+// resets `h`; a `Seek` to any other offset than the current one disables
+// verification, since the digest would no longer cover the whole file.
+func VerifyFile(f File, want []byte, h hash.Hash) File {
+	return &verifyFile{File: f, want: want, h: h}
+}
+
+type verifyFile struct {
+	File
+	want     []byte
+	h        hash.Hash
+	hashed   int64
+	disabled bool
+}
+
+func (f *verifyFile) Read(b []byte) (int, error) {
+	n, err := f.File.Read(b)
+	if f.disabled {
+		return n, err
+	}
+	f.h.Write(b[:n])
+	f.hashed += int64(n)
+	if err == io.EOF && !bytes.Equal(f.h.Sum(nil), f.want) {
+		err = &os.PathError{Op: "read", Path: f.File.Name(), Err: ErrChecksumMismatch}
+	}
+	return n, err
+}
+
+func (f *verifyFile) Seek(offset int64, whence int) (int64, error) {
+	ret, err := f.File.Seek(offset, whence)
+	if err != nil || f.disabled || ret == f.hashed {
+		return ret, err
+	}
+	if ret == 0 {
+		f.h.Reset()
+		f.hashed = 0
+		return ret, nil
+	}
+	f.disabled = true
+	return ret, nil
+}
+
+// Verify - reads the whole of `f` from the start through `h` and returns an
+// `*os.PathError` wrapping `ErrChecksumMismatch` if the digest does not match
+// `want`. It reads with `ReadAt`, so the offset of `f` is not moved. `h` is
+// reset first.
+func Verify(f File, want []byte, h hash.Hash) error {
+	h.Reset()
+	if _, err := io.Copy(h, io.NewSectionReader(f, 0, math.MaxInt64)); err != nil {
+		return err
+	}
+	if !bytes.Equal(h.Sum(nil), want) {
+		return &os.PathError{Op: "verify", Path: f.Name(), Err: ErrChecksumMismatch}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package absfs
+
+import (
+	"crypto/sha256"
+	"errors"
+	"io"
+	"testing"
+)
+
+func TestVerifyFile(t *testing.T) {
+	content := "the quick brown fox"
+	sum := sha256.Sum256([]byte(content))
+	f := openTestFile(t, content)
+
+	vf := VerifyFile(f, sum[:], sha256.New())
+	data, err := io.ReadAll(vf)
+	if err != nil || string(data) != content {
+		t.Errorf("got %q, %v", data, err)
+	}
+
+	// rewinding resets the digest
+	if _, err := vf.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(vf); err != nil {
+		t.Errorf("second read: %v", err)
+	}
+
+	bad := sha256.Sum256([]byte("something else"))
+	vf = VerifyFile(f, bad[:], sha256.New())
+	if _, err := vf.Seek(0, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Tell(vf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(vf); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("got %v, expected %v", err, ErrChecksumMismatch)
+	}
+
+	// a partial read can't be verified
+	vf = VerifyFile(f, bad[:], sha256.New())
+	if _, err := vf.Seek(4, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := io.ReadAll(vf); err != nil {
+		t.Errorf("got %v after seeking, expected verification to be disabled", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	content := "the quick brown fox"
+	sum := sha256.Sum256([]byte(content))
+	f := openTestFile(t, content)
+	if _, err := f.Seek(5, io.SeekStart); err != nil {
+		t.Fatal(err)
+	}
+
+	h := sha256.New()
+	h.Write([]byte("stale"))
+	if err := Verify(f, sum[:], h); err != nil {
+		t.Error(err)
+	}
+	if pos, err := Tell(f); pos != 5 || err != nil {
+		t.Errorf("offset moved to %d, %v", pos, err)
+	}
+	if err := Verify(f, []byte("wrong"), h); !errors.Is(err, ErrChecksumMismatch) {
+		t.Errorf("got %v, expected %v", err, ErrChecksumMismatch)
+	}
+}
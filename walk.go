@@ -0,0 +1,149 @@
+package absfs
+
+import (
+	iofs "io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// SkipDir - can be returned from a walk function to skip the directory being
+// visited. Returned while visiting a file, it skips the remaining entries in
+// the file's directory. It is the same value as `fs.SkipDir` and
+// `filepath.SkipDir`, so walkers built on absfs share one convention.
+var SkipDir = iofs.SkipDir
+
+// SkipAll - can be returned from a walk function to stop the walk
+// immediately; the walk itself then returns nil. It is the same value as
+// `fs.SkipAll` and `filepath.SkipAll`.
+var SkipAll = iofs.SkipAll
+
+// Walk - walks the file tree rooted at `root` in `fsys`, calling `fn` for each
+// file or directory in the tree, including `root`. It behaves like
+// `filepath.Walk`: files are visited in lexical order, `fn` is called with
+// any error encountered reading a directory, returning `SkipDir` from `fn`
+// skips the remainder of a directory, and returning `SkipAll` ends the walk. If `fsys`
+// implements `SymLinker` entries are examined with `Lstat`, so symbolic links
+// are reported but not followed. Paths are joined with the separator of
+// `fsys`.
+func Walk(fsys FileSystem, root string, fn filepath.WalkFunc) error {
+	info, err := Lstat(fsys, root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walk(fsys, root, info, fn)
+	}
+	if err == SkipDir || err == SkipAll {
+		return nil
+	}
+	return err
+}
+
+func walk(fsys FileSystem, path string, info os.FileInfo, fn filepath.WalkFunc) error {
+	if !info.IsDir() {
+		return fn(path, info, nil)
+	}
+
+	names, err := readDirNames(fsys, path)
+	err1 := fn(path, info, err)
+	if err != nil || err1 != nil {
+		return err1
+	}
+
+	for _, name := range names {
+		filename := Join(fsys, path, name)
+		fileInfo, err := Lstat(fsys, filename)
+		if err != nil {
+			if err := fn(filename, fileInfo, err); err != nil && err != SkipDir {
+				return err
+			}
+			continue
+		}
+		err = walk(fsys, filename, fileInfo, fn)
+		if err != nil {
+			if !fileInfo.IsDir() || err != SkipDir {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// WalkDir - walks the file tree rooted at `root` in `fsys`, calling `fn` for
+// each file or directory in the tree, including `root`. It behaves like
+// `fs.WalkDir`: files are visited in lexical order and `fn` is passed an
+// `os.DirEntry` rather than an `os.FileInfo`. Entries come from reading the
+// directory with `FileReadDir`, so unlike `Walk` no `Stat` call is made per
+// entry; only `root` is examined, with `Lstat`. Returning `SkipDir`
+// from `fn` skips a directory and returning `SkipAll` ends the walk. Paths are
+// joined with the separator of `fsys`.
+func WalkDir(fsys FileSystem, root string, fn iofs.WalkDirFunc) error {
+	info, err := Lstat(fsys, root)
+	if err != nil {
+		err = fn(root, nil, err)
+	} else {
+		err = walkDir(fsys, root, FileInfoDirEntry(info), fn)
+	}
+	if err == SkipDir || err == SkipAll {
+		return nil
+	}
+	return err
+}
+
+func walkDir(fsys FileSystem, path string, d os.DirEntry, fn iofs.WalkDirFunc) error {
+	if err := fn(path, d, nil); err != nil || !d.IsDir() {
+		if err == SkipDir && d.IsDir() {
+			err = nil
+		}
+		return err
+	}
+
+	entries, err := readDirEntries(fsys, path)
+	if err != nil {
+		err = fn(path, d, err)
+		if err != nil {
+			if err == SkipDir && d.IsDir() {
+				err = nil
+			}
+			return err
+		}
+	}
+
+	for _, entry := range entries {
+		name := Join(fsys, path, entry.Name())
+		if err := walkDir(fsys, name, entry, fn); err != nil {
+			if err == SkipDir {
+				break
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// readDirEntries - returns the entries in directory `dir` sorted by name.
+func readDirEntries(fsys FileSystem, dir string) ([]os.DirEntry, error) {
+	f, err := fsys.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := FileReadDir(f, -1)
+	f.Close()
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name() < entries[j].Name() })
+	return entries, err
+}
+
+// readDirNames - returns the sorted names of the entries in directory `dir`.
+func readDirNames(fsys FileSystem, dir string) ([]string, error) {
+	f, err := fsys.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+	names, err := ReaddirnamesAll(f)
+	f.Close()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(names)
+	return names, nil
+}
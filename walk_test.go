@@ -0,0 +1,176 @@
+package absfs
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestWalk(t *testing.T) {
+	fsys := newTestFS()
+	for _, dir := range []string{"/a/b", "/a/c", "/d"} {
+		if err := fsys.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	createFiles(t, fsys, "/a/b/f1", "/a/c/f2", "/d/f3")
+
+	var visited []string
+	err := Walk(fsys, "/", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		if path == "/a/c" {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"/", "/a", "/a/b", "/a/b/f1", "/a/c", "/d", "/d/f3"}
+	if !reflect.DeepEqual(visited, expected) {
+		t.Errorf("visited %v, expected %v", visited, expected)
+	}
+}
+
+func TestWalkSkip(t *testing.T) {
+	fsys := newTestFS()
+	for _, dir := range []string{"/a", "/b"} {
+		if err := fsys.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	createFiles(t, fsys, "/a/f1", "/a/f2", "/a/f3", "/b/f4")
+
+	tests := []struct {
+		stop     string
+		err      error
+		expected []string
+	}{
+		// SkipDir on a file skips the rest of its directory.
+		{"/a/f2", SkipDir, []string{"/", "/a", "/a/f1", "/a/f2", "/b", "/b/f4"}},
+		{"/a/f2", SkipAll, []string{"/", "/a", "/a/f1", "/a/f2"}},
+		{"/a", SkipAll, []string{"/", "/a"}},
+	}
+	for _, test := range tests {
+		var visited []string
+		err := Walk(fsys, "/", func(path string, info os.FileInfo, err error) error {
+			if err != nil {
+				return err
+			}
+			visited = append(visited, path)
+			if path == test.stop {
+				return test.err
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("%s at %s: %v", test.err, test.stop, err)
+		}
+		if !reflect.DeepEqual(visited, test.expected) {
+			t.Errorf("%s at %s: visited %v, expected %v", test.err, test.stop, visited, test.expected)
+		}
+	}
+
+	if SkipDir != filepath.SkipDir || SkipAll != filepath.SkipAll {
+		t.Error("SkipDir and SkipAll should match the standard library sentinels")
+	}
+}
+
+// statCountFS - counts calls to `Stat`.
+type statCountFS struct {
+	FileSystem
+	stats int
+}
+
+func (fs *statCountFS) Stat(name string) (os.FileInfo, error) {
+	fs.stats++
+	return fs.FileSystem.Stat(name)
+}
+
+func TestWalkDir(t *testing.T) {
+	fsys := &statCountFS{FileSystem: newTestFS()}
+	for _, dir := range []string{"/a/b", "/a/c", "/d"} {
+		if err := fsys.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	createFiles(t, fsys, "/a/b/f1", "/a/c/f2", "/d/f3")
+
+	fsys.stats = 0
+	var visited []string
+	err := WalkDir(fsys, "/", func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		if path == "/a/c" {
+			return SkipDir
+		}
+		if path == "/d/f3" && (d.IsDir() || d.Name() != "f3") {
+			t.Errorf("unexpected entry %v for %s", d, path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"/", "/a", "/a/b", "/a/b/f1", "/a/c", "/d", "/d/f3"}
+	if !reflect.DeepEqual(visited, expected) {
+		t.Errorf("visited %v, expected %v", visited, expected)
+	}
+	walkDirStats := fsys.stats
+
+	fsys.stats = 0
+	if err := Walk(fsys, "/", func(string, os.FileInfo, error) error { return nil }); err != nil {
+		t.Fatal(err)
+	}
+	if walkDirStats >= fsys.stats {
+		t.Errorf("WalkDir made %d Stat calls, expected fewer than Walk's %d", walkDirStats, fsys.stats)
+	}
+}
+
+func TestWalkSeparator(t *testing.T) {
+	m := newMockFiler()
+	fsys := ExtendFilerWithOptions(backslashFiler{m}, WithSeparator('\\'))
+	if err := fsys.MkdirAll(`\a\b`, 0755); err != nil {
+		t.Fatal(err)
+	}
+	createFiles(t, fsys, `\a\b\f`)
+
+	var visited []string
+	err := Walk(fsys, `\a`, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{`\a`, `\a\b`, `\a\b\f`}
+	if !reflect.DeepEqual(visited, expected) {
+		t.Errorf("visited %v, expected %v", visited, expected)
+	}
+
+	visited = nil
+	err = WalkDir(fsys, `\a`, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		visited = append(visited, path)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(visited, expected) {
+		t.Errorf("WalkDir visited %v, expected %v", visited, expected)
+	}
+}
@@ -0,0 +1,77 @@
+package absfs
+
+import (
+	"os"
+	"strings"
+)
+
+// FsOp - is a bitmask of the kinds of change described by an `FsEvent`.
+type FsOp uint32
+
+const (
+	OpCreate FsOp = 1 << iota // a file or directory was created.
+	OpWrite                   // the contents of a file changed.
+	OpRemove                  // a file or directory was removed.
+	OpRename                  // a file or directory was renamed away from the path.
+	OpChmod                   // the metadata of a file or directory changed.
+)
+
+// String - returns the names of the bits set in `op` separated by "|", e.g.
+// "CREATE|WRITE".
+func (op FsOp) String() string {
+	var names []string
+	for i, name := range fsOpNames {
+		if op&(1<<i) != 0 {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return "0"
+	}
+	return strings.Join(names, "|")
+}
+
+var fsOpNames = [...]string{"CREATE", "WRITE", "REMOVE", "RENAME", "CHMOD"}
+
+// FsEvent - describes a change to the file or directory `Name`.
+type FsEvent struct {
+	Name string
+	Op   FsOp
+}
+
+func (e FsEvent) String() string {
+	return e.Op.String() + " " + e.Name
+}
+
+// Watcher - is implemented by filesystems that can report changes.
+//
+// `Watch` returns a channel of events for `name` and a function that stops
+// watching. Watching a file reports changes to that file. Watching a
+// directory reports changes to the directory itself and to its direct
+// children, but not to deeper descendants; the `Name` of an event is the path
+// of the entry that changed. A rename is reported as `OpRename` for the old
+// name and `OpCreate` for the new name, when both are watched.
+//
+// Implementations may coalesce several changes to the same path that happen
+// close together into one event with more than one `FsOp` bit set, and may
+// drop events if the receiver falls behind, so events are hints to rescan
+// rather than an exact log. The channel is closed once watching stops.
+type Watcher interface {
+	Watch(name string) (<-chan FsEvent, func() error, error)
+}
+
+// Watch - starts watching `name` for changes if `fsys` implements `Watcher`,
+// otherwise it returns an `*os.PathError` wrapping `ErrNotImplemented`. The
+// returned cancel function may be called more than once; only the first call
+// has any effect and later calls return nil.
+func Watch(fsys FileSystem, name string) (<-chan FsEvent, func() error, error) {
+	w, ok := fsys.(Watcher)
+	if !ok {
+		return nil, nil, &os.PathError{Op: "watch", Path: name, Err: ErrNotImplemented}
+	}
+	events, cancel, err := w.Watch(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	return events, onceFunc(cancel), nil
+}
@@ -0,0 +1,85 @@
+package absfs
+
+import (
+	"errors"
+	"testing"
+)
+
+// watchFiler - returns a buffered channel per `Watch` call and counts
+// cancellations.
+type watchFiler struct {
+	*mockFiler
+	events  chan FsEvent
+	name    string
+	cancels int
+}
+
+func (m *watchFiler) Watch(name string) (<-chan FsEvent, func() error, error) {
+	m.name = name
+	m.events = make(chan FsEvent, 1)
+	return m.events, func() error {
+		m.cancels++
+		close(m.events)
+		return nil
+	}, nil
+}
+
+func TestWatch(t *testing.T) {
+	if _, _, err := Watch(newTestFS(), "/"); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("got %v, expected %v", err, ErrNotImplemented)
+	}
+
+	m := &watchFiler{mockFiler: newMockFiler()}
+	events, cancel, err := Watch(ExtendFiler(m), "dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.name != "/dir" {
+		t.Errorf("got %q, expected %q", m.name, "/dir")
+	}
+
+	m.events <- FsEvent{Name: "/dir/file", Op: OpCreate | OpWrite}
+	if e := <-events; e.String() != "CREATE|WRITE /dir/file" {
+		t.Errorf("got %q", e)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := cancel(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if m.cancels != 1 {
+		t.Errorf("got %d cancellations, expected 1", m.cancels)
+	}
+	if _, ok := <-events; ok {
+		t.Error("expected the channel to be closed")
+	}
+
+	// the cancel function returned by the FileSystem itself is idempotent too
+	_, cancel, err = ExtendFiler(m).(Watcher).Watch("dir")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.cancels = 0
+	for i := 0; i < 3; i++ {
+		if err := cancel(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if m.cancels != 1 {
+		t.Errorf("got %d cancellations, expected 1", m.cancels)
+	}
+}
+
+func TestFsOpString(t *testing.T) {
+	tests := map[FsOp]string{
+		0:                             "0",
+		OpRemove:                      "REMOVE",
+		OpCreate | OpRename | OpChmod: "CREATE|RENAME|CHMOD",
+	}
+	for op, expected := range tests {
+		if op.String() != expected {
+			t.Errorf("got %q, expected %q", op.String(), expected)
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package absfs
+
+import (
+	"os"
+	"time"
+)
+
+// WrapErrors - returns a FileSystem that normalizes the errors returned by
+// `fsys`, so that every method returns either nil or an `*os.PathError`, and
+// `Rename` returns either nil or an `*os.LinkError`. Errors that are already
+// of the expected type are returned unchanged. Any other error is wrapped
+// with the name of the operation and the path it was given, made absolute
+// against the working directory of `fsys`, so callers can always use
+// `errors.As` to find the path and `errors.Is` to test the cause. A failed
+// open always returns an `*InvalidFile` rather than a nil File.
+//
+// Only the methods of the FileSystem are normalized; errors from the returned
+// File handles, such as `io.EOF` from `Read`, are passed through as is.
+func WrapErrors(fsys FileSystem) FileSystem {
+	return &errfs{fsys}
+}
+
+type errfs struct {
+	FileSystem
+}
+
+// pathError - normalizes a non-nil `err` to an `*os.PathError` for `op` on
+// `name`.
+func (fs *errfs) pathError(op, name string, err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*os.PathError); ok {
+		return err
+	}
+	if p, aerr := Abs(fs.FileSystem, name); aerr == nil {
+		name = p
+	}
+	return &os.PathError{Op: op, Path: name, Err: err}
+}
+
+func (fs *errfs) open(name string, f File, err error) (File, error) {
+	if err == nil {
+		return f, nil
+	}
+	err = fs.pathError("open", name, err)
+	if f == nil {
+		f = &InvalidFile{Path: name, Err: err}
+	}
+	return f, err
+}
+
+func (fs *errfs) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	f, err := fs.FileSystem.OpenFile(name, flag, perm)
+	return fs.open(name, f, err)
+}
+
+func (fs *errfs) Open(name string) (File, error) {
+	f, err := fs.FileSystem.Open(name)
+	return fs.open(name, f, err)
+}
+
+func (fs *errfs) Create(name string) (File, error) {
+	f, err := fs.FileSystem.Create(name)
+	return fs.open(name, f, err)
+}
+
+func (fs *errfs) Mkdir(name string, perm os.FileMode) error {
+	return fs.pathError("mkdir", name, fs.FileSystem.Mkdir(name, perm))
+}
+
+func (fs *errfs) MkdirAll(name string, perm os.FileMode) error {
+	return fs.pathError("mkdir", name, fs.FileSystem.MkdirAll(name, perm))
+}
+
+func (fs *errfs) Remove(name string) error {
+	return fs.pathError("remove", name, fs.FileSystem.Remove(name))
+}
+
+func (fs *errfs) RemoveAll(name string) error {
+	return fs.pathError("removeall", name, fs.FileSystem.RemoveAll(name))
+}
+
+func (fs *errfs) Rename(oldpath, newpath string) error {
+	err := fs.FileSystem.Rename(oldpath, newpath)
+	if err == nil {
+		return nil
+	}
+	if _, ok := err.(*os.LinkError); !ok {
+		if p, aerr := Abs(fs.FileSystem, oldpath); aerr == nil {
+			oldpath = p
+		}
+		if p, aerr := Abs(fs.FileSystem, newpath); aerr == nil {
+			newpath = p
+		}
+	}
+	return linkError("rename", oldpath, newpath, err)
+}
+
+func (fs *errfs) Stat(name string) (os.FileInfo, error) {
+	info, err := fs.FileSystem.Stat(name)
+	if err != nil {
+		return nil, fs.pathError("stat", name, err)
+	}
+	return info, nil
+}
+
+func (fs *errfs) Chmod(name string, mode os.FileMode) error {
+	return fs.pathError("chmod", name, fs.FileSystem.Chmod(name, mode))
+}
+
+func (fs *errfs) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	return fs.pathError("chtimes", name, fs.FileSystem.Chtimes(name, atime, mtime))
+}
+
+func (fs *errfs) Chown(name string, uid, gid int) error {
+	return fs.pathError("chown", name, fs.FileSystem.Chown(name, uid, gid))
+}
+
+func (fs *errfs) Truncate(name string, size int64) error {
+	return fs.pathError("truncate", name, fs.FileSystem.Truncate(name, size))
+}
+
+func (fs *errfs) Chdir(dir string) error {
+	return fs.pathError("chdir", dir, fs.FileSystem.Chdir(dir))
+}
+
+func (fs *errfs) Getwd() (string, error) {
+	dir, err := fs.FileSystem.Getwd()
+	if err != nil {
+		if _, ok := err.(*os.PathError); !ok {
+			err = &os.PathError{Op: "getwd", Path: ".", Err: err}
+		}
+		return "", err
+	}
+	return dir, nil
+}
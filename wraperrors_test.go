@@ -0,0 +1,94 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+	"testing"
+	"time"
+)
+
+// bareErrFS - returns `errSloppy` from every method except `Getwd`.
+type bareErrFS struct {
+	FileSystem
+}
+
+func (fs bareErrFS) OpenFile(name string, flag int, perm os.FileMode) (File, error) {
+	return nil, errSloppy
+}
+func (fs bareErrFS) Open(name string) (File, error)               { return nil, errSloppy }
+func (fs bareErrFS) Create(name string) (File, error)             { return nil, errSloppy }
+func (fs bareErrFS) Mkdir(name string, perm os.FileMode) error    { return errSloppy }
+func (fs bareErrFS) MkdirAll(name string, perm os.FileMode) error { return errSloppy }
+func (fs bareErrFS) Remove(name string) error                     { return errSloppy }
+func (fs bareErrFS) RemoveAll(name string) error                  { return errSloppy }
+func (fs bareErrFS) Rename(oldpath, newpath string) error         { return errSloppy }
+func (fs bareErrFS) Stat(name string) (os.FileInfo, error)        { return nil, errSloppy }
+func (fs bareErrFS) Chmod(name string, mode os.FileMode) error    { return errSloppy }
+func (fs bareErrFS) Chown(name string, uid, gid int) error        { return errSloppy }
+func (fs bareErrFS) Truncate(name string, size int64) error       { return errSloppy }
+func (fs bareErrFS) Chdir(dir string) error                       { return errSloppy }
+func (fs bareErrFS) Chtimes(name string, atime time.Time, mtime time.Time) error {
+	return errSloppy
+}
+
+func TestWrapErrors(t *testing.T) {
+	inner := newTestFS()
+	if err := inner.Mkdir("/dir", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := inner.Chdir("/dir"); err != nil {
+		t.Fatal(err)
+	}
+	fsys := WrapErrors(bareErrFS{inner})
+
+	t0 := time.Now()
+	tests := []struct {
+		op  string
+		err error
+	}{
+		{"open", func() error { _, err := fsys.OpenFile("f", os.O_RDONLY, 0); return err }()},
+		{"open", func() error { _, err := fsys.Open("f"); return err }()},
+		{"open", func() error { _, err := fsys.Create("f"); return err }()},
+		{"mkdir", fsys.Mkdir("f", 0755)},
+		{"mkdir", fsys.MkdirAll("f", 0755)},
+		{"remove", fsys.Remove("f")},
+		{"removeall", fsys.RemoveAll("f")},
+		{"stat", func() error { _, err := fsys.Stat("f"); return err }()},
+		{"chmod", fsys.Chmod("f", 0644)},
+		{"chtimes", fsys.Chtimes("f", t0, t0)},
+		{"chown", fsys.Chown("f", 0, 0)},
+		{"truncate", fsys.Truncate("f", 0)},
+		{"chdir", fsys.Chdir("f")},
+	}
+	for _, test := range tests {
+		perr, ok := test.err.(*os.PathError)
+		if !ok {
+			t.Errorf("%s: got %#v, expected an *os.PathError", test.op, test.err)
+			continue
+		}
+		if perr.Op != test.op || perr.Path != "/dir/f" || !errors.Is(perr, errSloppy) {
+			t.Errorf("got %#v, expected op %q on /dir/f wrapping %v", perr, test.op, errSloppy)
+		}
+	}
+
+	f, err := fsys.Open("f")
+	if _, ok := f.(*InvalidFile); !ok || err == nil {
+		t.Errorf("got %T, expected an *InvalidFile", f)
+	}
+
+	err = fsys.Rename("a", "/b")
+	lerr, ok := err.(*os.LinkError)
+	if !ok || lerr.Op != "rename" || lerr.Old != "/dir/a" || lerr.New != "/b" || !errors.Is(err, errSloppy) {
+		t.Errorf("got %#v, expected an *os.LinkError", err)
+	}
+
+	// well formed errors are unchanged
+	fsys = WrapErrors(inner)
+	_, err = fsys.Stat("/missing")
+	if perr, ok := err.(*os.PathError); !ok || perr.Path != "/missing" || !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("got %#v", err)
+	}
+	if _, err := fsys.Stat("/dir"); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,71 @@
+package absfs
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrNoAttribute - is returned when an extended attribute does not exist.
+var ErrNoAttribute = errors.New("attribute not found")
+
+// XattrFiler - is implemented by filesystems that support extended
+// attributes.
+//
+// Attribute values are opaque byte slices. `Setxattr` must not retain
+// `value` after it returns, and the slice returned by `Getxattr` belongs to
+// the caller, so neither side can observe later modifications made by the
+// other. An empty value is distinct from a missing attribute: `Getxattr` and
+// `Removexattr` report a missing attribute with an `*os.PathError` wrapping
+// `ErrNoAttribute`.
+type XattrFiler interface {
+	// Getxattr returns the value of the attribute `attr` of the named file.
+	Getxattr(name, attr string) ([]byte, error)
+
+	// Setxattr creates or replaces the attribute `attr` of the named file.
+	Setxattr(name, attr string, value []byte) error
+
+	// Listxattr returns the names of the attributes of the named file.
+	Listxattr(name string) ([]string, error)
+
+	// Removexattr removes the attribute `attr` of the named file.
+	Removexattr(name, attr string) error
+}
+
+// Getxattr - returns the value of the attribute `attr` of `name` if `fsys`
+// implements `XattrFiler`, otherwise an `*os.PathError` wrapping
+// `ErrNotImplemented`.
+func Getxattr(fsys FileSystem, name, attr string) ([]byte, error) {
+	if x, ok := fsys.(XattrFiler); ok {
+		return x.Getxattr(name, attr)
+	}
+	return nil, &os.PathError{Op: "getxattr", Path: name, Err: ErrNotImplemented}
+}
+
+// Setxattr - sets the attribute `attr` of `name` to `value` if `fsys`
+// implements `XattrFiler`, otherwise it returns an `*os.PathError` wrapping
+// `ErrNotImplemented`.
+func Setxattr(fsys FileSystem, name, attr string, value []byte) error {
+	if x, ok := fsys.(XattrFiler); ok {
+		return x.Setxattr(name, attr, value)
+	}
+	return &os.PathError{Op: "setxattr", Path: name, Err: ErrNotImplemented}
+}
+
+// Listxattr - returns the attribute names of `name` if `fsys` implements
+// `XattrFiler`, otherwise an `*os.PathError` wrapping `ErrNotImplemented`.
+func Listxattr(fsys FileSystem, name string) ([]string, error) {
+	if x, ok := fsys.(XattrFiler); ok {
+		return x.Listxattr(name)
+	}
+	return nil, &os.PathError{Op: "listxattr", Path: name, Err: ErrNotImplemented}
+}
+
+// Removexattr - removes the attribute `attr` of `name` if `fsys` implements
+// `XattrFiler`, otherwise it returns an `*os.PathError` wrapping
+// `ErrNotImplemented`.
+func Removexattr(fsys FileSystem, name, attr string) error {
+	if x, ok := fsys.(XattrFiler); ok {
+		return x.Removexattr(name, attr)
+	}
+	return &os.PathError{Op: "removexattr", Path: name, Err: ErrNotImplemented}
+}
@@ -0,0 +1,89 @@
+package absfs
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// xattrFiler - stores extended attributes in memory.
+type xattrFiler struct {
+	*mockFiler
+	attrs map[string]map[string][]byte
+}
+
+func (m *xattrFiler) Getxattr(name, attr string) ([]byte, error) {
+	v, ok := m.attrs[name][attr]
+	if !ok {
+		return nil, &os.PathError{Op: "getxattr", Path: name, Err: ErrNoAttribute}
+	}
+	return append([]byte(nil), v...), nil
+}
+
+func (m *xattrFiler) Setxattr(name, attr string, value []byte) error {
+	if m.attrs[name] == nil {
+		m.attrs[name] = make(map[string][]byte)
+	}
+	m.attrs[name][attr] = append([]byte{}, value...)
+	return nil
+}
+
+func (m *xattrFiler) Listxattr(name string) ([]string, error) {
+	var names []string
+	for attr := range m.attrs[name] {
+		names = append(names, attr)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (m *xattrFiler) Removexattr(name, attr string) error {
+	if _, ok := m.attrs[name][attr]; !ok {
+		return &os.PathError{Op: "removexattr", Path: name, Err: ErrNoAttribute}
+	}
+	delete(m.attrs[name], attr)
+	return nil
+}
+
+func TestXattr(t *testing.T) {
+	fsys := newTestFS()
+	if _, err := Getxattr(fsys, "/f", "user.a"); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("Getxattr: got %v, expected %v", err, ErrNotImplemented)
+	}
+	if err := Setxattr(fsys, "/f", "user.a", nil); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("Setxattr: got %v, expected %v", err, ErrNotImplemented)
+	}
+	if _, err := Listxattr(fsys, "/f"); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("Listxattr: got %v, expected %v", err, ErrNotImplemented)
+	}
+	if err := Removexattr(fsys, "/f", "user.a"); !errors.Is(err, ErrNotImplemented) {
+		t.Errorf("Removexattr: got %v, expected %v", err, ErrNotImplemented)
+	}
+
+	fsys = ExtendFiler(&xattrFiler{newMockFiler(), make(map[string]map[string][]byte)})
+	value := []byte("value")
+	if err := Setxattr(fsys, "f", "user.a", value); err != nil {
+		t.Fatal(err)
+	}
+	if err := Setxattr(fsys, "/f", "user.b", []byte{}); err != nil {
+		t.Fatal(err)
+	}
+	value[0] = 'X'
+	got, err := Getxattr(fsys, "/f", "user.a")
+	if err != nil || !bytes.Equal(got, []byte("value")) {
+		t.Errorf("Getxattr: got %q, %v", got, err)
+	}
+	names, err := Listxattr(fsys, "/f")
+	if err != nil || !reflect.DeepEqual(names, []string{"user.a", "user.b"}) {
+		t.Errorf("Listxattr: got %v, %v", names, err)
+	}
+	if err := Removexattr(fsys, "/f", "user.a"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Getxattr(fsys, "/f", "user.a"); !errors.Is(err, ErrNoAttribute) {
+		t.Errorf("got %v, expected %v", err, ErrNoAttribute)
+	}
+}